@@ -15,11 +15,18 @@ import (
 // CachableFn is a function whose output can safely be cached
 type CachableFn func(context.Context) (interface{}, error)
 
+// ErrorCodeFn returns the http status code to respond to an error returned by
+// a CachableFn with, or 0 if it should be hidden behind ErrCacheableFnFailed
+type ErrorCodeFn func(error) int
+
 // Cachable is a keyed CachableFn
 type Cachable struct {
 	Key        []string
 	CachableFn CachableFn
 	TTL        time.Duration
+
+	// ErrorCodeFn, if set, lets errors the CachableFn returns be written as is
+	ErrorCodeFn ErrorCodeFn
 }
 
 type cacher interface {
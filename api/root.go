@@ -52,6 +52,11 @@ func (c *RootRequestContext) Ctx() context.Context {
 	return c.ctx
 }
 
+// Job returns the health.Job this request is reported as
+func (c *RootRequestContext) Job() *health.Job {
+	return c.job
+}
+
 // NetworkID returns the networkID this request is for
 func (c *RootRequestContext) NetworkID() uint32 {
 	return c.networkID
@@ -73,6 +78,12 @@ func (c *RootRequestContext) WriteCacheable(w http.ResponseWriter, cachable Cach
 
 	// Write error or response
 	if err != nil {
+		if cachable.ErrorCodeFn != nil {
+			if code := cachable.ErrorCodeFn(err); code != 0 {
+				c.WriteErr(w, code, err)
+				return
+			}
+		}
 		c.WriteErr(w, 500, ErrCacheableFnFailed)
 		return
 	}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/ava-labs/ortelius/cfg"
 	"github.com/ava-labs/ortelius/services/indexes/models"
+	"github.com/ava-labs/ortelius/services/indexes/params"
 )
 
 var (
@@ -40,6 +41,9 @@ func NewServer(conf cfg.Config) (*Server, error) {
 	// Set address prefix to use the configured network
 	models.SetBech32HRP(conf.NetworkID)
 
+	// Set the page size of requests that don't set a limit
+	params.SetDefaultLimit(conf.DefaultLimit)
+
 	return &Server{
 		log: log,
 		server: &http.Server{
@@ -47,6 +47,10 @@ type Services struct {
 
 type API struct {
 	ListenAddr string `json:"listenAddr"`
+
+	// DefaultLimit is the page size of list endpoints when the request doesn't
+	// set a limit. Zero uses the built-in default.
+	DefaultLimit int `json:"defaultLimit"`
 }
 
 type DB struct {
@@ -84,6 +88,10 @@ type Producer struct {
 type Consumer struct {
 	StartTime time.Time `json:"startTime"`
 	GroupName string    `json:"groupName"`
+
+	// MetricsListenAddr is the address to serve Prometheus metrics on. Metrics
+	// aren't served if it's empty.
+	MetricsListenAddr string `json:"metricsListenAddr"`
 }
 
 // NewFromFile creates a new *Config with the defaults replaced by the config  in
@@ -126,7 +134,8 @@ func NewFromFile(filePath string) (*Config, error) {
 		Services: Services{
 			Logging: loggingConf,
 			API: API{
-				ListenAddr: servicesAPIViper.GetString(keysServicesAPIListenAddr),
+				ListenAddr:   servicesAPIViper.GetString(keysServicesAPIListenAddr),
+				DefaultLimit: servicesAPIViper.GetInt(keysServicesAPIDefaultLimit),
 			},
 			DB: &DB{
 				Driver: servicesDBViper.GetString(keysServicesDBDriver),
@@ -153,6 +162,8 @@ func NewFromFile(filePath string) (*Config, error) {
 			Consumer: Consumer{
 				StartTime: streamConsumerViper.GetTime(keysStreamConsumerStartTime),
 				GroupName: streamConsumerViper.GetString(keysStreamConsumerGroupName),
+
+				MetricsListenAddr: streamConsumerViper.GetString(keysStreamConsumerMetricsListenAddr),
 			},
 		},
 	}, nil
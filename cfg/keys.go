@@ -14,8 +14,9 @@ const (
 
 	keysServices = "services"
 
-	keysServicesAPI           = "api"
-	keysServicesAPIListenAddr = "listenAddr"
+	keysServicesAPI             = "api"
+	keysServicesAPIListenAddr   = "listenAddr"
+	keysServicesAPIDefaultLimit = "defaultLimit"
 
 	keysServicesDB       = "db"
 	keysServicesDBDriver = "driver"
@@ -35,9 +36,10 @@ const (
 	keysStreamProducer        = "producer"
 	keysStreamProducerIPCRoot = "ipcRoot"
 
-	keysStreamConsumer          = "consumer"
-	keysStreamConsumerGroupName = "groupName"
-	keysStreamConsumerStartTime = "startTime"
+	keysStreamConsumer                  = "consumer"
+	keysStreamConsumerGroupName         = "groupName"
+	keysStreamConsumerStartTime         = "startTime"
+	keysStreamConsumerMetricsListenAddr = "metricsListenAddr"
 
 	keysStreamFilter    = "filter"
 	keysStreamFilterMin = "min"
@@ -7,11 +7,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
 
 	"github.com/ava-labs/ortelius/api"
@@ -147,7 +150,12 @@ func createStreamCmds(config *cfg.Config, runErr *error) *cobra.Command {
 		Use:   streamIndexerCmdUse,
 		Short: streamIndexerCmdDesc,
 		Long:  streamIndexerCmdDesc,
-		Run:   runStreamProcessorManagers(config, runErr, consumers.Indexer),
+		Run: func(cmd *cobra.Command, args []string) {
+			if addr := config.Consumer.MetricsListenAddr; addr != "" {
+				go serveMetrics(addr)
+			}
+			runStreamProcessorManagers(config, runErr, consumers.NewIndexer(stream.WithMetrics(prometheus.DefaultRegisterer)))(cmd, args)
+		},
 	})
 
 	return streamCmd
@@ -190,6 +198,16 @@ func runListenCloser(lc listenCloser) {
 	}
 }
 
+// serveMetrics serves the metrics of the default Prometheus registry at
+// /metrics on the given address
+func serveMetrics(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Println("Metrics server error:", err.Error())
+	}
+}
+
 // runStreamProcessorManagers returns a cobra command that instantiates and runs
 // a set of stream process managers
 func runStreamProcessorManagers(config *cfg.Config, runErr *error, factories ...stream.ProcessorFactory) func(_ *cobra.Command, _ []string) {
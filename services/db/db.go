@@ -61,19 +61,25 @@ func newDBRConnection(stream *health.Stream, conf cfg.DB) (*dbr.Connection, erro
 		dbrDialect dbr.Dialect = dialect.PostgreSQL
 	)
 
-	// If we want a transactional db then register that driver instead
-	if conf.TXDB {
-		driver = driverTXDB
-		registerTxDB(conf)
-	}
-
-	// If we're using MySQL we need to ensure to set the parseTime option
+	// If we're using MySQL we need to ensure to set the parseTime option and
+	// to use UTC for the session
 	if conf.Driver == DriverMysql {
 		dbrDialect = dialect.MySQL
 		dsn, err = forceParseTimeParam(dsn)
 		if err != nil {
 			return nil, err
 		}
+		dsn, err = forceUTCTimeZoneParam(dsn)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// If we want a transactional db then register that driver instead
+	if conf.TXDB {
+		driver = driverTXDB
+		conf.DSN = dsn
+		registerTxDB(conf)
 	}
 
 	// Create the underlying connection and ping it to ensure liveness
@@ -1,8 +1,15 @@
 package db
 
 import (
+	"database/sql/driver"
+	"errors"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/palantir/stacktrace"
 
 	"github.com/ava-labs/ortelius/cfg"
 )
@@ -24,6 +31,21 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestForceUTCTimeZoneParam(t *testing.T) {
+	dsn, err := forceUTCTimeZoneParam("root:password@tcp(mysql:3306)/ortelius_dev?loc=Local&parseTime=true")
+	if err != nil {
+		t.Fatal("Failed to force UTC:", err.Error())
+	}
+
+	conf, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		t.Fatal("Failed to parse dsn:", err.Error())
+	}
+	if conf.Loc != time.UTC || conf.Params["time_zone"] != "'+00:00'" || !conf.ParseTime {
+		t.Fatal("Unexpected dsn:", dsn)
+	}
+}
+
 func TestNewErrors(t *testing.T) {
 	conn, err := New(nil, cfg.DB{
 		Driver: "mysql",
@@ -61,3 +83,25 @@ func TestNewErrors(t *testing.T) {
 		t.Fatal("Expected i/o or context deadline timeout")
 	}
 }
+
+func TestErrIsRetryable(t *testing.T) {
+	for _, test := range []struct {
+		err       error
+		retryable bool
+	}{
+		{nil, false},
+		{errors.New("failed"), false},
+		{&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}, false},
+		{&mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"}, false},
+		{&mysql.MySQLError{Number: 1213, Message: "Deadlock found"}, true},
+		{&mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}, true},
+		{stacktrace.Propagate(&mysql.MySQLError{Number: 1213}, "Failed to insert tx"), true},
+		{driver.ErrBadConn, true},
+		{mysql.ErrInvalidConn, true},
+		{syscall.ECONNRESET, true},
+	} {
+		if retryable := ErrIsRetryable(test.err); retryable != test.retryable {
+			t.Fatalf("Incorrect retryability of %v: %v", test.err, retryable)
+		}
+	}
+}
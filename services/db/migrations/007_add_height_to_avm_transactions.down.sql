@@ -0,0 +1,2 @@
+DROP INDEX avm_transactions_chain_id_height ON `avm_transactions`;
+ALTER TABLE `avm_transactions` DROP COLUMN `height`;
@@ -0,0 +1,2 @@
+ALTER TABLE `avm_transactions` ADD COLUMN `height` bigint unsigned not null default 0;
+CREATE INDEX avm_transactions_chain_id_height ON `avm_transactions` (chain_id, height);
@@ -4,9 +4,16 @@
 package db
 
 import (
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
+	"github.com/palantir/stacktrace"
 
 	"github.com/ava-labs/ortelius/cfg"
 )
@@ -28,6 +35,35 @@ func ErrIsDuplicateEntryError(err error) bool {
 	return err != nil && strings.HasPrefix(err.Error(), "Error 1062: Duplicate entry")
 }
 
+// MySQL errors after which retrying the same statements may succeed
+const (
+	mysqlErrLockWaitTimeout uint16 = 1205
+	mysqlErrLockDeadlock    uint16 = 1213
+)
+
+// ErrIsRetryable returns true if err was caused by a transient failure, such
+// as a deadlock or a dropped connection, instead of by the statements
+// themselves. Constraint violations and other errors aren't retryable.
+func ErrIsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	err = stacktrace.RootCause(err)
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErrLockDeadlock || mysqlErr.Number == mysqlErrLockWaitTimeout
+	}
+
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.As(err, &netErr)
+}
+
 func forceParseTimeParam(dsn string) (string, error) {
 	// Parse dsn into a url
 	u, err := mysql.ParseDSN(dsn)
@@ -43,3 +79,22 @@ func forceParseTimeParam(dsn string) (string, error) {
 	// Re-encode as a string
 	return u.FormatDSN(), nil
 }
+
+// forceUTCTimeZoneParam sets the session time zone, and the location times are
+// written and parsed in, to UTC. Timestamps are converted to and from the
+// session time zone, so without this the times written, read, and compared by
+// UNIX_TIMESTAMP would depend on the server's default time zone.
+func forceUTCTimeZoneParam(dsn string) (string, error) {
+	u, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return "", err
+	}
+
+	if u.Params == nil {
+		u.Params = make(map[string]string)
+	}
+	u.Params["time_zone"] = "'+00:00'"
+	u.Loc = time.UTC
+
+	return u.FormatDSN(), nil
+}
@@ -0,0 +1,47 @@
+// (c) 2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gocraft/health"
+
+	"github.com/ava-labs/ortelius/services"
+	"github.com/ava-labs/ortelius/services/cache"
+)
+
+// AggregatesGenerationPeriod is the length of the time buckets that writes are
+// tracked in for invalidating cached aggregates
+const AggregatesGenerationPeriod = 24 * time.Hour
+
+// AggregatesGenerationKey is the redis key of the counter incremented each time
+// outputs created in the AggregatesGenerationPeriod of ts are written to
+// chainID, or to any chain if chainID is empty. Cached aggregates of a time
+// range are valid while the counters of its periods and chains are unchanged.
+func AggregatesGenerationKey(chainID string, ts time.Time) string {
+	period := ts.UTC().Truncate(AggregatesGenerationPeriod).Unix()
+	return cache.KeyFromParts("avm", "aggregates_generation", chainID, strconv.FormatInt(period, 10))
+}
+
+// InvalidateAggregates increments the generations of the period of ts for the
+// chain and for all chains, so that Readers stop using the aggregates they
+// cached for time ranges including it. Writers call it after committing
+// outputs created at ts. Failing to isn't fatal since cached aggregates expire
+// anyway.
+func InvalidateAggregates(ctx context.Context, conns *services.Connections, job *health.Job, chainID string, ts time.Time) {
+	redisConn := conns.Redis()
+	if redisConn == nil {
+		return
+	}
+
+	pipe := redisConn.Pipeline()
+	pipe.Incr(ctx, AggregatesGenerationKey(chainID, ts))
+	pipe.Incr(ctx, AggregatesGenerationKey("", ts))
+	if _, err := pipe.Exec(ctx); err != nil {
+		_ = job.EventErr("invalidate_aggregates", err)
+	}
+}
@@ -108,6 +108,7 @@ func (w *Writer) InsertTransaction(ctx services.ConsumerCtx, txBytes []byte, uns
 		Pair("type", txType.String()).
 		Pair("memo", baseTx.Memo).
 		Pair("created_at", ctx.Time()).
+		Pair("height", ctx.Height()).
 		Pair("canonical_serialization", txBytes).
 		ExecContext(ctx.Ctx())
 	if err != nil && !db.ErrIsDuplicateEntryError(err) {
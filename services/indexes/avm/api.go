@@ -6,6 +6,7 @@ package avm
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/ava-labs/avalanchego/genesis"
@@ -19,6 +20,29 @@ import (
 
 const VMName = "avm"
 
+// validationErrors are the errors the Reader returns for requests that can't be
+// answered as given, which clients can fix by changing their parameters
+var validationErrors = []error{
+	ErrAggregateIntervalCountTooLarge,
+	ErrSearchQueryTooShort,
+	ErrAmbiguousAlias,
+	ErrUnknownChainAlias,
+	params.ErrUndefinedSort,
+	params.ErrInvalidCursor,
+	params.ErrCursorWithSort,
+	params.ErrMinHeightWithSort,
+	params.ErrMinBalanceWithoutAsset,
+	params.ErrSortByBalanceWithoutAsset,
+	params.ErrUndefinedOutputType,
+	params.ErrInvalidAmountRange,
+	params.ErrInvalidTimeRange,
+	params.ErrInvalidIntervalSize,
+	params.ErrIntervalSizeTooLarge,
+	params.ErrInvalidIntervalWindow,
+	params.ErrUndefinedIntervalAlignment,
+	params.ErrIntervalRangeNotAligned,
+}
+
 func init() {
 	api.RegisterRouter(VMName, NewAPIRouter, APIContext{})
 }
@@ -34,7 +58,11 @@ type APIContext struct {
 }
 
 func NewAPIRouter(params api.RouterParams) error {
-	reader := NewReader(params.Connections, params.ChainConfig.ID)
+	var readerOpts []ReaderOption
+	if params.ChainConfig.Alias != "" {
+		readerOpts = append(readerOpts, WithChainAliases(map[string]string{params.ChainConfig.Alias: params.ChainConfig.ID}))
+	}
+	reader := NewReader(params.Connections, params.ChainConfig.ID, readerOpts...)
 
 	_, avaxAssetID, err := genesis.Genesis(params.NetworkID)
 	if err != nil {
@@ -65,23 +93,57 @@ func NewAPIRouter(params api.RouterParams) error {
 		Get("/", func(c *APIContext, w web.ResponseWriter, _ *web.Request) {
 			api.WriteJSON(w, overviewBytes)
 		}).
+		Get("/health", (*APIContext).Health).
+		Get("/status", (*APIContext).IndexStatus).
 		Get("/search", (*APIContext).Search).
 		Get("/aggregates", (*APIContext).Aggregate).
+		Get("/aggregates/assets", (*APIContext).AggregateByAsset).
+		Get("/aggregates/addresses", (*APIContext).AggregateActiveAddresses).
 		Get("/transactions/aggregates", (*APIContext).Aggregate). // DEPRECATED
 
 		// List and Get routes
 		Get("/transactions", (*APIContext).ListTransactions).
 		Get("/transactions/:id", (*APIContext).GetTransaction).
 		Get("/assets", (*APIContext).ListAssets).
+		Get("/assets/recent", (*APIContext).ListRecentAssets).
 		Get("/assets/:id", (*APIContext).GetAsset).
 		Get("/addresses", (*APIContext).ListAddresses).
 		Get("/addresses/:id", (*APIContext).GetAddress).
+		Get("/addresses/:id/transactions", (*APIContext).ListAddressTransactions).
+		Get("/addresses/:id/balances", (*APIContext).GetAddressPortfolio).
+		Get("/addresses/:id/assets", (*APIContext).ListAddressAssets).
 		Get("/outputs", (*APIContext).ListOutputs).
 		Get("/outputs/:id", (*APIContext).GetOutput)
 
 	return nil
 }
 
+// Health responds with a 503 if the index's DB can't be queried. It's never
+// cached. The driver error is logged but only whether the DB was slow or
+// unavailable is responded with.
+func (c *APIContext) Health(w web.ResponseWriter, r *web.Request) {
+	if err := c.reader.Health(r.Context()); err != nil {
+		_ = c.Job().EventErr("health", err)
+		if errors.Is(err, ErrDBSlow) {
+			c.WriteErr(w, 503, ErrDBSlow)
+		} else {
+			c.WriteErr(w, 503, ErrDBUnavailable)
+		}
+		return
+	}
+	api.WriteJSON(w, []byte(`{"healthy":true}`))
+}
+
+func (c *APIContext) IndexStatus(w web.ResponseWriter, r *web.Request) {
+	c.WriteCacheable(w, api.Cachable{
+		TTL: 5 * time.Second,
+		Key: []string{"avm", c.chainID, "index_status"},
+		CachableFn: func(ctx context.Context) (interface{}, error) {
+			return c.reader.IndexStatus(ctx)
+		},
+	})
+}
+
 func (c *APIContext) Search(w web.ResponseWriter, r *web.Request) {
 	p := &params.SearchParams{}
 	if err := p.ForValues(r.URL.Query()); err != nil {
@@ -89,6 +151,12 @@ func (c *APIContext) Search(w web.ResponseWriter, r *web.Request) {
 		return
 	}
 
+	// Tell clients when they searched for a malformed address
+	if _, err := params.AddressFromString(p.Query); errors.Is(err, params.ErrInvalidAddress) {
+		c.WriteErr(w, 400, err)
+		return
+	}
+
 	c.WriteCacheable(w, api.Cachable{
 		Key: c.cacheKeyForParams("search", p),
 		CachableFn: func(ctx context.Context) (interface{}, error) {
@@ -103,6 +171,10 @@ func (c *APIContext) Aggregate(w web.ResponseWriter, r *web.Request) {
 		c.WriteErr(w, 400, err)
 		return
 	}
+	if err := validateAggregateParams(p); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
 
 	if len(p.ChainIDs) < 1 {
 		p.ChainIDs = []string{c.chainID}
@@ -116,12 +188,73 @@ func (c *APIContext) Aggregate(w web.ResponseWriter, r *web.Request) {
 	})
 }
 
+func (c *APIContext) AggregateByAsset(w web.ResponseWriter, r *web.Request) {
+	p := &params.AggregateParams{}
+	if err := p.ForValues(r.URL.Query()); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+	if err := validateAggregateParams(p); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+
+	if len(p.ChainIDs) < 1 {
+		p.ChainIDs = []string{c.chainID}
+	}
+
+	c.WriteCacheable(w, api.Cachable{
+		Key: c.cacheKeyForParams("aggregate_by_asset", p),
+		CachableFn: func(ctx context.Context) (interface{}, error) {
+			return c.reader.AggregateByAsset(ctx, p)
+		},
+	})
+}
+
+func (c *APIContext) AggregateActiveAddresses(w web.ResponseWriter, r *web.Request) {
+	p := &params.AggregateParams{}
+	if err := p.ForValues(r.URL.Query()); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+	if err := validateAggregateParams(p); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+
+	if len(p.ChainIDs) < 1 {
+		p.ChainIDs = []string{c.chainID}
+	}
+
+	c.WriteCacheable(w, api.Cachable{
+		Key: c.cacheKeyForParams("aggregate_active_addresses", p),
+		CachableFn: func(ctx context.Context) (interface{}, error) {
+			return c.reader.AggregateActiveAddresses(ctx, p)
+		},
+	})
+}
+
+// validateAggregateParams rejects aggregate requests that can't produce a
+// histogram before they reach the reader. Without a startTime the range starts
+// at the first transaction, which only the reader knows, so it validates those
+// requests itself once the range is resolved.
+func validateAggregateParams(p *params.AggregateParams) error {
+	if p.StartTime.IsZero() {
+		return nil
+	}
+	return p.Validate()
+}
+
 func (c *APIContext) ListTransactions(w web.ResponseWriter, r *web.Request) {
 	p := &params.ListTransactionsParams{}
 	if err := p.ForValues(r.URL.Query()); err != nil {
 		c.WriteErr(w, 400, err)
 		return
 	}
+	if err := p.Validate(); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
 
 	if len(p.ChainIDs) < 1 {
 		p.ChainIDs = []string{c.chainID}
@@ -147,7 +280,7 @@ func (c *APIContext) GetTransaction(w web.ResponseWriter, r *web.Request) {
 		TTL: 5 * time.Second,
 		Key: c.cacheKeyForID("get_transaction", r.PathParams["id"]),
 		CachableFn: func(ctx context.Context) (interface{}, error) {
-			return c.reader.GetTransaction(ctx, id)
+			return nilIfNotFound(c.reader.GetTransaction(ctx, id))
 		},
 	})
 }
@@ -158,6 +291,10 @@ func (c *APIContext) ListAssets(w web.ResponseWriter, r *web.Request) {
 		c.WriteErr(w, 400, err)
 		return
 	}
+	if err := p.Validate(); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
 	c.WriteCacheable(w, api.Cachable{
 		Key: c.cacheKeyForParams("list_assets", p),
 		CachableFn: func(ctx context.Context) (interface{}, error) {
@@ -166,12 +303,28 @@ func (c *APIContext) ListAssets(w web.ResponseWriter, r *web.Request) {
 	})
 }
 
+func (c *APIContext) ListRecentAssets(w web.ResponseWriter, r *web.Request) {
+	limit, err := params.GetQueryInt(r.URL.Query(), params.KeyLimit, MaxRecentAssets)
+	if err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+
+	c.WriteCacheable(w, api.Cachable{
+		TTL: 5 * time.Second,
+		Key: []string{"avm", c.chainID, "list_recent_assets", params.CacheKey(params.KeyLimit, limit)},
+		CachableFn: func(ctx context.Context) (interface{}, error) {
+			return c.reader.ListRecentAssets(ctx, limit)
+		},
+	})
+}
+
 func (c *APIContext) GetAsset(w web.ResponseWriter, r *web.Request) {
 	id := r.PathParams["id"]
 	c.WriteCacheable(w, api.Cachable{
 		Key: c.cacheKeyForID("get_address", id),
 		CachableFn: func(ctx context.Context) (interface{}, error) {
-			return c.reader.GetAsset(ctx, id)
+			return nilIfNotFound(c.reader.GetAsset(ctx, id))
 		},
 	})
 }
@@ -203,7 +356,61 @@ func (c *APIContext) GetAddress(w web.ResponseWriter, r *web.Request) {
 		TTL: 1 * time.Second,
 		Key: c.cacheKeyForID("get_address", r.PathParams["id"]),
 		CachableFn: func(ctx context.Context) (interface{}, error) {
-			return c.reader.GetAddress(ctx, id)
+			return nilIfNotFound(c.reader.GetAddress(ctx, id))
+		},
+	})
+}
+
+func (c *APIContext) ListAddressTransactions(w web.ResponseWriter, r *web.Request) {
+	id, err := params.AddressFromString(r.PathParams["id"])
+	if err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+
+	p := &params.ListParams{}
+	if err = p.ForValues(r.URL.Query()); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+
+	c.WriteCacheable(w, api.Cachable{
+		TTL: 1 * time.Second,
+		Key: append(c.cacheKeyForParams("list_address_transactions", p), params.CacheKey(params.KeyAddress, id.String())),
+		CachableFn: func(ctx context.Context) (interface{}, error) {
+			return c.reader.ListAddressTransactions(ctx, id, p)
+		},
+	})
+}
+
+func (c *APIContext) GetAddressPortfolio(w web.ResponseWriter, r *web.Request) {
+	id, err := params.AddressFromString(r.PathParams["id"])
+	if err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+
+	c.WriteCacheable(w, api.Cachable{
+		TTL: 1 * time.Second,
+		Key: c.cacheKeyForID("get_address_portfolio", r.PathParams["id"]),
+		CachableFn: func(ctx context.Context) (interface{}, error) {
+			return c.reader.GetPortfolio(ctx, id)
+		},
+	})
+}
+
+func (c *APIContext) ListAddressAssets(w web.ResponseWriter, r *web.Request) {
+	id, err := params.AddressFromString(r.PathParams["id"])
+	if err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
+
+	c.WriteCacheable(w, api.Cachable{
+		TTL: 5 * time.Second,
+		Key: c.cacheKeyForID("list_address_assets", r.PathParams["id"]),
+		CachableFn: func(ctx context.Context) (interface{}, error) {
+			return c.reader.ListAddressAssets(ctx, id)
 		},
 	})
 }
@@ -214,6 +421,10 @@ func (c *APIContext) ListOutputs(w web.ResponseWriter, r *web.Request) {
 		c.WriteErr(w, 400, err)
 		return
 	}
+	if err := p.Validate(); err != nil {
+		c.WriteErr(w, 400, err)
+		return
+	}
 
 	if len(p.ChainIDs) < 1 {
 		p.ChainIDs = []string{c.chainID}
@@ -238,11 +449,43 @@ func (c *APIContext) GetOutput(w web.ResponseWriter, r *web.Request) {
 	c.WriteCacheable(w, api.Cachable{
 		Key: c.cacheKeyForID("get_output", r.PathParams["id"]),
 		CachableFn: func(ctx context.Context) (interface{}, error) {
-			return c.reader.GetOutput(ctx, id)
+			return nilIfNotFound(c.reader.GetOutput(ctx, id))
 		},
 	})
 }
 
+// WriteCacheable writes the output of the Cachable like the RootRequestContext
+// does, but responds to the Reader's typed errors with their own status codes
+func (c *APIContext) WriteCacheable(w web.ResponseWriter, cachable api.Cachable) {
+	if cachable.ErrorCodeFn == nil {
+		cachable.ErrorCodeFn = readerErrorCode
+	}
+	c.RootRequestContext.WriteCacheable(w, cachable)
+}
+
+// readerErrorCode returns 503 for queries the Reader gave up on under load and
+// 400 for requests it rejected as invalid. Other errors are left hidden.
+func readerErrorCode(err error) int {
+	if errors.Is(err, ErrQueryTimeout) || errors.Is(err, ErrTooBusy) {
+		return 503
+	}
+	for _, validationErr := range validationErrors {
+		if errors.Is(err, validationErr) {
+			return 400
+		}
+	}
+	return 0
+}
+
+// nilIfNotFound maps the Reader's not found errors to a nil result so the
+// endpoints keep responding with null for unknown IDs
+func nilIfNotFound(result interface{}, err error) (interface{}, error) {
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return result, err
+}
+
 func (c *APIContext) cacheKeyForID(name string, id string) []string {
 	return []string{"avm", c.chainID, name, params.CacheKey("id", id)}
 }
@@ -4,17 +4,32 @@
 package avm
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/alicebob/miniredis"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/health"
 
 	"github.com/ava-labs/ortelius/services"
 
 	"github.com/ava-labs/ortelius/cfg"
+	"github.com/ava-labs/ortelius/services/indexes/avax"
+	"github.com/ava-labs/ortelius/services/indexes/models"
 	"github.com/ava-labs/ortelius/services/indexes/params"
 )
 
@@ -43,7 +58,4885 @@ func TestIndexBootstrap(t *testing.T) {
 	}
 }
 
-func newTestIndex(t *testing.T, networkID uint32, chainID ids.ID) (*Writer, *Reader, func()) {
+func TestGetAssetCreationTransaction(t *testing.T) {
+	writer, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	// Bootstrapping indexes the genesis asset and the tx creating it
+	if err := writer.Bootstrap(newTestContext()); err != nil {
+		t.Fatal("Failed to bootstrap index:", err.Error())
+	}
+
+	assetList, err := reader.ListAssets(context.Background(), &params.ListAssetsParams{})
+	if err != nil {
+		t.Fatal("Failed to list assets:", err.Error())
+	}
+	if len(assetList.Assets) != 1 {
+		t.Fatal("Incorrect number of assets:", len(assetList.Assets))
+	}
+	asset := assetList.Assets[0]
+	if asset.CreatedByTransactionID == "" {
+		t.Fatal("Missing creation transaction")
+	}
+
+	assetID, err := ids.FromString(string(asset.ID))
+	if err != nil {
+		t.Fatal("Failed to parse asset id:", err.Error())
+	}
+	tx, err := reader.GetAssetCreationTransaction(context.Background(), assetID)
+	if err != nil {
+		t.Fatal("Failed to get creation transaction:", err.Error())
+	}
+	if tx == nil || tx.ID != asset.CreatedByTransactionID {
+		t.Fatal("Incorrect creation transaction:", tx)
+	}
+	if len(tx.Outputs) == 0 || tx.Outputs[0].AssetID != asset.ID {
+		t.Fatal("Creation transaction doesn't output the asset")
+	}
+
+	if _, err = reader.GetAssetCreationTransaction(context.Background(), testID(1)); err != ErrAssetNotFound {
+		t.Fatal("Expected ErrAssetNotFound, got:", err)
+	}
+}
+
+func TestListTransactionsByAssetIDs(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// tx1 creates an output of asset1, tx2 spends it and creates an output of
+	// asset2, and tx3 only touches asset3
+	tx1, tx2, tx3 := testID(1), testID(2), testID(3)
+	asset1, asset2, asset3 := testID(101), testID(102), testID(103)
+	addr := testShortID(1)
+
+	insertTestTransaction(t, sess, tx1, now)
+	insertTestTransaction(t, sess, tx2, now.Add(time.Second))
+	insertTestTransaction(t, sess, tx3, now.Add(2*time.Second))
+	insertTestOutput(t, sess, tx1, 0, asset1, 100, addr, now)
+	insertTestOutput(t, sess, tx1, 1, asset1, 200, addr, now)
+	insertTestOutput(t, sess, tx2, 0, asset2, 100, addr, now.Add(time.Second))
+	insertTestOutput(t, sess, tx3, 0, asset3, 100, addr, now.Add(2*time.Second))
+	spendTestOutput(t, sess, tx1.Prefix(0), tx2)
+
+	for _, test := range []struct {
+		assetIDs []ids.ID
+		expected []ids.ID
+	}{
+		{[]ids.ID{asset1}, []ids.ID{tx1, tx2}},
+		{[]ids.ID{asset2}, []ids.ID{tx2}},
+		{[]ids.ID{asset1, asset3}, []ids.ID{tx1, tx2, tx3}},
+		{[]ids.ID{testID(104)}, []ids.ID{}},
+	} {
+		// Use a limit equal to the expected count to force the count query to run
+		p := &params.ListTransactionsParams{AssetIDs: test.assetIDs}
+		p.Limit = len(test.expected)
+
+		txList, err := reader.ListTransactions(context.Background(), p)
+		if err != nil {
+			t.Fatal("Failed to list transactions:", err.Error())
+		}
+		assertTransactionIDs(t, txList.Transactions, test.expected)
+		if txList.Count != uint64(len(test.expected)) {
+			t.Fatal("Incorrect count:", txList.Count)
+		}
+	}
+}
+
+func TestListOutputsCursor(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// Two outputs share a timestamp so the id must break the tie
+	txID := testID(1)
+	insertTestOutput(t, sess, txID, 0, testID(101), 1, testShortID(1), now)
+	insertTestOutput(t, sess, txID, 1, testID(101), 1, testShortID(1), now)
+	insertTestOutput(t, sess, txID, 2, testID(101), 1, testShortID(1), now.Add(time.Second))
+
+	seen := map[models.StringID]struct{}{}
+	cursor := params.Cursor{}
+	for page := 0; ; page++ {
+		p := &params.ListOutputsParams{StartAfter: &cursor}
+		p.Limit = 2
+
+		outputList, err := reader.ListOutputs(context.Background(), p)
+		if err != nil {
+			t.Fatal("Failed to list outputs:", err.Error())
+		}
+
+		// Insert a new output between page fetches, which must not cause a
+		// duplicate on the next page
+		if page == 0 {
+			insertTestOutput(t, sess, txID, 3, testID(101), 1, testShortID(1), now.Add(2*time.Second))
+		}
+
+		for _, output := range outputList.Outputs {
+			if _, ok := seen[output.ID]; ok {
+				t.Fatal("Duplicate output:", output.ID)
+			}
+			seen[output.ID] = struct{}{}
+		}
+
+		if outputList.NextCursor == "" {
+			if outputList.Count != 4 {
+				t.Fatal("Incorrect count:", outputList.Count)
+			}
+			break
+		}
+		if page > 2 {
+			t.Fatal("Too many pages")
+		}
+
+		cursor, err = params.ParseCursor(outputList.NextCursor)
+		if err != nil {
+			t.Fatal("Failed to parse cursor:", err.Error())
+		}
+	}
+
+	if len(seen) != 4 {
+		t.Fatal("Incorrect number of outputs:", len(seen))
+	}
+}
+
+func TestListAssetsCursor(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// Two assets share a timestamp so the id must break the tie
+	insertTestAsset(t, sess, testID(101), testXChainID.String(), 0, now)
+	insertTestAsset(t, sess, testID(102), testXChainID.String(), 0, now)
+	insertTestAsset(t, sess, testID(103), testXChainID.String(), 0, now.Add(time.Second))
+
+	seen := map[models.StringID]struct{}{}
+	cursor := params.Cursor{}
+	for page := 0; ; page++ {
+		p := &params.ListAssetsParams{StartAfter: &cursor}
+		p.Limit = 2
+
+		assetList, err := reader.ListAssets(context.Background(), p)
+		if err != nil {
+			t.Fatal("Failed to list assets:", err.Error())
+		}
+
+		// Create a new asset between page fetches, which must not cause a
+		// duplicate on the next page
+		if page == 0 {
+			insertTestAsset(t, sess, testID(104), testXChainID.String(), 0, now.Add(2*time.Second))
+		}
+
+		for _, asset := range assetList.Assets {
+			if _, ok := seen[asset.ID]; ok {
+				t.Fatal("Duplicate asset:", asset.ID)
+			}
+			seen[asset.ID] = struct{}{}
+		}
+
+		if assetList.NextCursor == "" {
+			if assetList.Count != 4 {
+				t.Fatal("Incorrect count:", assetList.Count)
+			}
+			break
+		}
+		if page > 2 {
+			t.Fatal("Too many pages")
+		}
+
+		cursor, err = params.ParseCursor(assetList.NextCursor)
+		if err != nil {
+			t.Fatal("Failed to parse cursor:", err.Error())
+		}
+	}
+
+	if len(seen) != 4 {
+		t.Fatal("Incorrect number of assets:", len(seen))
+	}
+
+	// Counting can be disabled while paginating by cursor
+	p := &params.ListAssetsParams{StartAfter: &params.Cursor{}}
+	p.Limit = 2
+	p.DisableCounting = true
+	assetList, err := reader.ListAssets(context.Background(), p)
+	if err != nil {
+		t.Fatal("Failed to list assets:", err.Error())
+	}
+	if assetList.Count != 0 || assetList.NextCursor == "" || len(assetList.Assets) != 2 {
+		t.Fatalf("Incorrect page without counting: %d assets, count %d, cursor %q", len(assetList.Assets), assetList.Count, assetList.NextCursor)
+	}
+
+	// Cursors only support the default sort
+	p = &params.ListAssetsParams{StartAfter: &params.Cursor{}, Sort: params.AssetSortRelevance}
+	if _, err = reader.ListAssets(context.Background(), p); err != params.ErrCursorWithSort {
+		t.Fatal("Expected ErrCursorWithSort, got:", err)
+	}
+}
+
+func TestListHasMore(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// Two of each type of result
+	for i := byte(1); i <= 2; i++ {
+		insertTestTransaction(t, sess, testID(i), now)
+		insertTestOutput(t, sess, testID(i), 0, testID(100+i), 1, testShortID(i), now)
+		insertTestAsset(t, sess, testID(100+i), testXChainID.String(), 0, now)
+	}
+
+	ctx := context.Background()
+	for _, test := range []struct {
+		limit   int
+		hasMore bool
+	}{
+		{1, true},
+		{2, false},
+		{3, false},
+	} {
+		// Counting is disabled so that HasMore is the only way to tell
+		listParams := params.ListParams{Limit: test.limit, DisableCounting: true}
+		expectedLen := test.limit
+		if expectedLen > 2 {
+			expectedLen = 2
+		}
+
+		assertPage := func(name string, metadata models.ListMetadata, n int) {
+			if n != expectedLen || metadata.HasMore != test.hasMore || metadata.Count != 0 {
+				t.Fatalf("Incorrect %s page for limit %d: %d results, hasMore %t, count %d", name, test.limit, n, metadata.HasMore, metadata.Count)
+			}
+		}
+
+		txList, err := reader.ListTransactions(ctx, &params.ListTransactionsParams{ListParams: listParams})
+		if err != nil {
+			t.Fatal("Failed to list transactions:", err.Error())
+		}
+		assertPage("transactions", txList.ListMetadata, len(txList.Transactions))
+
+		assetList, err := reader.ListAssets(ctx, &params.ListAssetsParams{ListParams: listParams})
+		if err != nil {
+			t.Fatal("Failed to list assets:", err.Error())
+		}
+		assertPage("assets", assetList.ListMetadata, len(assetList.Assets))
+
+		addressList, err := reader.ListAddresses(ctx, &params.ListAddressesParams{ListParams: listParams})
+		if err != nil {
+			t.Fatal("Failed to list addresses:", err.Error())
+		}
+		assertPage("addresses", addressList.ListMetadata, len(addressList.Addresses))
+
+		outputList, err := reader.ListOutputs(ctx, &params.ListOutputsParams{ListParams: listParams})
+		if err != nil {
+			t.Fatal("Failed to list outputs:", err.Error())
+		}
+		assertPage("outputs", outputList.ListMetadata, len(outputList.Outputs))
+	}
+}
+
+// testQuerySink records the SQL of every select run by sessions of the stream
+// it's added to
+type testQuerySink struct{ queries []string }
+
+func (*testQuerySink) EmitEvent(string, string, map[string]string)                            {}
+func (*testQuerySink) EmitEventErr(string, string, error, map[string]string)                  {}
+func (*testQuerySink) EmitComplete(string, health.CompletionStatus, int64, map[string]string) {}
+func (*testQuerySink) EmitGauge(string, string, float64, map[string]string)                   {}
+func (s *testQuerySink) EmitTiming(_ string, event string, _ int64, kvs map[string]string) {
+	if event == "dbr.select" {
+		s.queries = append(s.queries, kvs["sql"])
+	}
+}
+
+func TestListCountOnly(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// Two of each type of result
+	for i := byte(1); i <= 2; i++ {
+		insertTestTransaction(t, sess, testID(i), now)
+		insertTestOutput(t, sess, testID(i), 0, testID(100+i), 1, testShortID(i), now)
+		insertTestAsset(t, sess, testID(100+i), testXChainID.String(), 0, now)
+	}
+
+	sink := &testQuerySink{}
+	reader.conns.Stream().AddSink(sink)
+
+	// Counting is disabled to show that it's overridden
+	ctx := context.Background()
+	listParams := params.ListParams{Limit: 1, CountOnly: true, DisableCounting: true}
+	for name, list := range map[string]func() (models.ListMetadata, int, error){
+		"transactions": func() (models.ListMetadata, int, error) {
+			l, err := reader.ListTransactions(ctx, &params.ListTransactionsParams{ListParams: listParams})
+			return l.ListMetadata, len(l.Transactions), err
+		},
+		"assets": func() (models.ListMetadata, int, error) {
+			l, err := reader.ListAssets(ctx, &params.ListAssetsParams{ListParams: listParams})
+			return l.ListMetadata, len(l.Assets), err
+		},
+		"addresses": func() (models.ListMetadata, int, error) {
+			l, err := reader.ListAddresses(ctx, &params.ListAddressesParams{ListParams: listParams})
+			return l.ListMetadata, len(l.Addresses), err
+		},
+		"outputs": func() (models.ListMetadata, int, error) {
+			l, err := reader.ListOutputs(ctx, &params.ListOutputsParams{ListParams: listParams})
+			return l.ListMetadata, len(l.Outputs), err
+		},
+	} {
+		sink.queries = nil
+		metadata, n, err := list()
+		if err != nil {
+			t.Fatalf("Failed to list %s: %s", name, err.Error())
+		}
+		if metadata.Count != 2 || n != 0 || metadata.HasMore {
+			t.Fatalf("Incorrect %s: count %d, %d results, hasMore %t", name, metadata.Count, n, metadata.HasMore)
+		}
+
+		if len(sink.queries) == 0 {
+			t.Fatalf("No queries recorded for %s", name)
+		}
+		for _, query := range sink.queries {
+			if !strings.HasPrefix(query, "SELECT COUNT(") {
+				t.Fatalf("Loaded %s rows in count only mode: %s", name, query)
+			}
+		}
+	}
+}
+
+func TestListApproximateCounts(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// A ttl of 1ns makes every cached count stale, so each request serves the
+	// cached count and refreshes it
+	approxReader := NewReader(reader.conns, testXChainID.String(), WithApproximateCounts(time.Nanosecond))
+
+	for i := byte(1); i <= 3; i++ {
+		insertTestTransaction(t, sess, testID(i), now)
+	}
+
+	assertCount := func(exact bool, expected uint64, expectedApproximate bool) {
+		p := &params.ListTransactionsParams{ListParams: params.ListParams{Limit: 1, ExactCount: exact}}
+		txList, err := approxReader.ListTransactions(context.Background(), p)
+		if err != nil {
+			t.Fatal("Failed to list transactions:", err.Error())
+		}
+		if txList.Count != expected || txList.Approximate != expectedApproximate {
+			t.Fatalf("Incorrect count: %d, approximate %t", txList.Count, txList.Approximate)
+		}
+	}
+	waitForRefresh := func() {
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			approxReader.countCacheLock.Lock()
+			refreshing := false
+			for _, entry := range approxReader.countCache {
+				refreshing = refreshing || entry.refreshing
+			}
+			approxReader.countCacheLock.Unlock()
+			if !refreshing {
+				return
+			}
+		}
+		t.Fatal("Count wasn't refreshed")
+	}
+
+	// The first count is exact and cached
+	assertCount(false, 3, false)
+
+	// New transactions aren't counted until the cached count is refreshed
+	insertTestTransaction(t, sess, testID(4), now)
+	insertTestTransaction(t, sess, testID(5), now)
+	assertCount(false, 3, true)
+	waitForRefresh()
+	assertCount(false, 5, true)
+	waitForRefresh()
+
+	// Exact counts are still available
+	insertTestTransaction(t, sess, testID(6), now)
+	assertCount(true, 6, false)
+
+	// Counts aren't approximated by default
+	txList, err := reader.ListTransactions(context.Background(), &params.ListTransactionsParams{ListParams: params.ListParams{Limit: 1}})
+	if err != nil {
+		t.Fatal("Failed to list transactions:", err.Error())
+	}
+	if txList.Count != 6 || txList.Approximate {
+		t.Fatalf("Incorrect count: %d, approximate %t", txList.Count, txList.Approximate)
+	}
+}
+
+func TestListOutputsByAddresses(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// addr1 and addr2 share a multisig output and each have one of their own
+	addr1, addr2 := testShortID(1), testShortID(2)
+	multisigID := testID(1).Prefix(0)
+	insertTestOutput(t, sess, testID(1), 0, testID(101), 100, addr1, now)
+	_, err := sess.
+		InsertInto("avm_output_addresses").
+		Pair("output_id", multisigID.String()).
+		Pair("address", addr2.String()).
+		Pair("created_at", now).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to insert output address:", err.Error())
+	}
+	insertTestOutput(t, sess, testID(2), 0, testID(101), 200, addr1, now)
+	insertTestOutput(t, sess, testID(3), 0, testID(101), 300, addr2, now)
+	insertTestOutput(t, sess, testID(4), 0, testID(101), 400, testShortID(3), now)
+
+	p := &params.ListOutputsParams{Addresses: []ids.ShortID{addr1, addr2}}
+	p.Limit = 1
+	outputList, err := reader.ListOutputs(context.Background(), p)
+	if err != nil {
+		t.Fatal("Failed to list outputs:", err.Error())
+	}
+	if outputList.Count != 3 {
+		t.Fatal("Incorrect count:", outputList.Count)
+	}
+
+	outputList, err = reader.ListOutputs(context.Background(), &params.ListOutputsParams{Addresses: []ids.ShortID{addr1, addr2}})
+	if err != nil {
+		t.Fatal("Failed to list outputs:", err.Error())
+	}
+	if len(outputList.Outputs) != 3 {
+		t.Fatal("Incorrect number of outputs:", len(outputList.Outputs))
+	}
+
+	seen := map[models.StringID]struct{}{}
+	for _, output := range outputList.Outputs {
+		if _, ok := seen[output.ID]; ok {
+			t.Fatal("Duplicate output:", output.ID)
+		}
+		seen[output.ID] = struct{}{}
+
+		// The multisig output is still listed with both of its addresses
+		if output.ID == models.ToStringID(multisigID) && len(output.Addresses) != 2 {
+			t.Fatal("Incorrect multisig addresses:", output.Addresses)
+		}
+	}
+	if _, ok := seen[models.ToStringID(testID(4).Prefix(0))]; ok {
+		t.Fatal("Listed output of another address")
+	}
+}
+
+func TestListOutputsSort(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// Amounts don't follow creation order, and 9 sorts before 10 only when
+	// compared numerically
+	out1, out2, out3 := testID(1).Prefix(0), testID(2).Prefix(0), testID(3).Prefix(0)
+	insertTestOutput(t, sess, testID(1), 0, testID(101), 10, testShortID(1), now)
+	insertTestOutput(t, sess, testID(2), 0, testID(101), 9, testShortID(1), now.Add(time.Second))
+	insertTestOutput(t, sess, testID(3), 0, testID(101), 100, testShortID(1), now.Add(2*time.Second))
+
+	for _, test := range []struct {
+		sort     params.OutputSort
+		expected []ids.ID
+	}{
+		{"", []ids.ID{out1, out2, out3}},
+		{params.OutputSortTimestampAsc, []ids.ID{out1, out2, out3}},
+		{params.OutputSortTimestampDesc, []ids.ID{out3, out2, out1}},
+		{params.OutputSortAmountAsc, []ids.ID{out2, out1, out3}},
+		{params.OutputSortAmountDesc, []ids.ID{out3, out1, out2}},
+	} {
+		// A full page makes ListOutputs run its count query, which is unsorted
+		p := &params.ListOutputsParams{Sort: test.sort}
+		p.Limit = 3
+		outputList, err := reader.ListOutputs(context.Background(), p)
+		if err != nil {
+			t.Fatal("Failed to list outputs:", err.Error())
+		}
+		if outputList.Count != 3 || len(outputList.Outputs) != len(test.expected) {
+			t.Fatalf("Incorrect outputs for %q sort: %d of %d", test.sort, len(outputList.Outputs), outputList.Count)
+		}
+		for i, output := range outputList.Outputs {
+			if output.ID != models.ToStringID(test.expected[i]) {
+				t.Fatalf("Incorrect output %d for %q sort: %s", i, test.sort, output.ID)
+			}
+		}
+	}
+
+	// Cursors only support the default sort
+	p := &params.ListOutputsParams{StartAfter: &params.Cursor{}, Sort: params.OutputSortAmountDesc}
+	if _, err := reader.ListOutputs(context.Background(), p); err != params.ErrCursorWithSort {
+		t.Fatal("Expected ErrCursorWithSort, got:", err)
+	}
+}
+
+func TestListOutputsByAmount(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// 9 is less than 10 only when compared numerically
+	out1, out2, out3 := testID(1).Prefix(0), testID(2).Prefix(0), testID(3).Prefix(0)
+	insertTestOutput(t, sess, testID(1), 0, testID(101), 10, testShortID(1), now)
+	insertTestOutput(t, sess, testID(2), 0, testID(101), 9, testShortID(1), now.Add(time.Second))
+	insertTestOutput(t, sess, testID(3), 0, testID(101), 100, testShortID(1), now.Add(2*time.Second))
+
+	// Bounds larger than the amount column can hold still compare correctly
+	huge, _ := new(big.Int).SetString("1000000000000000000000", 10)
+
+	for name, test := range map[string]struct {
+		min, max *big.Int
+		expected []ids.ID
+	}{
+		"min":         {big.NewInt(10), nil, []ids.ID{out1, out3}},
+		"max":         {nil, big.NewInt(10), []ids.ID{out1, out2}},
+		"both":        {big.NewInt(10), big.NewInt(99), []ids.ID{out1}},
+		"equal":       {big.NewInt(9), big.NewInt(9), []ids.ID{out2}},
+		"huge max":    {nil, huge, []ids.ID{out1, out2, out3}},
+		"huge min":    {huge, nil, []ids.ID{}},
+		"unbounded":   {nil, nil, []ids.ID{out1, out2, out3}},
+		"empty range": {big.NewInt(11), big.NewInt(99), []ids.ID{}},
+	} {
+		// A page of one makes ListOutputs run its count query whenever there's
+		// a result
+		p := &params.ListOutputsParams{MinAmount: test.min, MaxAmount: test.max}
+		p.Limit = 1
+		outputList, err := reader.ListOutputs(context.Background(), p)
+		if err != nil {
+			t.Fatalf("Failed to list outputs by %s: %s", name, err.Error())
+		}
+		if outputList.Count != uint64(len(test.expected)) {
+			t.Fatalf("Incorrect count by %s: %d", name, outputList.Count)
+		}
+		if len(test.expected) > 0 && (len(outputList.Outputs) != 1 || outputList.Outputs[0].ID != models.ToStringID(test.expected[0])) {
+			t.Fatalf("Incorrect outputs by %s: %v", name, outputList.Outputs)
+		}
+	}
+
+	p := &params.ListOutputsParams{MinAmount: big.NewInt(11), MaxAmount: big.NewInt(10)}
+	if _, err := reader.ListOutputs(context.Background(), p); err != params.ErrInvalidAmountRange {
+		t.Fatal("Expected ErrInvalidAmountRange, got:", err)
+	}
+}
+
+func TestSnapshotUTXOs(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// Five outputs of the asset, two sharing a timestamp, of which one is spent
+	assetID := testID(101)
+	insertTestOutput(t, sess, testID(1), 0, assetID, 1, testShortID(1), now)
+	insertTestOutput(t, sess, testID(1), 1, assetID, 2, testShortID(2), now)
+	insertTestOutput(t, sess, testID(2), 0, assetID, 3, testShortID(1), now.Add(time.Second))
+	insertTestOutput(t, sess, testID(3), 0, assetID, 4, testShortID(1), now.Add(2*time.Second))
+	insertTestOutput(t, sess, testID(4), 0, assetID, 5, testShortID(1), now.Add(3*time.Second))
+	insertTestOutput(t, sess, testID(5), 0, testID(102), 6, testShortID(1), now)
+	spendTestOutput(t, sess, testID(2).Prefix(0), testID(6))
+
+	// Outputs sharing a timestamp are ordered by ID
+	expected := []ids.ID{testID(1).Prefix(0), testID(1).Prefix(1), testID(3).Prefix(0), testID(4).Prefix(0)}
+	if expected[0].String() > expected[1].String() {
+		expected[0], expected[1] = expected[1], expected[0]
+	}
+
+	// Small batches page through the outputs in several queries
+	for _, batchSize := range []int{2, 3, UTXOSnapshotBatchSize} {
+		outputIDs := []models.StringID{}
+		err := reader.snapshotUTXOs(context.Background(), assetID, batchSize, func(output *models.Output) error {
+			if len(output.Addresses) != 1 {
+				t.Fatal("Incorrect addresses:", output.Addresses)
+			}
+			outputIDs = append(outputIDs, output.ID)
+			return nil
+		})
+		if err != nil {
+			t.Fatal("Failed to snapshot UTXOs:", err.Error())
+		}
+
+		if len(outputIDs) != len(expected) {
+			t.Fatalf("Incorrect number of UTXOs with batch size %d: %d", batchSize, len(outputIDs))
+		}
+		for i, id := range expected {
+			if outputIDs[i] != models.ToStringID(id) {
+				t.Fatalf("Incorrect UTXO %d with batch size %d: %s", i, batchSize, outputIDs[i])
+			}
+		}
+	}
+
+	// Callback errors stop the snapshot
+	calls := 0
+	errStop := errors.New("stop")
+	err := reader.SnapshotUTXOs(context.Background(), assetID, func(*models.Output) error {
+		calls++
+		return errStop
+	})
+	if err != errStop || calls != 1 {
+		t.Fatalf("Expected snapshot to stop after 1 call, got %d calls: %v", calls, err)
+	}
+}
+
+func TestDetectInconsistentOutputs(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+	asset := testID(101)
+
+	// tx1 and tx4 create the asset, so they may output more than they spend
+	tx1, tx2, tx3, tx4, tx5, unindexed := testID(1), testID(2), testID(3), testID(4), testID(5), testID(9)
+	for _, txID := range []ids.ID{tx1, tx2, tx3, tx5} {
+		insertTestTransaction(t, sess, txID, now)
+	}
+	insertTestTransaction(t, sess, tx4, now.Add(time.Minute))
+	for _, txID := range []ids.ID{tx1, tx4} {
+		_, err := sess.
+			Update("avm_transactions").
+			Set("type", models.TransactionTypeCreateAsset.String()).
+			Where("id = ?", txID.String()).
+			Exec()
+		if err != nil {
+			t.Fatal("Failed to update transaction:", err.Error())
+		}
+	}
+
+	// tx2 spends 100 to output 90, which is consistent
+	insertTestOutput(t, sess, tx1, 0, asset, 100, testShortID(1), now)
+	spendTestOutput(t, sess, tx1.Prefix(0), tx2)
+	insertTestOutput(t, sess, tx2, 0, asset, 90, testShortID(1), now)
+
+	// One output is spent by a transaction that isn't indexed, and one before
+	// it was created
+	insertTestOutput(t, sess, tx1, 1, asset, 10, testShortID(1), now)
+	spendTestOutput(t, sess, tx1.Prefix(1), unindexed)
+	insertTestOutput(t, sess, tx4, 0, asset, 10, testShortID(1), now.Add(time.Minute))
+	spendTestOutput(t, sess, tx4.Prefix(0), tx3)
+
+	// tx5 spends 50 to output 60
+	insertTestOutput(t, sess, tx1, 2, asset, 50, testShortID(1), now)
+	spendTestOutput(t, sess, tx1.Prefix(2), tx5)
+	insertTestOutput(t, sess, tx5, 0, asset, 60, testShortID(1), now)
+
+	expectedOutputs := map[models.StringID]models.InconsistencyReason{
+		models.ToStringID(tx1.Prefix(1)): models.InconsistencyRedeemerNotIndexed,
+		models.ToStringID(tx4.Prefix(0)): models.InconsistencyRedeemedBeforeCreated,
+	}
+
+	// Small batches scan the index in several queries
+	for _, batchSize := range []int{1, 2, IntegrityScanBatchSize} {
+		report, err := reader.detectInconsistentOutputs(context.Background(), 0, batchSize)
+		if err != nil {
+			t.Fatal("Failed to detect inconsistent outputs:", err.Error())
+		}
+		if report.HasMore || len(report.Outputs) != len(expectedOutputs) {
+			t.Fatalf("Incorrect outputs with batch size %d: %d, hasMore %t", batchSize, len(report.Outputs), report.HasMore)
+		}
+		for _, output := range report.Outputs {
+			if reason, ok := expectedOutputs[output.ID]; !ok || output.Reason != reason {
+				t.Fatalf("Incorrect output with batch size %d: %s, %s", batchSize, output.ID, output.Reason)
+			}
+		}
+		expectedFee := &models.FeeInconsistency{
+			TransactionID: models.ToStringID(tx5),
+			AssetID:       models.ToStringID(asset),
+			InputAmount:   "50",
+			OutputAmount:  "60",
+		}
+		if len(report.Transactions) != 1 || !reflect.DeepEqual(report.Transactions[0], expectedFee) {
+			t.Fatalf("Incorrect transactions with batch size %d: %v", batchSize, report.Transactions)
+		}
+	}
+
+	// The report stops at the limit
+	report, err := reader.DetectInconsistentOutputs(context.Background(), 1)
+	if err != nil {
+		t.Fatal("Failed to detect inconsistent outputs:", err.Error())
+	}
+	if !report.HasMore || len(report.Outputs) != 1 || len(report.Transactions) != 1 {
+		t.Fatalf("Incorrect limited report: %d outputs, %d transactions, hasMore %t", len(report.Outputs), len(report.Transactions), report.HasMore)
+	}
+}
+
+func TestListOutputsByType(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	txID := testID(1)
+	insertTestOutput(t, sess, txID, 0, testID(101), 1, testShortID(1), now)
+	insertTestOutput(t, sess, txID, 1, testID(101), 1, testShortID(1), now)
+	insertTestOutput(t, sess, txID, 2, testID(101), 1, testShortID(1), now)
+	_, err := sess.
+		Update("avm_outputs").
+		Set("output_type", models.OutputTypesNFTMint).
+		Where("id = ?", txID.Prefix(2).String()).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to set output type:", err.Error())
+	}
+
+	for _, test := range []struct {
+		outputTypes []models.OutputType
+		expected    int
+	}{
+		{nil, 3},
+		{[]models.OutputType{models.OutputTypesSECP2556K1Transfer}, 2},
+		{[]models.OutputType{models.OutputTypesNFTMint}, 1},
+		{[]models.OutputType{models.OutputTypesSECP2556K1Transfer, models.OutputTypesNFTMint}, 3},
+		{[]models.OutputType{models.OutputTypesNFTTransfer}, 0},
+	} {
+		// Use a limit of 1 to force the count query to run
+		p := &params.ListOutputsParams{OutputTypes: test.outputTypes}
+		p.Limit = 1
+
+		outputList, err := reader.ListOutputs(context.Background(), p)
+		if err != nil {
+			t.Fatal("Failed to list outputs:", err.Error())
+		}
+		if outputList.Count != uint64(test.expected) {
+			t.Fatalf("Incorrect count for %v: %d", test.outputTypes, outputList.Count)
+		}
+		for _, output := range outputList.Outputs {
+			if !containsOutputType(test.outputTypes, output.OutputType) {
+				t.Fatal("Incorrect output type:", output.OutputType)
+			}
+		}
+	}
+
+	p := &params.ListOutputsParams{OutputTypes: []models.OutputType{99}}
+	if _, err = reader.ListOutputs(context.Background(), p); !errors.Is(err, params.ErrUndefinedOutputType) {
+		t.Fatal("Expected an undefined output type error, got:", err)
+	}
+
+	p = &params.ListOutputsParams{}
+	if err = p.ForValues(url.Values{params.KeyOutputType: {"nft_mint", "7"}}); err != nil {
+		t.Fatal("Failed to parse output types:", err.Error())
+	}
+	if len(p.OutputTypes) != 2 || p.OutputTypes[0] != models.OutputTypesNFTMint || p.OutputTypes[1] != models.OutputTypesSECP2556K1Transfer {
+		t.Fatal("Incorrect output types:", p.OutputTypes)
+	}
+	if err = p.ForValues(url.Values{params.KeyOutputType: {"nft"}}); !errors.Is(err, params.ErrUndefinedOutputType) {
+		t.Fatal("Expected an undefined output type error, got:", err)
+	}
+}
+
+func TestListOutputsIncludeRedeemedAt(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	spent := created.Add(time.Hour)
+
+	// tx2 spends the first of tx1's outputs an hour after it was created
+	insertTestTransaction(t, sess, testID(1), created)
+	insertTestTransaction(t, sess, testID(2), spent)
+	insertTestOutput(t, sess, testID(1), 0, testID(101), 100, testShortID(1), created)
+	insertTestOutput(t, sess, testID(1), 1, testID(101), 200, testShortID(1), created.Add(time.Second))
+	spendTestOutput(t, sess, testID(1).Prefix(0), testID(2))
+
+	for _, includeRedeemedAt := range []bool{false, true} {
+		outputList, err := reader.ListOutputs(context.Background(), &params.ListOutputsParams{IncludeRedeemedAt: includeRedeemedAt})
+		if err != nil {
+			t.Fatal("Failed to list outputs:", err.Error())
+		}
+		if len(outputList.Outputs) != 2 {
+			t.Fatal("Incorrect number of outputs:", len(outputList.Outputs))
+		}
+
+		spentOutput, unspentOutput := outputList.Outputs[0], outputList.Outputs[1]
+		if unspentOutput.RedeemedAt != nil {
+			t.Fatal("Unspent output has a redeemed time:", unspentOutput.RedeemedAt)
+		}
+		if !includeRedeemedAt {
+			if spentOutput.RedeemedAt != nil {
+				t.Fatal("Redeemed time set without being requested")
+			}
+			continue
+		}
+		if spentOutput.RedeemedAt == nil || !spentOutput.RedeemedAt.Equal(spent) {
+			t.Fatal("Incorrect redeemed time:", spentOutput.RedeemedAt)
+		}
+	}
+}
+
+func TestListOutputsIncludeAddresses(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	insertTestTransactionChain(t, sess, 3)
+
+	addressQueries := 0
+	hookedReader := NewReader(reader.conns, testXChainID.String(),
+		WithQueryHook(func(_, sql string, _ []interface{}, _ time.Duration) {
+			if strings.Contains(sql, "avm_output_addresses.output_id IN") {
+				addressQueries++
+			}
+		}))
+
+	include, exclude := true, false
+	for _, includeAddresses := range []*bool{nil, &include, &exclude} {
+		addressQueries = 0
+		outputList, err := hookedReader.ListOutputs(context.Background(), &params.ListOutputsParams{IncludeAddresses: includeAddresses})
+		if err != nil {
+			t.Fatal("Failed to list outputs:", err.Error())
+		}
+		if len(outputList.Outputs) != 3 {
+			t.Fatal("Incorrect number of outputs:", len(outputList.Outputs))
+		}
+
+		// Addresses are only skipped when explicitly excluded
+		included := includeAddresses == nil || *includeAddresses
+		expectedQueries, expectedAddresses := 0, 0
+		if included {
+			expectedQueries, expectedAddresses = 1, 1
+		}
+		if addressQueries != expectedQueries {
+			t.Fatal("Incorrect number of address queries:", addressQueries)
+		}
+		for _, output := range outputList.Outputs {
+			if len(output.Addresses) != expectedAddresses {
+				t.Fatal("Incorrect number of addresses for output:", output.ID, len(output.Addresses))
+			}
+		}
+	}
+}
+
+func TestListOutputsDecodePayloads(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	payloads := []struct {
+		payload  []byte
+		expected *models.DecodedPayload
+	}{
+		{[]byte("ipfs://QmNft/1.png"), &models.DecodedPayload{Format: models.PayloadFormatURL, Text: "ipfs://QmNft/1.png"}},
+		{[]byte(`{"name":"nft"}`), &models.DecodedPayload{Format: models.PayloadFormatJSON, Text: `{"name":"nft"}`}},
+		{[]byte("héllo nft"), &models.DecodedPayload{Format: models.PayloadFormatText, Text: "héllo nft"}},
+		{[]byte{0x00, 0x01, 0xff, 0xfe}, nil},
+		{nil, nil},
+	}
+	txID := testID(1)
+	insertTestTransaction(t, sess, txID, now)
+	for i, test := range payloads {
+		insertTestOutput(t, sess, txID, uint64(i), testID(101), 1, testShortID(1), now)
+		_, err := sess.
+			Update("avm_outputs").
+			Set("payload", test.payload).
+			Where("id = ?", txID.Prefix(uint64(i)).String()).
+			Exec()
+		if err != nil {
+			t.Fatal("Failed to set payload:", err.Error())
+		}
+	}
+
+	for _, decode := range []bool{false, true} {
+		outputList, err := reader.ListOutputs(context.Background(), &params.ListOutputsParams{DecodePayloads: decode})
+		if err != nil {
+			t.Fatal("Failed to list outputs:", err.Error())
+		}
+		if len(outputList.Outputs) != len(payloads) {
+			t.Fatal("Incorrect number of outputs:", len(outputList.Outputs))
+		}
+
+		// Binary and empty payloads are left undecoded, as are all of them
+		// unless requested
+		for _, output := range outputList.Outputs {
+			expected := payloads[output.OutputIndex].expected
+			if !decode {
+				expected = nil
+			}
+			if !reflect.DeepEqual(output.DecodedPayload, expected) {
+				t.Fatalf("Incorrect decoded payload of output %d: %+v", output.OutputIndex, output.DecodedPayload)
+			}
+		}
+	}
+}
+
+func TestListOutputsLockStatus(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// Outputs without a locktime, locked until tomorrow, and unlocked since
+	// yesterday
+	past, future := now.Add(-24*time.Hour), now.Add(24*time.Hour)
+	locktimes := []uint64{0, uint64(future.Unix()), uint64(past.Unix())}
+	for idx, locktime := range locktimes {
+		insertTestOutput(t, sess, testID(1), uint64(idx), testID(101), 100, testShortID(1), now)
+		_, err := sess.
+			Update("avm_outputs").
+			Set("locktime", locktime).
+			Where("id = ?", testID(1).Prefix(uint64(idx)).String()).
+			Exec()
+		if err != nil {
+			t.Fatal("Failed to set locktime:", err.Error())
+		}
+	}
+
+	outputList, err := reader.ListOutputs(context.Background(), &params.ListOutputsParams{})
+	if err != nil {
+		t.Fatal("Failed to list outputs:", err.Error())
+	}
+	outputs := make(map[uint64]*models.Output, len(outputList.Outputs))
+	for _, output := range outputList.Outputs {
+		outputs[output.OutputIndex] = output
+	}
+	if len(outputs) != len(locktimes) {
+		t.Fatal("Incorrect number of outputs:", len(outputs))
+	}
+
+	if outputs[0].Locked || outputs[0].UnlocksAt != nil {
+		t.Fatal("Output without a locktime is locked:", outputs[0].UnlocksAt)
+	}
+	if !outputs[1].Locked || outputs[1].UnlocksAt == nil || !outputs[1].UnlocksAt.Equal(future) {
+		t.Fatal("Incorrect lock status of locked output:", outputs[1].Locked, outputs[1].UnlocksAt)
+	}
+	if outputs[2].Locked || outputs[2].UnlocksAt == nil || !outputs[2].UnlocksAt.Equal(past) {
+		t.Fatal("Incorrect lock status of unlocked output:", outputs[2].Locked, outputs[2].UnlocksAt)
+	}
+}
+
+func TestListOutputsByGroupID(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// Outputs 0 and 1 share NFT group 5, output 2 is in group 6 and output 3
+	// isn't an NFT
+	txID := testID(1)
+	for idx, groupID := range []uint32{5, 5, 6} {
+		insertTestOutput(t, sess, txID, uint64(idx), testID(101), 1, testShortID(1), now)
+		_, err := sess.
+			Update("avm_outputs").
+			Set("output_type", models.OutputTypesNFTTransfer).
+			Set("group_id", groupID).
+			Where("id = ?", txID.Prefix(uint64(idx)).String()).
+			Exec()
+		if err != nil {
+			t.Fatal("Failed to set group id:", err.Error())
+		}
+	}
+	insertTestOutput(t, sess, txID, 3, testID(102), 1, testShortID(1), now)
+
+	groupID := uint32(5)
+	p := &params.ListOutputsParams{GroupID: &groupID}
+	p.Limit = 1
+	outputList, err := reader.ListOutputs(context.Background(), p)
+	if err != nil {
+		t.Fatal("Failed to list outputs:", err.Error())
+	}
+	if outputList.Count != 2 {
+		t.Fatal("Incorrect count:", outputList.Count)
+	}
+
+	p = &params.ListOutputsParams{GroupID: &groupID}
+	outputList, err = reader.ListOutputs(context.Background(), p)
+	if err != nil {
+		t.Fatal("Failed to list outputs:", err.Error())
+	}
+	if len(outputList.Outputs) != 2 {
+		t.Fatal("Incorrect number of outputs:", len(outputList.Outputs))
+	}
+	for _, output := range outputList.Outputs {
+		if output.ID != models.ToStringID(txID.Prefix(0)) && output.ID != models.ToStringID(txID.Prefix(1)) {
+			t.Fatal("Incorrect output:", output.ID)
+		}
+	}
+
+	// Group 0 matches the non-NFT output unless restricted to NFTs
+	groupID = 0
+	for _, test := range []struct {
+		outputTypes []models.OutputType
+		expected    int
+	}{
+		{nil, 1},
+		{[]models.OutputType{models.OutputTypesNFTTransfer, models.OutputTypesNFTMint}, 0},
+	} {
+		p = &params.ListOutputsParams{GroupID: &groupID, OutputTypes: test.outputTypes}
+		outputList, err = reader.ListOutputs(context.Background(), p)
+		if err != nil {
+			t.Fatal("Failed to list outputs:", err.Error())
+		}
+		if len(outputList.Outputs) != test.expected {
+			t.Fatalf("Incorrect number of outputs for %v: %d", test.outputTypes, len(outputList.Outputs))
+		}
+	}
+}
+
+func TestAggregateNFTGroups(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+	asset := testID(101)
+
+	insertNFTOutput := func(txID ids.ID, idx uint64, outputType models.OutputType, groupID uint32, addr ids.ShortID, ts time.Time) {
+		insertTestOutput(t, sess, txID, idx, asset, 1, addr, ts)
+		_, err := sess.
+			Update("avm_outputs").
+			Set("output_type", outputType).
+			Set("group_id", groupID).
+			Where("id = ?", txID.Prefix(idx).String()).
+			Exec()
+		if err != nil {
+			t.Fatal("Failed to set group id:", err.Error())
+		}
+	}
+
+	// Group 5 has three NFTs, one of them spent by tx2 to the owner of another,
+	// and group 6 has one. The mint output and the plain transfer output of the
+	// asset aren't NFTs and aren't counted.
+	tx1, tx2 := testID(1), testID(2)
+	insertTestTransaction(t, sess, tx1, now)
+	insertTestTransaction(t, sess, tx2, now.Add(10*time.Second))
+	insertNFTOutput(tx1, 0, models.OutputTypesNFTTransfer, 5, testShortID(1), now)
+	insertNFTOutput(tx1, 1, models.OutputTypesNFTTransfer, 5, testShortID(2), now)
+	insertNFTOutput(tx1, 2, models.OutputTypesNFTTransfer, 6, testShortID(1), now)
+	insertNFTOutput(tx1, 3, models.OutputTypesNFTMint, 7, testShortID(1), now)
+	insertTestOutput(t, sess, tx1, 4, asset, 1000, testShortID(3), now)
+	insertNFTOutput(tx2, 0, models.OutputTypesNFTTransfer, 5, testShortID(2), now.Add(10*time.Second))
+	spendTestOutput(t, sess, tx1.Prefix(0), tx2)
+
+	groups, err := reader.AggregateNFTGroups(context.Background(), asset)
+	if err != nil {
+		t.Fatal("Failed to aggregate NFT groups:", err.Error())
+	}
+
+	expected := []models.NFTGroupStats{
+		{GroupID: 5, OutputCount: 3, HolderCount: 1, LastActivity: now.Add(10 * time.Second)},
+		{GroupID: 6, OutputCount: 1, HolderCount: 1, LastActivity: now},
+	}
+	if len(groups) != len(expected) {
+		t.Fatal("Incorrect number of groups:", groups)
+	}
+	for i, group := range groups {
+		if group.GroupID != expected[i].GroupID ||
+			group.OutputCount != expected[i].OutputCount ||
+			group.HolderCount != expected[i].HolderCount ||
+			!group.LastActivity.Equal(expected[i].LastActivity) {
+			t.Fatalf("Incorrect group at %d: %+v", i, group)
+		}
+	}
+
+	// Assets without NFTs have no groups
+	groups, err = reader.AggregateNFTGroups(context.Background(), testID(102))
+	if err != nil {
+		t.Fatal("Failed to aggregate NFT groups:", err.Error())
+	}
+	if len(groups) != 0 {
+		t.Fatal("Expected no groups:", groups)
+	}
+}
+
+func containsOutputType(outputTypes []models.OutputType, outputType models.OutputType) bool {
+	if len(outputTypes) == 0 {
+		return true
+	}
+	for _, t := range outputTypes {
+		if t == outputType {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAggregateParamsValidation(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		name         string
+		startTime    time.Time
+		endTime      time.Time
+		intervalSize time.Duration
+		expectedErr  error
+	}{
+		{"valid", start, start.Add(2 * time.Hour), time.Hour, nil},
+		{"whole range", start, start.Add(2 * time.Hour), 0, nil},
+		{"interval equal to range", start, start.Add(time.Hour), time.Hour, nil},
+		{"end before start", start, start.Add(-time.Hour), time.Hour, params.ErrInvalidTimeRange},
+		{"end before start without interval", start, start.Add(-time.Second), 0, params.ErrInvalidTimeRange},
+		{"negative interval", start, start.Add(2 * time.Hour), -time.Hour, params.ErrInvalidIntervalSize},
+		{"interval larger than range", start, start.Add(time.Hour), 2 * time.Hour, params.ErrIntervalSizeTooLarge},
+		{"interval with empty range", start, start, time.Hour, params.ErrIntervalSizeTooLarge},
+	} {
+		p := &params.AggregateParams{StartTime: test.startTime, EndTime: test.endTime, IntervalSize: test.intervalSize}
+		if _, err := reader.Aggregate(context.Background(), p); err != test.expectedErr {
+			t.Fatalf("%s: expected error %v from Aggregate, got %v", test.name, test.expectedErr, err)
+		}
+
+		p = &params.AggregateParams{StartTime: test.startTime, EndTime: test.endTime, IntervalSize: test.intervalSize}
+		if _, err := reader.AggregateByAsset(context.Background(), p); err != test.expectedErr {
+			t.Fatalf("%s: expected error %v from AggregateByAsset, got %v", test.name, test.expectedErr, err)
+		}
+	}
+}
+
+func TestAggregateIntervalCountTooLarge(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := reader.Aggregate(context.Background(), &params.AggregateParams{
+		StartTime:    start,
+		EndTime:      start.Add((MaxAggregateIntervalCount + 1) * time.Minute),
+		IntervalSize: time.Minute,
+	})
+	if !errors.Is(err, ErrAggregateIntervalCountTooLarge) {
+		t.Fatal("Expected ErrAggregateIntervalCountTooLarge, got:", err)
+	}
+
+	expected := fmt.Sprintf("requested %d intervals, max %d", MaxAggregateIntervalCount+1, MaxAggregateIntervalCount)
+	if !strings.Contains(err.Error(), expected) {
+		t.Fatal("Incorrect error message:", err.Error())
+	}
+}
+
+func TestAggregateByAsset(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// asset1 has outputs in the first and last intervals, asset2 only in the
+	// second interval
+	asset1, asset2 := testID(101), testID(102)
+	insertTestOutput(t, sess, testID(1), 0, asset1, 10, testShortID(1), start)
+	insertTestOutput(t, sess, testID(2), 0, asset1, 20, testShortID(1), start.Add(2*time.Hour))
+	insertTestOutput(t, sess, testID(3), 0, asset2, 30, testShortID(1), start.Add(time.Hour))
+
+	histograms, err := reader.AggregateByAsset(context.Background(), &params.AggregateParams{
+		StartTime:    start,
+		EndTime:      start.Add(3 * time.Hour),
+		IntervalSize: time.Hour,
+	})
+	if err != nil {
+		t.Fatal("Failed to aggregate:", err.Error())
+	}
+	if len(histograms) != 2 {
+		t.Fatal("Incorrect number of assets:", len(histograms))
+	}
+
+	// Padded intervals are left without a volume
+	for assetID, expected := range map[ids.ID][]models.TokenAmount{
+		asset1: {"10", "", "20"},
+		asset2: {"", "30", ""},
+	} {
+		histogram := histograms[models.ToStringID(assetID)]
+		if histogram == nil {
+			t.Fatal("Missing asset:", assetID.String())
+		}
+		if len(histogram.Intervals) != len(expected) {
+			t.Fatal("Incorrect number of intervals:", len(histogram.Intervals))
+		}
+		for i, interval := range histogram.Intervals {
+			if interval.TransactionVolume != expected[i] {
+				t.Fatalf("Incorrect volume for interval %d: %s", i, interval.TransactionVolume)
+			}
+			if !interval.StartTime.Equal(start.Add(time.Duration(i) * time.Hour)) {
+				t.Fatalf("Incorrect start time for interval %d: %s", i, interval.StartTime)
+			}
+		}
+	}
+}
+
+func TestTopAssetsByVolume(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	// asset2 has the most volume in the window and asset1 the least. asset3's
+	// largest output is after the window so it ranks second.
+	asset1, asset2, asset3 := testID(101), testID(102), testID(103)
+	insertTestTransaction(t, sess, testID(1), start)
+	insertTestOutput(t, sess, testID(1), 0, asset1, 10, testShortID(1), start)
+	insertTestOutput(t, sess, testID(1), 1, asset2, 900, testShortID(1), start)
+	insertTestOutput(t, sess, testID(1), 2, asset2, 100, testShortID(1), start.Add(time.Hour))
+	insertTestOutput(t, sess, testID(1), 3, asset3, 50, testShortID(1), start.Add(time.Hour))
+	insertTestOutput(t, sess, testID(1), 4, asset3, 5000, testShortID(1), end)
+
+	for _, test := range []struct {
+		limit    int
+		expected []models.AssetVolume
+	}{
+		{0, []models.AssetVolume{
+			{AssetID: models.ToStringID(asset2), Volume: "1000"},
+			{AssetID: models.ToStringID(asset3), Volume: "50"},
+			{AssetID: models.ToStringID(asset1), Volume: "10"},
+		}},
+		{2, []models.AssetVolume{
+			{AssetID: models.ToStringID(asset2), Volume: "1000"},
+			{AssetID: models.ToStringID(asset3), Volume: "50"},
+		}},
+	} {
+		volumes, err := reader.TopAssetsByVolume(context.Background(), start, end, test.limit)
+		if err != nil {
+			t.Fatal("Failed to get top assets:", err.Error())
+		}
+		if !reflect.DeepEqual(volumes, test.expected) {
+			t.Fatalf("Incorrect top assets for limit %d: %+v", test.limit, volumes)
+		}
+	}
+}
+
+func TestAggregateVolumeExcludedOutputTypes(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// A transfer of 100 and an NFT whose amount isn't fungible
+	insertTestOutput(t, sess, testID(1), 0, testID(101), 100, testShortID(1), start)
+	insertTestOutput(t, sess, testID(1), 1, testID(102), 7, testShortID(2), start)
+	_, err := sess.
+		Update("avm_outputs").
+		Set("output_type", models.OutputTypesNFTTransfer).
+		Where("id = ?", testID(1).Prefix(1).String()).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to set output type:", err.Error())
+	}
+
+	for _, test := range []struct {
+		excluded []models.OutputType
+		volume   models.TokenAmount
+	}{
+		{nil, "107"},
+		{[]models.OutputType{models.OutputTypesNFTTransfer, models.OutputTypesNFTMint}, "100"},
+	} {
+		histogram, err := reader.Aggregate(context.Background(), &params.AggregateParams{
+			StartTime:                 start,
+			EndTime:                   start.Add(time.Hour),
+			IntervalSize:              time.Hour,
+			VolumeExcludedOutputTypes: test.excluded,
+		})
+		if err != nil {
+			t.Fatal("Failed to aggregate:", err.Error())
+		}
+		for _, aggs := range []models.Aggregates{histogram.Aggregates, histogram.Intervals[0]} {
+			if aggs.TransactionVolume != test.volume {
+				t.Fatalf("Incorrect volume excluding %v: %s", test.excluded, aggs.TransactionVolume)
+			}
+			if aggs.OutputCount != 2 || aggs.AssetCount != 2 || aggs.AddressCount != 2 {
+				t.Fatalf("Incorrect counts excluding %v: %+v", test.excluded, aggs)
+			}
+		}
+	}
+
+	// Undefined output types are rejected
+	_, err = reader.Aggregate(context.Background(), &params.AggregateParams{
+		StartTime:                 start,
+		EndTime:                   start.Add(time.Hour),
+		VolumeExcludedOutputTypes: []models.OutputType{99},
+	})
+	if !errors.Is(err, params.ErrUndefinedOutputType) {
+		t.Fatal("Expected ErrUndefinedOutputType, got:", err)
+	}
+}
+
+func TestAggregateIntervalWindow(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Outputs in hourly intervals 50, 105, and 150 of a 200 hour series
+	insertTestOutput(t, sess, testID(1), 0, testID(101), 100, testShortID(1), start.Add(50*time.Hour))
+	insertTestOutput(t, sess, testID(2), 0, testID(101), 200, testShortID(1), start.Add(105*time.Hour))
+	insertTestOutput(t, sess, testID(3), 0, testID(101), 300, testShortID(1), start.Add(150*time.Hour))
+
+	histogram, err := reader.Aggregate(context.Background(), &params.AggregateParams{
+		StartTime:      start,
+		EndTime:        start.Add(200 * time.Hour),
+		IntervalSize:   time.Hour,
+		IntervalOffset: 100,
+		IntervalLimit:  11,
+	})
+	if err != nil {
+		t.Fatal("Failed to aggregate:", err.Error())
+	}
+
+	// The totals cover the whole series
+	if histogram.Aggregates.OutputCount != 3 || histogram.Aggregates.TransactionVolume != "600" {
+		t.Fatal("Incorrect aggregates:", histogram.Aggregates)
+	}
+	if histogram.IntervalCount != 200 {
+		t.Fatal("Incorrect interval count:", histogram.IntervalCount)
+	}
+
+	// Only intervals 100 through 110 are returned, padded around interval 105
+	if len(histogram.Intervals) != 11 {
+		t.Fatal("Incorrect number of intervals:", len(histogram.Intervals))
+	}
+	for i, interval := range histogram.Intervals {
+		idx := 100 + i
+		if !interval.StartTime.Equal(start.Add(time.Duration(idx) * time.Hour)) {
+			t.Fatalf("Incorrect start time of interval %d: %s", idx, interval.StartTime)
+		}
+		expectedCount := uint64(0)
+		if idx == 105 {
+			expectedCount = 1
+		}
+		if interval.OutputCount != expectedCount {
+			t.Fatalf("Incorrect output count of interval %d: %d", idx, interval.OutputCount)
+		}
+	}
+
+	// Windows past the end are empty and negative ones are rejected
+	histogram, err = reader.Aggregate(context.Background(), &params.AggregateParams{
+		StartTime:      start,
+		EndTime:        start.Add(200 * time.Hour),
+		IntervalSize:   time.Hour,
+		IntervalOffset: 195,
+		IntervalLimit:  10,
+	})
+	if err != nil {
+		t.Fatal("Failed to aggregate:", err.Error())
+	}
+	if len(histogram.Intervals) != 5 {
+		t.Fatal("Incorrect number of intervals:", len(histogram.Intervals))
+	}
+	_, err = reader.Aggregate(context.Background(), &params.AggregateParams{
+		StartTime:      start,
+		EndTime:        start.Add(200 * time.Hour),
+		IntervalSize:   time.Hour,
+		IntervalOffset: -1,
+	})
+	if err != params.ErrInvalidIntervalWindow {
+		t.Fatal("Expected ErrInvalidIntervalWindow, got:", err)
+	}
+}
+
+func TestAggregateNonUTCStartTime(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+
+	// Midnight in UTC-5 is 05:00 UTC, so daily intervals run from 05:00 UTC
+	est := time.FixedZone("EST", -5*60*60)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, est)
+	startUTC := time.Date(2020, 1, 1, 5, 0, 0, 0, time.UTC)
+
+	// Outputs just before and after the end of the first interval, and one
+	// before the start that falls on the first UTC day
+	insertTestOutput(t, sess, testID(1), 0, testID(101), 100, testShortID(1), startUTC.Add(-time.Hour))
+	insertTestOutput(t, sess, testID(2), 0, testID(101), 200, testShortID(1), startUTC.Add(23*time.Hour))
+	insertTestOutput(t, sess, testID(3), 0, testID(101), 300, testShortID(1), startUTC.Add(25*time.Hour))
+
+	histogram, err := reader.Aggregate(context.Background(), &params.AggregateParams{
+		StartTime:    start,
+		EndTime:      start.AddDate(0, 0, 2),
+		IntervalSize: 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal("Failed to aggregate:", err.Error())
+	}
+
+	if histogram.Aggregates.StartTime.Location() != time.UTC || !histogram.Aggregates.StartTime.Equal(startUTC) {
+		t.Fatal("Incorrect start time:", histogram.Aggregates.StartTime)
+	}
+	if histogram.Aggregates.OutputCount != 2 || histogram.Aggregates.TransactionVolume != "500" {
+		t.Fatal("Incorrect aggregates:", histogram.Aggregates)
+	}
+	if len(histogram.Intervals) != 2 {
+		t.Fatal("Incorrect number of intervals:", len(histogram.Intervals))
+	}
+	for i, interval := range histogram.Intervals {
+		expectedStart := startUTC.AddDate(0, 0, i)
+		if interval.StartTime != expectedStart || interval.EndTime != expectedStart.Add(24*time.Hour-time.Second) {
+			t.Fatalf("Incorrect times of interval %d: %s - %s", i, interval.StartTime, interval.EndTime)
+		}
+		if interval.OutputCount != 1 {
+			t.Fatalf("Incorrect output count of interval %d: %d", i, interval.OutputCount)
+		}
+	}
+}
+
+func TestAggregateCache(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	assetID := testID(101)
+	insertTestOutput(t, sess, testID(1), 0, assetID, 100, testShortID(1), start.Add(time.Hour))
+
+	aggregateQueries := 0
+	cachingReader := NewReader(reader.conns, testXChainID.String(),
+		WithQueryHook(func(sessionName, _ string, _ []interface{}, _ time.Duration) {
+			if sessionName == "get_transaction_aggregates_histogram" {
+				aggregateQueries++
+			}
+		}))
+	aggregate := func() *models.AggregatesHistogram {
+		histogram, err := cachingReader.Aggregate(context.Background(), &params.AggregateParams{
+			AssetID:      &assetID,
+			StartTime:    start,
+			EndTime:      start.Add(24 * time.Hour),
+			IntervalSize: time.Hour,
+		})
+		if err != nil {
+			t.Fatal("Failed to aggregate:", err.Error())
+		}
+		return histogram
+	}
+
+	// The repeated request is served from the cache
+	first := aggregate()
+	queries := aggregateQueries
+	if queries == 0 {
+		t.Fatal("Expected aggregate queries")
+	}
+	second := aggregate()
+	if aggregateQueries != queries {
+		t.Fatal("Expected the cached aggregates to be used")
+	}
+	if !reflect.DeepEqual(first, second) || first == second {
+		t.Fatal("Incorrect cached aggregates:", second)
+	}
+
+	// Writing to the chain after the time range keeps the cached aggregates
+	job := reader.conns.Stream().NewJob("test")
+	avax.InvalidateAggregates(context.Background(), reader.conns, job, testXChainID.String(), start.Add(72*time.Hour))
+	aggregate()
+	if aggregateQueries != queries {
+		t.Fatal("Expected the cached aggregates to be used after a later write")
+	}
+
+	// Writing to the chain within the time range invalidates them
+	insertTestOutput(t, sess, testID(2), 0, assetID, 200, testShortID(1), start.Add(2*time.Hour))
+	avax.InvalidateAggregates(context.Background(), reader.conns, job, testXChainID.String(), start.Add(2*time.Hour))
+
+	third := aggregate()
+	if aggregateQueries == queries {
+		t.Fatal("Expected the aggregates to be reloaded")
+	}
+	if third.Aggregates.OutputCount != 2 || third.Aggregates.TransactionVolume != "300" {
+		t.Fatal("Incorrect aggregates:", third.Aggregates)
+	}
+}
+
+func TestAggregateIntervalAlignment(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	// 24 hours isn't a multiple of 7, so the fourth interval only has 3 hours.
+	// The second output is in it.
+	insertTestOutput(t, sess, testID(1), 0, testID(101), 100, testShortID(1), start.Add(time.Hour))
+	insertTestOutput(t, sess, testID(2), 0, testID(101), 200, testShortID(1), start.Add(22*time.Hour))
+
+	aggregate := func(alignment params.IntervalAlignment, intervalSize time.Duration) (*models.AggregatesHistogram, error) {
+		return reader.Aggregate(context.Background(), &params.AggregateParams{
+			StartTime:         start,
+			EndTime:           end,
+			IntervalSize:      intervalSize,
+			IntervalAlignment: alignment,
+		})
+	}
+
+	// Without alignment the partial interval is returned
+	histogram, err := aggregate(params.IntervalAlignmentNone, 7*time.Hour)
+	if err != nil {
+		t.Fatal("Failed to aggregate:", err.Error())
+	}
+	if len(histogram.Intervals) != 4 || histogram.Aggregates.OutputCount != 2 || histogram.RequestedEndTime != nil {
+		t.Fatal("Incorrect histogram:", histogram)
+	}
+
+	// Strict alignment rejects the range unless it's a multiple of the size
+	if _, err = aggregate(params.IntervalAlignmentStrict, 7*time.Hour); err != params.ErrIntervalRangeNotAligned {
+		t.Fatal("Expected ErrIntervalRangeNotAligned, got:", err)
+	}
+	histogram, err = aggregate(params.IntervalAlignmentStrict, 6*time.Hour)
+	if err != nil {
+		t.Fatal("Failed to aggregate:", err.Error())
+	}
+	if len(histogram.Intervals) != 4 || histogram.RequestedEndTime != nil {
+		t.Fatal("Incorrect histogram:", histogram)
+	}
+
+	// Aligning ends the range after the third interval and reports the end
+	// time that was requested
+	histogram, err = aggregate(params.IntervalAlignmentAlign, 7*time.Hour)
+	if err != nil {
+		t.Fatal("Failed to aggregate:", err.Error())
+	}
+	if len(histogram.Intervals) != 3 || histogram.Aggregates.OutputCount != 1 {
+		t.Fatal("Incorrect histogram:", histogram)
+	}
+	if !histogram.Aggregates.EndTime.Equal(start.Add(21*time.Hour)) || !histogram.Intervals[2].EndTime.Equal(start.Add(21*time.Hour-time.Second)) {
+		t.Fatal("Incorrect end time:", histogram.Aggregates.EndTime)
+	}
+	if histogram.RequestedEndTime == nil || !histogram.RequestedEndTime.Equal(end) {
+		t.Fatal("Incorrect requested end time:", histogram.RequestedEndTime)
+	}
+
+	// Undefined alignments are rejected
+	p := &params.AggregateParams{}
+	if err = p.ForValues(url.Values{params.KeyIntervalAlignment: {"sideways"}}); !errors.Is(err, params.ErrUndefinedIntervalAlignment) {
+		t.Fatal("Expected ErrUndefinedIntervalAlignment, got:", err)
+	}
+}
+
+func TestAggregateSingleAsset(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// asset1 has 10 and 250 output by two txs, the 250 owned by two addresses.
+	// asset2's output would make a combined volume meaningless.
+	asset1, asset2 := testID(101), testID(102)
+	insertTestOutput(t, sess, testID(1), 0, asset1, 10, testShortID(1), start)
+	insertTestOutput(t, sess, testID(2), 0, asset1, 250, testShortID(2), start.Add(time.Hour))
+	_, err := sess.
+		InsertInto("avm_output_addresses").
+		Pair("output_id", testID(2).Prefix(0).String()).
+		Pair("address", testShortID(3).String()).
+		Pair("created_at", start.Add(time.Hour)).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to insert output address:", err.Error())
+	}
+	insertTestOutput(t, sess, testID(2), 1, asset2, 1000000, testShortID(2), start.Add(time.Hour))
+
+	aggregate := func(intervalSize time.Duration) *models.AggregatesHistogram {
+		histogram, err := reader.Aggregate(context.Background(), &params.AggregateParams{
+			AssetID:      &asset1,
+			StartTime:    start,
+			EndTime:      start.Add(2 * time.Hour),
+			IntervalSize: intervalSize,
+		})
+		if err != nil {
+			t.Fatal("Failed to aggregate:", err.Error())
+		}
+		return histogram
+	}
+	assertAggregates := func(name string, aggs models.Aggregates, expected models.Aggregates) {
+		if aggs.TransactionVolume != expected.TransactionVolume ||
+			aggs.TransactionCount != expected.TransactionCount ||
+			aggs.OutputCount != expected.OutputCount ||
+			aggs.AddressCount != expected.AddressCount ||
+			aggs.AssetCount != expected.AssetCount {
+			t.Fatalf("Incorrect %s: %+v", name, aggs)
+		}
+	}
+
+	assertAggregates("aggregates", aggregate(0).Aggregates, models.Aggregates{
+		TransactionVolume: "260", TransactionCount: 2, OutputCount: 2, AddressCount: 3, AssetCount: 1,
+	})
+
+	histogram := aggregate(time.Hour)
+	if len(histogram.Intervals) != 2 {
+		t.Fatal("Incorrect number of intervals:", len(histogram.Intervals))
+	}
+	assertAggregates("first interval", histogram.Intervals[0], models.Aggregates{
+		TransactionVolume: "10", TransactionCount: 1, OutputCount: 1, AddressCount: 1, AssetCount: 1,
+	})
+	assertAggregates("second interval", histogram.Intervals[1], models.Aggregates{
+		TransactionVolume: "250", TransactionCount: 1, OutputCount: 1, AddressCount: 2, AssetCount: 1,
+	})
+	if histogram.Aggregates.TransactionVolume != "260" {
+		t.Fatal("Incorrect volume:", histogram.Aggregates.TransactionVolume)
+	}
+}
+
+func TestAggregateVelocity(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// The asset's supply is 3000, of which 1000 moves in the first hour, 2000
+	// in the second, and none in the third
+	asset := testID(101)
+	insertTestAsset(t, sess, asset, testXChainID.String(), 0, start)
+	_, err := sess.
+		Update("avm_assets").
+		Set("current_supply", 3000).
+		Where("id = ?", asset.String()).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to set supply:", err.Error())
+	}
+	insertTestOutput(t, sess, testID(1), 0, asset, 1000, testShortID(1), start)
+	insertTestOutput(t, sess, testID(2), 0, asset, 2000, testShortID(1), start.Add(time.Hour))
+
+	aggregate := func(assetID *ids.ID) *models.AggregatesHistogram {
+		histogram, err := reader.Aggregate(context.Background(), &params.AggregateParams{
+			AssetID:      assetID,
+			StartTime:    start,
+			EndTime:      start.Add(3 * time.Hour),
+			IntervalSize: time.Hour,
+		})
+		if err != nil {
+			t.Fatal("Failed to aggregate:", err.Error())
+		}
+		return histogram
+	}
+
+	histogram := aggregate(&asset)
+	if histogram.Aggregates.Velocity != "1.00000000" {
+		t.Fatal("Incorrect velocity:", histogram.Aggregates.Velocity)
+	}
+	if len(histogram.Intervals) != 3 {
+		t.Fatal("Incorrect number of intervals:", len(histogram.Intervals))
+	}
+
+	// Velocities are rounded half away from zero and padded intervals are left
+	// without one like their volume
+	for i, expected := range []string{"0.33333333", "0.66666667", ""} {
+		if histogram.Intervals[i].Velocity != expected {
+			t.Fatalf("Incorrect velocity for interval %d: %s", i, histogram.Intervals[i].Velocity)
+		}
+	}
+
+	// Velocity isn't meaningful across assets
+	histogram = aggregate(nil)
+	if histogram.Aggregates.Velocity != "" {
+		t.Fatal("Expected no velocity without an asset, got:", histogram.Aggregates.Velocity)
+	}
+}
+
+func TestAggregateGroupByChain(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	otherChainID := testID(200).String()
+
+	// The Reader's chain has two outputs in the first interval, the other
+	// chain has one in the second interval
+	insertTestOutput(t, sess, testID(1), 0, testID(101), 10, testShortID(1), start)
+	insertTestOutput(t, sess, testID(1), 1, testID(101), 20, testShortID(2), start)
+	insertTestOutput(t, sess, testID(2), 0, testID(102), 30, testShortID(1), start.Add(time.Hour))
+	_, err := sess.
+		Update("avm_outputs").
+		Set("chain_id", otherChainID).
+		Where("id = ?", testID(2).Prefix(0).String()).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to move output to other chain:", err.Error())
+	}
+
+	aggs, err := reader.Aggregate(context.Background(), &params.AggregateParams{
+		ChainIDs:     []string{testXChainID.String(), otherChainID},
+		StartTime:    start,
+		EndTime:      start.Add(2 * time.Hour),
+		IntervalSize: time.Hour,
+		GroupByChain: true,
+	})
+	if err != nil {
+		t.Fatal("Failed to aggregate:", err.Error())
+	}
+
+	// Intervals still cover both chains
+	if len(aggs.Intervals) != 2 ||
+		aggs.Intervals[0].TransactionVolume != "30" ||
+		aggs.Intervals[1].TransactionVolume != "30" {
+		t.Fatal("Incorrect intervals:", aggs.Intervals)
+	}
+	if aggs.Aggregates.TransactionVolume != "60" || aggs.Aggregates.OutputCount != 3 {
+		t.Fatal("Incorrect aggregates:", aggs.Aggregates)
+	}
+
+	if len(aggs.Chains) != 2 {
+		t.Fatal("Incorrect number of chains:", len(aggs.Chains))
+	}
+	for chainID, expected := range map[string]models.Aggregates{
+		testXChainID.String(): {TransactionVolume: "30", TransactionCount: 1, AddressCount: 2, OutputCount: 2, AssetCount: 1},
+		otherChainID:          {TransactionVolume: "30", TransactionCount: 1, AddressCount: 1, OutputCount: 1, AssetCount: 1},
+	} {
+		expected.StartTime, expected.EndTime = start, start.Add(2*time.Hour)
+		if actual, ok := aggs.Chains[chainID]; !ok || actual != expected {
+			t.Fatalf("Incorrect aggregates for chain %s: %v", chainID, actual)
+		}
+	}
+
+	// Without GroupByChain no chains are returned
+	aggs, err = reader.Aggregate(context.Background(), &params.AggregateParams{
+		ChainIDs:  []string{testXChainID.String(), otherChainID},
+		StartTime: start,
+		EndTime:   start.Add(2 * time.Hour),
+	})
+	if err != nil {
+		t.Fatal("Failed to aggregate:", err.Error())
+	}
+	if aggs.Chains != nil {
+		t.Fatal("Expected no chains, got:", aggs.Chains)
+	}
+}
+
+func TestAggregateActiveAddresses(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// addr1 is active in the first and last intervals, addr2 only in the first
+	insertTestOutput(t, sess, testID(1), 0, testID(101), 10, testShortID(1), start)
+	insertTestOutput(t, sess, testID(1), 1, testID(101), 20, testShortID(1), start)
+	insertTestOutput(t, sess, testID(2), 0, testID(102), 30, testShortID(2), start.Add(time.Minute))
+	insertTestOutput(t, sess, testID(3), 0, testID(101), 40, testShortID(1), start.Add(2*time.Hour))
+
+	for _, chainIDs := range [][]string{nil, {testXChainID.String()}} {
+		aggs, err := reader.AggregateActiveAddresses(context.Background(), &params.AggregateParams{
+			ChainIDs:     chainIDs,
+			StartTime:    start,
+			EndTime:      start.Add(3 * time.Hour),
+			IntervalSize: time.Hour,
+		})
+		if err != nil {
+			t.Fatal("Failed to aggregate active addresses:", err.Error())
+		}
+
+		expected := []uint64{2, 0, 1}
+		if len(aggs.Intervals) != len(expected) {
+			t.Fatal("Incorrect number of intervals:", len(aggs.Intervals))
+		}
+		for i, interval := range aggs.Intervals {
+			if interval.AddressCount != expected[i] {
+				t.Fatalf("Incorrect address count for interval %d: %d", i, interval.AddressCount)
+			}
+			if !interval.StartTime.Equal(start.Add(time.Duration(i) * time.Hour)) {
+				t.Fatalf("Incorrect start time for interval %d: %s", i, interval.StartTime)
+			}
+			if interval.OutputCount != 0 || interval.TransactionVolume != "" {
+				t.Fatalf("Unexpected metrics for interval %d: %v", i, interval)
+			}
+		}
+		if aggs.Aggregates.AddressCount != 3 || aggs.Aggregates.TransactionVolume != "" {
+			t.Fatal("Incorrect aggregates:", aggs.Aggregates)
+		}
+	}
+
+	// Addresses are only counted for the requested asset
+	assetID := testID(102)
+	aggs, err := reader.AggregateActiveAddresses(context.Background(), &params.AggregateParams{
+		AssetID:   &assetID,
+		StartTime: start,
+		EndTime:   start.Add(3 * time.Hour),
+	})
+	if err != nil {
+		t.Fatal("Failed to aggregate active addresses:", err.Error())
+	}
+	if aggs.Aggregates.AddressCount != 1 {
+		t.Fatal("Incorrect address count:", aggs.Aggregates.AddressCount)
+	}
+}
+
+func BenchmarkAggregate(b *testing.B) {
+	benchmarkAggregate(b, func(r *Reader, p *params.AggregateParams) error {
+		_, err := r.Aggregate(context.Background(), p)
+		return err
+	})
+}
+
+func BenchmarkAggregateActiveAddresses(b *testing.B) {
+	benchmarkAggregate(b, func(r *Reader, p *params.AggregateParams) error {
+		_, err := r.AggregateActiveAddresses(context.Background(), p)
+		return err
+	})
+}
+
+func benchmarkAggregate(b *testing.B, aggregate func(*Reader, *params.AggregateParams) error) {
+	_, reader, closeFn := newTestIndex(b, 5, testXChainID)
+	defer closeFn()
+
+	// Spread outputs to 50 addresses over a day
+	sess := reader.conns.DB().NewSession("test")
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 1000; i++ {
+		txID := testID(byte(i / 256))
+		txID = txID.Prefix(uint64(i))
+		insertTestOutput(b, sess, txID, 0, testID(101), uint64(i), testShortID(byte(i%50)), start.Add(time.Duration(i)*time.Minute))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := aggregate(reader, &params.AggregateParams{
+			StartTime:    start,
+			EndTime:      start.Add(24 * time.Hour),
+			IntervalSize: time.Hour,
+		})
+		if err != nil {
+			b.Fatal("Failed to aggregate:", err.Error())
+		}
+	}
+}
+
+func TestAssetSupplyHistory(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// The asset's creation mints 1000 in the first interval, and a transaction
+	// in the last interval spends it all but only creates 900, burning 100
+	assetID, burnTxID := testID(101), testID(2)
+	insertTestOutput(t, sess, assetID, 0, assetID, 1000, testShortID(1), start)
+	insertTestTransaction(t, sess, burnTxID, start.Add(2*time.Hour))
+	insertTestOutput(t, sess, burnTxID, 0, assetID, 900, testShortID(2), start.Add(2*time.Hour))
+	spendTestOutput(t, sess, assetID.Prefix(0), burnTxID)
+
+	// Outputs of other assets are ignored
+	insertTestOutput(t, sess, testID(3), 0, testID(102), 50, testShortID(1), start.Add(time.Hour))
+
+	aggs, err := reader.AssetSupplyHistory(context.Background(), assetID, &params.AggregateParams{
+		StartTime:    start,
+		EndTime:      start.Add(3 * time.Hour),
+		IntervalSize: time.Hour,
+	})
+	if err != nil {
+		t.Fatal("Failed to get asset supply history:", err.Error())
+	}
+
+	expected := []models.TokenAmount{"1000", "", "-100"}
+	if len(aggs.Intervals) != len(expected) {
+		t.Fatal("Incorrect number of intervals:", len(aggs.Intervals))
+	}
+	for i, interval := range aggs.Intervals {
+		if interval.SupplyChange != expected[i] {
+			t.Fatalf("Incorrect supply change for interval %d: %s", i, interval.SupplyChange)
+		}
+	}
+	if aggs.Aggregates.SupplyChange != "900" || aggs.Aggregates.TransactionVolume != "" {
+		t.Fatal("Incorrect aggregates:", aggs.Aggregates)
+	}
+
+	// Without intervals only the total is computed
+	aggs, err = reader.AssetSupplyHistory(context.Background(), assetID, &params.AggregateParams{
+		StartTime: start.Add(time.Hour),
+		EndTime:   start.Add(3 * time.Hour),
+	})
+	if err != nil {
+		t.Fatal("Failed to get asset supply history:", err.Error())
+	}
+	if aggs.Aggregates.SupplyChange != "-100" || len(aggs.Intervals) != 0 {
+		t.Fatal("Incorrect aggregates:", aggs.Aggregates)
+	}
+}
+
+func TestListAddressesByMinBalance(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// addr1 holds 300 of asset1, addr2 held 500 but has spent 400 of it, addr3
+	// only holds asset2
+	asset1, asset2 := testID(101), testID(102)
+	addr1, addr2, addr3 := testShortID(1), testShortID(2), testShortID(3)
+	insertTestOutput(t, sess, testID(1), 0, asset1, 100, addr1, now)
+	insertTestOutput(t, sess, testID(1), 1, asset1, 200, addr1, now)
+	insertTestOutput(t, sess, testID(2), 0, asset1, 400, addr2, now)
+	insertTestOutput(t, sess, testID(2), 1, asset1, 100, addr2, now)
+	insertTestOutput(t, sess, testID(3), 0, asset2, 1000, addr3, now)
+	spendTestOutput(t, sess, testID(2).Prefix(0), testID(4))
+
+	for _, test := range []struct {
+		minBalance int64
+		expected   []ids.ShortID
+	}{
+		{0, []ids.ShortID{addr1, addr2}},
+		{100, []ids.ShortID{addr1, addr2}},
+		{101, []ids.ShortID{addr1}},
+		{300, []ids.ShortID{addr1}},
+		{301, []ids.ShortID{}},
+	} {
+		// Use a limit equal to the expected count to force the count query to run
+		p := &params.ListAddressesParams{AssetID: &asset1, MinBalance: big.NewInt(test.minBalance)}
+		p.Limit = len(test.expected)
+
+		addressList, err := reader.ListAddresses(context.Background(), p)
+		if err != nil {
+			t.Fatal("Failed to list addresses:", err.Error())
+		}
+		if len(addressList.Addresses) != len(test.expected) {
+			t.Fatalf("Incorrect number of addresses for %d: %d", test.minBalance, len(addressList.Addresses))
+		}
+		found := map[models.Address]struct{}{}
+		for _, addr := range addressList.Addresses {
+			found[addr.Address] = struct{}{}
+		}
+		for _, addr := range test.expected {
+			if _, ok := found[models.ToAddress(addr)]; !ok {
+				t.Fatal("Missing address:", addr.String())
+			}
+		}
+		if addressList.Count != uint64(len(test.expected)) {
+			t.Fatalf("Incorrect count for %d: %d", test.minBalance, addressList.Count)
+		}
+	}
+}
+
+func TestListAddressesSortByBalance(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// addr1 holds 300 of asset1, addr2 holds 500, and addr3 holds 100 after
+	// spending 1000. addr3 also holds a lot of asset2, which doesn't count.
+	asset1, asset2 := testID(101), testID(102)
+	addr1, addr2, addr3 := testShortID(1), testShortID(2), testShortID(3)
+	insertTestOutput(t, sess, testID(1), 0, asset1, 100, addr1, now)
+	insertTestOutput(t, sess, testID(1), 1, asset1, 200, addr1, now)
+	insertTestOutput(t, sess, testID(2), 0, asset1, 500, addr2, now)
+	insertTestOutput(t, sess, testID(3), 0, asset1, 1000, addr3, now)
+	insertTestOutput(t, sess, testID(3), 1, asset1, 100, addr3, now)
+	insertTestOutput(t, sess, testID(3), 2, asset2, 5000, addr3, now)
+	spendTestOutput(t, sess, testID(3).Prefix(0), testID(4))
+
+	expected := []ids.ShortID{addr2, addr1, addr3}
+	for _, limit := range []int{3, 2} {
+		p := &params.ListAddressesParams{AssetID: &asset1, SortByBalance: true}
+		p.Limit = limit
+
+		addressList, err := reader.ListAddresses(context.Background(), p)
+		if err != nil {
+			t.Fatal("Failed to list addresses:", err.Error())
+		}
+		if len(addressList.Addresses) != limit {
+			t.Fatal("Incorrect number of addresses:", len(addressList.Addresses))
+		}
+		for i, addr := range addressList.Addresses {
+			if addr.Address != models.ToAddress(expected[i]) {
+				t.Fatalf("Incorrect address at %d: %s", i, addr.Address)
+			}
+		}
+		if addressList.Count != 3 || addressList.HasMore != (limit < 3) {
+			t.Fatal("Incorrect list metadata:", addressList.ListMetadata)
+		}
+	}
+
+	// Sorting by balance requires an asset
+	p := &params.ListAddressesParams{}
+	if err := p.ForValues(url.Values{params.KeySortByBalance: {"true"}}); err != params.ErrSortByBalanceWithoutAsset {
+		t.Fatal("Expected ErrSortByBalanceWithoutAsset, got:", err)
+	}
+}
+
+func TestListAddressesByPublicKey(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// addr1's public key was recovered, addr2 is known without a public key,
+	// and addr3 has only received outputs. addr1 has two outputs to check that
+	// it isn't counted twice.
+	addr1, addr2, addr3 := testShortID(1), testShortID(2), testShortID(3)
+	insertTestOutput(t, sess, testID(1), 0, testID(101), 1, addr1, now)
+	insertTestOutput(t, sess, testID(1), 1, testID(101), 1, addr1, now)
+	insertTestOutput(t, sess, testID(1), 2, testID(101), 1, addr2, now)
+	insertTestOutput(t, sess, testID(1), 3, testID(101), 1, addr3, now)
+	for addr, publicKey := range map[ids.ShortID][]byte{addr1: bytes.Repeat([]byte{2}, 33), addr2: nil} {
+		_, err := sess.
+			InsertInto("addresses").
+			Pair("address", addr.String()).
+			Pair("public_key", publicKey).
+			Exec()
+		if err != nil {
+			t.Fatal("Failed to insert address:", err.Error())
+		}
+	}
+
+	for _, test := range []struct {
+		hasPublicKey bool
+		expected     []ids.ShortID
+	}{
+		{true, []ids.ShortID{addr1}},
+		{false, []ids.ShortID{addr2, addr3}},
+	} {
+		hasPublicKey := test.hasPublicKey
+
+		// A limit of 1 forces the count query to run
+		p := &params.ListAddressesParams{HasPublicKey: &hasPublicKey}
+		p.Limit = 1
+		addressList, err := reader.ListAddresses(context.Background(), p)
+		if err != nil {
+			t.Fatal("Failed to list addresses:", err.Error())
+		}
+		if addressList.Count != uint64(len(test.expected)) {
+			t.Fatalf("Incorrect count for %t: %d", hasPublicKey, addressList.Count)
+		}
+
+		p = &params.ListAddressesParams{HasPublicKey: &hasPublicKey}
+		addressList, err = reader.ListAddresses(context.Background(), p)
+		if err != nil {
+			t.Fatal("Failed to list addresses:", err.Error())
+		}
+		if len(addressList.Addresses) != len(test.expected) {
+			t.Fatalf("Incorrect number of addresses for %t: %d", hasPublicKey, len(addressList.Addresses))
+		}
+		for i, addr := range addressList.Addresses {
+			if addr.Address != models.ToAddress(test.expected[i]) {
+				t.Fatalf("Incorrect address at %d for %t: %s", i, hasPublicKey, addr.Address)
+			}
+			if (len(addr.PublicKey) > 0) != hasPublicKey {
+				t.Fatalf("Incorrect public key for %s: %v", addr.Address, addr.PublicKey)
+			}
+
+			// NULL public keys are scanned as nil so they're encoded as null
+			if !hasPublicKey && addr.PublicKey != nil {
+				t.Fatalf("Expected nil public key for %s: %v", addr.Address, addr.PublicKey)
+			}
+		}
+	}
+}
+
+func TestGetAddressBalances(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// addr1 holds 300 of asset1 after spending 50 and 1000 of asset2
+	asset1, asset2 := testID(101), testID(102)
+	addr1, addr2 := testShortID(1), testShortID(2)
+	insertTestOutput(t, sess, testID(1), 0, asset1, 100, addr1, now)
+	insertTestOutput(t, sess, testID(1), 1, asset1, 200, addr1, now)
+	insertTestOutput(t, sess, testID(1), 2, asset1, 50, addr1, now)
+	insertTestOutput(t, sess, testID(2), 0, asset2, 1000, addr1, now)
+	insertTestOutput(t, sess, testID(2), 1, asset2, 500, addr2, now)
+	spendTestOutput(t, sess, testID(1).Prefix(2), testID(3))
+
+	for _, test := range []struct {
+		addr     ids.ShortID
+		assetIDs []ids.ID
+		expected map[models.StringID]models.TokenAmount
+	}{
+		{addr1, nil, map[models.StringID]models.TokenAmount{
+			models.ToStringID(asset1): "300",
+			models.ToStringID(asset2): "1000",
+		}},
+		{addr1, []ids.ID{asset2}, map[models.StringID]models.TokenAmount{
+			models.ToStringID(asset2): "1000",
+		}},
+		{addr2, []ids.ID{asset1}, map[models.StringID]models.TokenAmount{}},
+		{testShortID(3), nil, map[models.StringID]models.TokenAmount{}},
+	} {
+		balances, err := reader.GetAddressBalances(context.Background(), test.addr, test.assetIDs)
+		if err != nil {
+			t.Fatal("Failed to get address balances:", err.Error())
+		}
+		if balances == nil || len(balances) != len(test.expected) {
+			t.Fatalf("Incorrect balances for %s: %v", test.addr.String(), balances)
+		}
+		for assetID, amount := range test.expected {
+			if balances[assetID] != amount {
+				t.Fatalf("Incorrect balance of %s for %s: %s", assetID, test.addr.String(), balances[assetID])
+			}
+		}
+	}
+}
+
+func TestListAddressAssets(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// addr1 received asset1 twice and asset2 once, and has spent its asset2.
+	// addr2 only received asset3.
+	asset1, asset2, asset3 := testID(101), testID(102), testID(103)
+	addr1, addr2 := testShortID(1), testShortID(2)
+	insertTestOutput(t, sess, testID(1), 0, asset2, 100, addr1, now)
+	insertTestOutput(t, sess, testID(1), 1, asset1, 200, addr1, now)
+	insertTestOutput(t, sess, testID(2), 0, asset1, 300, addr1, now)
+	insertTestOutput(t, sess, testID(2), 1, asset3, 400, addr2, now)
+	spendTestOutput(t, sess, testID(1).Prefix(0), testID(3))
+
+	assetIDs, err := reader.ListAddressAssets(context.Background(), addr1)
+	if err != nil {
+		t.Fatal("Failed to list address assets:", err.Error())
+	}
+	expected := []models.StringID{models.ToStringID(asset1), models.ToStringID(asset2)}
+	if asset2.String() < asset1.String() {
+		expected[0], expected[1] = expected[1], expected[0]
+	}
+	if !reflect.DeepEqual(assetIDs, expected) {
+		t.Fatal("Incorrect assets:", assetIDs)
+	}
+
+	// Unknown addresses have no assets
+	assetIDs, err = reader.ListAddressAssets(context.Background(), testShortID(3))
+	if err != nil {
+		t.Fatal("Failed to list address assets:", err.Error())
+	}
+	if assetIDs == nil || len(assetIDs) != 0 {
+		t.Fatal("Expected an empty list, got:", assetIDs)
+	}
+}
+
+func TestGetPortfolio(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// addr holds 300 of asset1 across two UTXOs and 1000 of asset2, and has
+	// spent all of asset3
+	asset1, asset2, asset3 := testID(101), testID(102), testID(103)
+	addr := testShortID(1)
+	insertTestOutput(t, sess, testID(1), 0, asset1, 100, addr, now)
+	insertTestOutput(t, sess, testID(1), 1, asset1, 200, addr, now)
+	insertTestOutput(t, sess, testID(2), 0, asset2, 1000, addr, now)
+	insertTestOutput(t, sess, testID(2), 1, asset3, 50, addr, now)
+	spendTestOutput(t, sess, testID(2).Prefix(1), testID(3))
+
+	portfolio, err := reader.GetPortfolio(context.Background(), addr)
+	if err != nil {
+		t.Fatal("Failed to get portfolio:", err.Error())
+	}
+	expected := map[models.StringID]models.TokenAmount{
+		models.ToStringID(asset1): "300",
+		models.ToStringID(asset2): "1000",
+	}
+	if !reflect.DeepEqual(portfolio, expected) {
+		t.Fatal("Incorrect portfolio:", portfolio)
+	}
+
+	portfolio, err = reader.GetPortfolio(context.Background(), testShortID(2))
+	if err != nil {
+		t.Fatal("Failed to get portfolio:", err.Error())
+	}
+	if portfolio == nil || len(portfolio) != 0 {
+		t.Fatal("Expected an empty portfolio, got:", portfolio)
+	}
+}
+
+func TestGetSpendingTransaction(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	insertTestTransaction(t, sess, testID(1), now)
+	insertTestTransaction(t, sess, testID(2), now.Add(time.Second))
+	insertTestOutput(t, sess, testID(1), 0, testID(101), 100, testShortID(1), now)
+	insertTestOutput(t, sess, testID(1), 1, testID(101), 200, testShortID(1), now)
+	spendTestOutput(t, sess, testID(1).Prefix(0), testID(2))
+
+	tx, err := reader.GetSpendingTransaction(context.Background(), testID(1).Prefix(0))
+	if err != nil {
+		t.Fatal("Failed to get spending transaction:", err.Error())
+	}
+	if tx == nil || tx.ID != models.ToStringID(testID(2)) {
+		t.Fatal("Incorrect spending transaction:", tx)
+	}
+
+	// An unspent output has no spending transaction
+	tx, err = reader.GetSpendingTransaction(context.Background(), testID(1).Prefix(1))
+	if err != nil {
+		t.Fatal("Failed to get spending transaction:", err.Error())
+	}
+	if tx != nil {
+		t.Fatal("Expected no spending transaction for unspent output, got:", tx.ID)
+	}
+
+	// A missing output is an error
+	if _, err = reader.GetSpendingTransaction(context.Background(), testID(1).Prefix(9)); err != ErrOutputNotFound {
+		t.Fatal("Expected ErrOutputNotFound, got:", err)
+	}
+
+	// An output imported by another indexed chain is spent by its transaction
+	otherChainID := testID(200).String()
+	insertTestOutput(t, sess, testID(1), 2, testID(101), 300, testShortID(1), now)
+	_, err = sess.
+		InsertInto("avm_transactions").
+		Pair("id", testID(3).String()).
+		Pair("chain_id", otherChainID).
+		Pair("type", models.TransactionTypePVMImport.String()).
+		Pair("canonical_serialization", []byte{}).
+		Pair("created_at", now.Add(time.Second)).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to insert transaction:", err.Error())
+	}
+	spendTestOutput(t, sess, testID(1).Prefix(2), testID(3))
+
+	tx, err = reader.GetSpendingTransaction(context.Background(), testID(1).Prefix(2))
+	if err != nil {
+		t.Fatal("Failed to get spending transaction:", err.Error())
+	}
+	if tx == nil || tx.ID != models.ToStringID(testID(3)) || tx.ChainID != models.StringID(otherChainID) {
+		t.Fatal("Incorrect cross-chain spending transaction:", tx)
+	}
+
+	// An output spent on a chain that isn't indexed reports the spender
+	insertTestOutput(t, sess, testID(1), 3, testID(101), 400, testShortID(1), now)
+	spendTestOutput(t, sess, testID(1).Prefix(3), testID(4))
+	_, err = reader.GetSpendingTransaction(context.Background(), testID(1).Prefix(3))
+	if !errors.Is(err, ErrSpentOnOtherChain) || !strings.Contains(err.Error(), testID(4).String()) {
+		t.Fatal("Expected ErrSpentOnOtherChain, got:", err)
+	}
+}
+
+func TestGetRawTransaction(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+	txBytes := []byte{0, 0, 1, 2, 3}
+
+	insertTestTransaction(t, sess, testID(1), now)
+	_, err := sess.
+		Update("avm_transactions").
+		Set("canonical_serialization", txBytes).
+		Where("id = ?", testID(1).String()).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to set serialization:", err.Error())
+	}
+
+	rawTx, err := reader.GetRawTransaction(context.Background(), testID(1))
+	if err != nil {
+		t.Fatal("Failed to get raw transaction:", err.Error())
+	}
+	if !bytes.Equal(rawTx, txBytes) {
+		t.Fatal("Incorrect raw transaction:", rawTx)
+	}
+
+	if _, err = reader.GetRawTransaction(context.Background(), testID(2)); err != ErrTransactionNotFound {
+		t.Fatal("Expected ErrTransactionNotFound, got:", err)
+	}
+
+	// The serialization is only listed when requested
+	for _, include := range []bool{false, true} {
+		txList, err := reader.ListTransactions(context.Background(), &params.ListTransactionsParams{IncludeSerialization: include})
+		if err != nil {
+			t.Fatal("Failed to list transactions:", err.Error())
+		}
+		if len(txList.Transactions) != 1 {
+			t.Fatal("Incorrect number of transactions:", len(txList.Transactions))
+		}
+		if serialization := txList.Transactions[0].CanonicalSerialization; bytes.Equal(serialization, txBytes) != include {
+			t.Fatalf("Incorrect serialization when include is %t: %v", include, serialization)
+		}
+	}
+}
+
+func TestInputCredentialsWithoutPublicKey(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// Both inputs of tx2 are signed but only addr1's public key was recovered
+	addr1, addr2 := testShortID(1), testShortID(2)
+	publicKey := bytes.Repeat([]byte{2}, 33)
+	insertTestTransaction(t, sess, testID(1), now)
+	insertTestTransaction(t, sess, testID(2), now.Add(time.Second))
+	insertTestOutput(t, sess, testID(1), 0, testID(101), 100, addr1, now)
+	insertTestOutput(t, sess, testID(1), 1, testID(101), 200, addr2, now)
+	for i, addr := range []ids.ShortID{addr1, addr2} {
+		spendTestOutput(t, sess, testID(1).Prefix(uint64(i)), testID(2))
+		_, err := sess.
+			Update("avm_output_addresses").
+			Set("redeeming_signature", []byte{byte(i + 1)}).
+			Where("address = ?", addr.String()).
+			Exec()
+		if err != nil {
+			t.Fatal("Failed to sign input:", err.Error())
+		}
+	}
+	_, err := sess.
+		InsertInto("addresses").
+		Pair("address", addr1.String()).
+		Pair("public_key", publicKey).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to insert address:", err.Error())
+	}
+
+	tx, err := reader.GetTransaction(context.Background(), testID(2))
+	if err != nil {
+		t.Fatal("Failed to get transaction:", err.Error())
+	}
+	if tx == nil || len(tx.Inputs) != 2 {
+		t.Fatal("Incorrect inputs:", tx)
+	}
+
+	for _, input := range tx.Inputs {
+		if len(input.Creds) != 1 {
+			t.Fatal("Incorrect number of credentials:", len(input.Creds))
+		}
+		cred := input.Creds[0]
+		if len(cred.Signature) != 1 {
+			t.Fatal("Incorrect signature:", cred.Signature)
+		}
+
+		switch cred.Address {
+		case models.Address(addr1.String()):
+			if !cred.HasPublicKey || !bytes.Equal(cred.PublicKey, publicKey) {
+				t.Fatal("Incorrect public key:", cred.PublicKey)
+			}
+		case models.Address(addr2.String()):
+			if cred.HasPublicKey || len(cred.PublicKey) != 0 {
+				t.Fatal("Expected no public key, got:", cred.PublicKey)
+			}
+		default:
+			t.Fatal("Incorrect address:", cred.Address)
+		}
+	}
+}
+
+func TestListAddressTransactions(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// addr1 receives in tx1, spends that output in tx2 and gets change back,
+	// and receives again in tx4. tx3 only involves addr2.
+	addr1, addr2 := testShortID(1), testShortID(2)
+	for i := byte(1); i <= 4; i++ {
+		insertTestTransaction(t, sess, testID(i), now.Add(time.Duration(i)*time.Second))
+	}
+	insertTestOutput(t, sess, testID(1), 0, testID(101), 100, addr1, now.Add(1*time.Second))
+	insertTestOutput(t, sess, testID(2), 0, testID(101), 60, addr2, now.Add(2*time.Second))
+	insertTestOutput(t, sess, testID(2), 1, testID(101), 40, addr1, now.Add(2*time.Second))
+	insertTestOutput(t, sess, testID(3), 0, testID(101), 10, addr2, now.Add(3*time.Second))
+	insertTestOutput(t, sess, testID(4), 0, testID(101), 5, addr1, now.Add(4*time.Second))
+	spendTestOutput(t, sess, testID(1).Prefix(0), testID(2))
+
+	for _, test := range []struct {
+		addr     ids.ShortID
+		offset   int
+		limit    int
+		expected []ids.ID
+	}{
+		{addr1, 0, 0, []ids.ID{testID(4), testID(2), testID(1)}},
+		{addr1, 0, 2, []ids.ID{testID(4), testID(2)}},
+		{addr1, 2, 2, []ids.ID{testID(1)}},
+		{addr2, 0, 0, []ids.ID{testID(3), testID(2)}},
+		{testShortID(3), 0, 0, []ids.ID{}},
+	} {
+		txList, err := reader.ListAddressTransactions(context.Background(), test.addr, &params.ListParams{Offset: test.offset, Limit: test.limit})
+		if err != nil {
+			t.Fatal("Failed to list address transactions:", err.Error())
+		}
+		if len(txList.Transactions) != len(test.expected) {
+			t.Fatalf("Incorrect number of transactions for %s: %d", test.addr.String(), len(txList.Transactions))
+		}
+		for i, id := range test.expected {
+			if !txList.Transactions[i].ID.Equals(models.ToStringID(id)) {
+				t.Fatalf("Incorrect transaction at %d: %s", i, txList.Transactions[i].ID)
+			}
+		}
+	}
+
+	// A full page requires counting, which must not count tx2 twice
+	txList, err := reader.ListAddressTransactions(context.Background(), addr1, &params.ListParams{Limit: 1})
+	if err != nil {
+		t.Fatal("Failed to list address transactions:", err.Error())
+	}
+	if txList.Count != 3 {
+		t.Fatal("Incorrect count:", txList.Count)
+	}
+}
+
+func TestTransactionFees(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// tx1 creates 1000 of the asset from nothing, tx2 spends it and outputs 900
+	// split between two addresses
+	tx1, tx2 := testID(1), testID(2)
+	asset := testID(101)
+	insertTestTransaction(t, sess, tx1, now)
+	insertTestTransaction(t, sess, tx2, now.Add(time.Second))
+	insertTestOutput(t, sess, tx1, 0, asset, 1000, testShortID(1), now)
+	insertTestOutput(t, sess, tx2, 0, asset, 600, testShortID(2), now.Add(time.Second))
+	insertTestOutput(t, sess, tx2, 1, asset, 300, testShortID(1), now.Add(time.Second))
+	spendTestOutput(t, sess, tx1.Prefix(0), tx2)
+
+	for txID, expected := range map[ids.ID]map[models.StringID]models.TokenAmount{
+		tx1: {},
+		tx2: {models.ToStringID(asset): "100"},
+	} {
+		tx, err := reader.GetTransaction(context.Background(), txID)
+		if err != nil {
+			t.Fatal("Failed to get transaction:", err.Error())
+		}
+		if tx == nil {
+			t.Fatal("Missing transaction:", txID.String())
+		}
+		if len(tx.Fees) != len(expected) {
+			t.Fatal("Incorrect number of fees:", tx.Fees)
+		}
+		for assetID, fee := range expected {
+			if tx.Fees[assetID].Amount != fee {
+				t.Fatal("Incorrect fee:", tx.Fees[assetID])
+			}
+		}
+	}
+}
+
+func TestTransactionTotalsWithoutDuplicates(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// tx1 creates 1000 of the asset owned by two addresses, and tx2 spends it
+	// with a signature from each, outputting 900
+	tx1, tx2 := testID(1), testID(2)
+	asset := testID(101)
+	addr1, addr2 := testShortID(1), testShortID(2)
+	insertTestTransaction(t, sess, tx1, now)
+	insertTestTransaction(t, sess, tx2, now.Add(time.Second))
+	insertTestOutput(t, sess, tx1, 0, asset, 1000, addr1, now)
+	_, err := sess.
+		InsertInto("avm_output_addresses").
+		Pair("output_id", tx1.Prefix(0).String()).
+		Pair("address", addr2.String()).
+		Pair("created_at", now).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to insert output address:", err.Error())
+	}
+	insertTestOutput(t, sess, tx2, 0, asset, 900, addr1, now.Add(time.Second))
+	spendTestOutput(t, sess, tx1.Prefix(0), tx2)
+	_, err = sess.
+		Update("avm_output_addresses").
+		Set("redeeming_signature", []byte{1}).
+		Where("output_id = ?", tx1.Prefix(0).String()).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to sign input:", err.Error())
+	}
+
+	// Both transactions are dressed together so tx1's output is loaded both as
+	// an output of tx1 and an input of tx2
+	txList, err := reader.ListTransactions(context.Background(), &params.ListTransactionsParams{})
+	if err != nil {
+		t.Fatal("Failed to list transactions:", err.Error())
+	}
+	if len(txList.Transactions) != 2 {
+		t.Fatal("Incorrect number of transactions:", len(txList.Transactions))
+	}
+
+	assetID := models.ToStringID(asset)
+	tx := txList.Transactions[0]
+	if tx.OutputCount != 1 || tx.OutputTotals[assetID].Amount != "1000" {
+		t.Fatalf("Incorrect outputs of tx1: %d, %v", tx.OutputCount, tx.OutputTotals)
+	}
+	if len(tx.Outputs[0].Addresses) != 2 {
+		t.Fatal("Incorrect addresses:", tx.Outputs[0].Addresses)
+	}
+
+	tx = txList.Transactions[1]
+	if tx.InputCount != 1 || tx.InputTotals[assetID].Amount != "1000" {
+		t.Fatalf("Incorrect inputs of tx2: %d, %v", tx.InputCount, tx.InputTotals)
+	}
+	if tx.OutputTotals[assetID].Amount != "900" || tx.Fees[assetID].Amount != "100" {
+		t.Fatalf("Incorrect totals of tx2: %v, %v", tx.OutputTotals, tx.Fees)
+	}
+	if len(tx.Inputs[0].Creds) != 2 {
+		t.Fatal("Incorrect number of credentials:", len(tx.Inputs[0].Creds))
+	}
+}
+
+func TestLoadTransactionOutputsParallel(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	txIDs := insertTestTransactionChain(t, sess, 20)
+
+	parallel, err := loadTransactionOutputs(context.Background(), sess, txIDs, 0)
+	if err != nil {
+		t.Fatal("Failed to load outputs:", err.Error())
+	}
+	sequential, err := loadTransactionOutputsSequentially(context.Background(), sess, txIDs)
+	if err != nil {
+		t.Fatal("Failed to load outputs:", err.Error())
+	}
+
+	// Each transaction creates one output and all but the first spend one
+	if len(parallel) != 39 {
+		t.Fatal("Incorrect number of records:", len(parallel))
+	}
+	if !reflect.DeepEqual(parallel, sequential) {
+		t.Fatal("Parallel and sequential loads differ")
+	}
+}
+
+func TestInQueryBatchSize(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	txIDs := insertTestTransactionChain(t, sess, 7)
+
+	addressQueries := 0
+	batchedReader := NewReader(reader.conns, testXChainID.String(),
+		WithInQueryBatchSize(2),
+		WithQueryHook(func(_, sql string, _ []interface{}, _ time.Duration) {
+			if strings.Contains(sql, "avm_output_addresses.output_id IN") {
+				addressQueries++
+			}
+		}))
+
+	// Every output has the address it was created with
+	outputList, err := batchedReader.ListOutputs(context.Background(), &params.ListOutputsParams{})
+	if err != nil {
+		t.Fatal("Failed to list outputs:", err.Error())
+	}
+	if len(outputList.Outputs) != 7 {
+		t.Fatal("Incorrect number of outputs:", len(outputList.Outputs))
+	}
+	for _, output := range outputList.Outputs {
+		if len(output.Addresses) != 1 {
+			t.Fatal("Incorrect number of addresses for output:", output.ID, len(output.Addresses))
+		}
+	}
+	if addressQueries != 4 {
+		t.Fatal("Incorrect number of address queries:", addressQueries)
+	}
+
+	// Batched loads match an unbatched one
+	batched, err := loadTransactionOutputs(context.Background(), sess, txIDs, 2)
+	if err != nil {
+		t.Fatal("Failed to load outputs:", err.Error())
+	}
+	unbatched, err := loadTransactionOutputs(context.Background(), sess, txIDs, 0)
+	if err != nil {
+		t.Fatal("Failed to load outputs:", err.Error())
+	}
+	// Rows of an IN query aren't ordered so compare them by ID
+	for _, records := range [][]*transactionOutputRecord{batched, unbatched} {
+		records := records
+		sort.Slice(records, func(i, j int) bool { return records[i].Output.ID < records[j].Output.ID })
+	}
+	if len(batched) != 13 || !reflect.DeepEqual(batched, unbatched) {
+		t.Fatal("Batched and unbatched loads differ")
+	}
+
+	txList, err := batchedReader.ListTransactions(context.Background(), &params.ListTransactionsParams{})
+	if err != nil {
+		t.Fatal("Failed to list transactions:", err.Error())
+	}
+	if len(txList.Transactions) != 7 {
+		t.Fatal("Incorrect number of transactions:", len(txList.Transactions))
+	}
+	inputs := 0
+	for _, tx := range txList.Transactions {
+		if len(tx.Outputs) != 1 {
+			t.Fatal("Incorrect number of outputs for transaction:", tx.ID, len(tx.Outputs))
+		}
+		inputs += len(tx.Inputs)
+	}
+	if inputs != 6 {
+		t.Fatal("Incorrect number of inputs:", inputs)
+	}
+}
+
+func TestDressAddressesBatched(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// 5 addresses each receive two assets, and spend one of the outputs
+	for i := byte(1); i <= 5; i++ {
+		txID := testID(i)
+		insertTestOutput(t, sess, txID, 0, testID(101), 100*uint64(i), testShortID(i), now)
+		insertTestOutput(t, sess, txID, 1, testID(102), 10*uint64(i), testShortID(i), now)
+		insertTestOutput(t, sess, txID, 2, testID(101), uint64(i), testShortID(i), now)
+		spendTestOutput(t, sess, txID.Prefix(0), testID(10+i))
+	}
+
+	groupedQueries := 0
+	batchedReader := NewReader(reader.conns, testXChainID.String(),
+		WithInQueryBatchSize(2),
+		WithQueryHook(func(_, sql string, _ []interface{}, _ time.Duration) {
+			if strings.Contains(sql, "GROUP BY avm_output_addresses.address") {
+				groupedQueries++
+			}
+		}))
+	unbatchedReader := NewReader(reader.conns, testXChainID.String(), WithInQueryBatchSize(0))
+
+	batched, err := batchedReader.ListAddresses(context.Background(), &params.ListAddressesParams{})
+	if err != nil {
+		t.Fatal("Failed to list addresses:", err.Error())
+	}
+	unbatched, err := unbatchedReader.ListAddresses(context.Background(), &params.ListAddressesParams{})
+	if err != nil {
+		t.Fatal("Failed to list addresses:", err.Error())
+	}
+
+	if groupedQueries != 3 {
+		t.Fatal("Incorrect number of grouped queries:", groupedQueries)
+	}
+	if len(batched.Addresses) != 5 || !reflect.DeepEqual(batched, unbatched) {
+		t.Fatal("Batched and unbatched addresses differ")
+	}
+	for _, addr := range batched.Addresses {
+		if len(addr.Assets) != 2 || addr.Assets[models.ToStringID(testID(101))].UTXOCount != 1 {
+			t.Fatal("Incorrect assets for address:", addr.Address, addr.Assets)
+		}
+	}
+}
+
+func BenchmarkDressTransactions(b *testing.B) {
+	_, reader, closeFn := newTestIndex(b, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	txIDs := insertTestTransactionChain(b, sess, 500)
+
+	for _, bench := range []struct {
+		name string
+		load func(context.Context, dbr.SessionRunner, []models.StringID) ([]*transactionOutputRecord, error)
+	}{
+		{"sequential", loadTransactionOutputsSequentially},
+		{"parallel", func(ctx context.Context, dbRunner dbr.SessionRunner, txIDs []models.StringID) ([]*transactionOutputRecord, error) {
+			return loadTransactionOutputs(ctx, dbRunner, txIDs, 0)
+		}},
+	} {
+		bench := bench
+		b.Run(bench.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := bench.load(context.Background(), sess, txIDs); err != nil {
+					b.Fatal("Failed to load outputs:", err.Error())
+				}
+			}
+		})
+	}
+}
+
+// insertTestTransactionChain inserts n transactions that each spend the output
+// of the previous one
+func insertTestTransactionChain(t testing.TB, sess *dbr.Session, n int) []models.StringID {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	txIDs := make([]models.StringID, n)
+	var prevTxID ids.ID
+	for i := 0; i < n; i++ {
+		txID := testID(byte(i / 256)).Prefix(uint64(i))
+		createdAt := start.Add(time.Duration(i) * time.Second)
+		insertTestTransaction(t, sess, txID, createdAt)
+		insertTestOutput(t, sess, txID, 0, testID(101), uint64(n-i), testShortID(byte(i%50)), createdAt)
+		if i > 0 {
+			spendTestOutput(t, sess, prevTxID.Prefix(0), txID)
+		}
+		txIDs[i] = models.ToStringID(txID)
+		prevTxID = txID
+	}
+	return txIDs
+}
+
+// loadTransactionOutputsSequentially is the sequential equivalent of
+// loadTransactionOutputs
+func loadTransactionOutputsSequentially(ctx context.Context, dbRunner dbr.SessionRunner, txIDs []models.StringID) ([]*transactionOutputRecord, error) {
+	var outputs, inputs []*transactionOutputRecord
+	_, err := selectOutputs(dbRunner).
+		Where("avm_outputs.transaction_id IN ?", txIDs).
+		LoadContext(ctx, &outputs)
+	if err != nil {
+		return nil, err
+	}
+	_, err = selectOutputs(dbRunner).
+		Where("avm_outputs.redeeming_transaction_id IN ?", txIDs).
+		LoadContext(ctx, &inputs)
+	if err != nil {
+		return nil, err
+	}
+	return append(outputs, inputs...), nil
+}
+
+func TestListTransactionsLight(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// tx2 spends tx1's output and creates two outputs of its own
+	tx1, tx2 := testID(1), testID(2)
+	asset := testID(101)
+	insertTestTransaction(t, sess, tx1, now)
+	insertTestTransaction(t, sess, tx2, now.Add(time.Second))
+	insertTestOutput(t, sess, tx1, 0, asset, 1000, testShortID(1), now)
+	insertTestOutput(t, sess, tx2, 0, asset, 600, testShortID(2), now.Add(time.Second))
+	insertTestOutput(t, sess, tx2, 1, asset, 300, testShortID(1), now.Add(time.Second))
+	spendTestOutput(t, sess, tx1.Prefix(0), tx2)
+
+	for _, light := range []bool{false, true} {
+		txList, err := reader.ListTransactions(context.Background(), &params.ListTransactionsParams{
+			ListParams: params.ListParams{Limit: 1, Offset: 1},
+			Light:      light,
+		})
+		if err != nil {
+			t.Fatal("Failed to list transactions:", err.Error())
+		}
+		if len(txList.Transactions) != 1 {
+			t.Fatal("Incorrect number of transactions:", len(txList.Transactions))
+		}
+
+		tx := txList.Transactions[0]
+		if !tx.ID.Equals(models.ToStringID(tx2)) {
+			t.Fatal("Incorrect transaction:", tx.ID)
+		}
+		if tx.InputCount != 1 || tx.OutputCount != 2 {
+			t.Fatalf("Incorrect counts with light=%t: %d inputs, %d outputs", light, tx.InputCount, tx.OutputCount)
+		}
+		if tx.InputTotals[models.ToStringID(asset)].Amount != "1000" || tx.OutputTotals[models.ToStringID(asset)].Amount != "900" {
+			t.Fatalf("Incorrect totals with light=%t: %v, %v", light, tx.InputTotals, tx.OutputTotals)
+		}
+
+		if light && (tx.Inputs != nil || tx.Outputs != nil) {
+			t.Fatal("Light transaction included inputs or outputs")
+		}
+		if !light && (len(tx.Inputs) != tx.InputCount || len(tx.Outputs) != tx.OutputCount) {
+			t.Fatal("Counts don't match inputs and outputs")
+		}
+	}
+}
+
+func TestTransactionOutputOrder(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// tx3 spends two outputs of tx1 and one of tx2, and creates five outputs
+	// inserted out of order
+	tx1, tx2, tx3 := testID(1), testID(2), testID(3)
+	asset := testID(101)
+	for _, txID := range []ids.ID{tx1, tx2, tx3} {
+		insertTestTransaction(t, sess, txID, now)
+	}
+	for _, spent := range []struct {
+		txID ids.ID
+		idx  uint64
+	}{{tx1, 1}, {tx2, 0}, {tx1, 0}} {
+		insertTestOutput(t, sess, spent.txID, spent.idx, asset, 10, testShortID(1), now)
+		spendTestOutput(t, sess, spent.txID.Prefix(spent.idx), tx3)
+	}
+	for _, idx := range []uint64{3, 0, 4, 1, 2} {
+		insertTestOutput(t, sess, tx3, idx, asset, 1, testShortID(1), now)
+	}
+
+	// Inputs are ordered by the ID of the transaction they spend from
+	expectedInputs := []ids.ID{tx1.Prefix(0), tx1.Prefix(1), tx2.Prefix(0)}
+	if tx2.String() < tx1.String() {
+		expectedInputs = []ids.ID{tx2.Prefix(0), tx1.Prefix(0), tx1.Prefix(1)}
+	}
+
+	// The order must hold on every request, not by chance
+	for i := 0; i < 5; i++ {
+		tx, err := reader.GetTransaction(context.Background(), tx3)
+		if err != nil {
+			t.Fatal("Failed to get transaction:", err.Error())
+		}
+		if len(tx.Outputs) != 5 || len(tx.Inputs) != len(expectedInputs) {
+			t.Fatalf("Incorrect number of inputs or outputs: %d, %d", len(tx.Inputs), len(tx.Outputs))
+		}
+		for j, output := range tx.Outputs {
+			if output.OutputIndex != uint64(j) {
+				t.Fatalf("Incorrect output at %d: %d", j, output.OutputIndex)
+			}
+		}
+		for j, input := range tx.Inputs {
+			if input.Output.ID != models.ToStringID(expectedInputs[j]) {
+				t.Fatalf("Incorrect input at %d: %s", j, input.Output.ID)
+			}
+		}
+	}
+}
+
+func TestListTransactionsIncludeAssets(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// tx1 and tx2 both output asset1, which is indexed, and tx2 also outputs
+	// asset2, which isn't
+	asset1, asset2 := testID(101), testID(102)
+	insertTestAsset(t, sess, asset1, testXChainID.String(), 9, now)
+	insertTestTransaction(t, sess, testID(1), now)
+	insertTestTransaction(t, sess, testID(2), now.Add(time.Second))
+	insertTestOutput(t, sess, testID(1), 0, asset1, 100, testShortID(1), now)
+	insertTestOutput(t, sess, testID(2), 0, asset1, 200, testShortID(1), now.Add(time.Second))
+	insertTestOutput(t, sess, testID(2), 1, asset2, 300, testShortID(1), now.Add(time.Second))
+
+	for _, includeAssets := range []bool{false, true} {
+		txList, err := reader.ListTransactions(context.Background(), &params.ListTransactionsParams{IncludeAssets: includeAssets})
+		if err != nil {
+			t.Fatal("Failed to list transactions:", err.Error())
+		}
+		if len(txList.Transactions) != 2 {
+			t.Fatal("Incorrect number of transactions:", len(txList.Transactions))
+		}
+
+		for _, tx := range txList.Transactions {
+			if !includeAssets {
+				if tx.Assets != nil {
+					t.Fatal("Unexpected assets:", tx.Assets)
+				}
+				continue
+			}
+			if len(tx.Assets) != 1 {
+				t.Fatal("Incorrect number of assets:", len(tx.Assets))
+			}
+			asset, ok := tx.Assets[models.ToStringID(asset1)]
+			if !ok || asset.Symbol != "TEST" || asset.Denomination != 9 {
+				t.Fatal("Incorrect asset:", asset)
+			}
+			if _, ok := tx.OutputTotals[models.ToStringID(asset1)]; !ok {
+				t.Fatal("Asset isn't in the totals")
+			}
+		}
+	}
+}
+
+func TestListTransactionsRecipientAddress(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+	addr1, addr2 := testShortID(1), testShortID(2)
+	asset := testID(101)
+
+	// tx1 sends to addr1. tx2 spends that to send to addr2 with change back to
+	// addr1. tx3 spends addr2's output to send to addr1.
+	tx1, tx2, tx3 := testID(1), testID(2), testID(3)
+	insertTestTransaction(t, sess, tx1, now)
+	insertTestTransaction(t, sess, tx2, now.Add(time.Second))
+	insertTestTransaction(t, sess, tx3, now.Add(2*time.Second))
+	insertTestOutput(t, sess, tx1, 0, asset, 1000, addr1, now)
+	insertTestOutput(t, sess, tx2, 0, asset, 600, addr2, now.Add(time.Second))
+	insertTestOutput(t, sess, tx2, 1, asset, 400, addr1, now.Add(time.Second))
+	insertTestOutput(t, sess, tx3, 0, asset, 600, addr1, now.Add(2*time.Second))
+	spendTestOutput(t, sess, tx1.Prefix(0), tx2)
+	spendTestOutput(t, sess, tx2.Prefix(0), tx3)
+
+	for _, test := range []struct {
+		addr     ids.ShortID
+		expected []ids.ID
+	}{
+		{addr1, []ids.ID{tx1, tx3}},
+		{addr2, []ids.ID{tx2}},
+		{testShortID(3), nil},
+	} {
+		addr := test.addr
+		txList, err := reader.ListTransactions(context.Background(), &params.ListTransactionsParams{RecipientAddress: &addr})
+		if err != nil {
+			t.Fatal("Failed to list transactions:", err.Error())
+		}
+		assertTransactionIDs(t, txList.Transactions, test.expected)
+		if txList.Count != uint64(len(test.expected)) {
+			t.Fatal("Incorrect count:", txList.Count)
+		}
+	}
+}
+
+func TestListTransactionsMinHeight(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// Heights 1 through 6, created in the reverse order so that sorting by
+	// time would differ
+	for i := 1; i <= 6; i++ {
+		txID := testID(byte(i))
+		insertTestTransaction(t, sess, txID, now.Add(-time.Duration(i)*time.Second))
+		_, err := sess.
+			Update("avm_transactions").
+			Set("height", i).
+			Where("id = ?", txID.String()).
+			Exec()
+		if err != nil {
+			t.Fatal("Failed to set height:", err.Error())
+		}
+	}
+
+	// Sync from the middle in chunks of two
+	minHeight := uint64(2)
+	for _, expected := range [][]uint64{{3, 4}, {5, 6}, {}} {
+		p := &params.ListTransactionsParams{MinHeight: &minHeight}
+		p.Limit = 2
+
+		txList, err := reader.ListTransactions(context.Background(), p)
+		if err != nil {
+			t.Fatal("Failed to list transactions:", err.Error())
+		}
+		if len(txList.Transactions) != len(expected) {
+			t.Fatalf("Incorrect number of transactions after %d: %d", minHeight, len(txList.Transactions))
+		}
+		for i, tx := range txList.Transactions {
+			if tx.Height != expected[i] || tx.ID != models.ToStringID(testID(byte(expected[i]))) {
+				t.Fatalf("Incorrect transaction after %d: %s at height %d", minHeight, tx.ID, tx.Height)
+			}
+		}
+		if len(txList.Transactions) > 0 {
+			minHeight = txList.Transactions[len(txList.Transactions)-1].Height
+		}
+	}
+
+	// Syncing by height can't be sorted any other way
+	p := &params.ListTransactionsParams{MinHeight: &minHeight, Sort: params.TransactionSortTimestampDesc}
+	if _, err := reader.ListTransactions(context.Background(), p); !errors.Is(err, params.ErrMinHeightWithSort) {
+		t.Fatal("Expected sorting with a minHeight to fail:", err)
+	}
+}
+
+func TestListTransactionsByTime(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	start := time.Now().UTC().Truncate(time.Hour)
+
+	// tx1 and tx2 are in the first hour and tx3 in the second. tx1 and tx3
+	// match the query.
+	tx1, tx2, tx3 := testID(1), testID(2), testID(3)
+	insertTestTransaction(t, sess, tx1, start)
+	insertTestTransaction(t, sess, tx2, start.Add(time.Minute))
+	insertTestTransaction(t, sess, tx3, start.Add(time.Hour))
+	_, err := sess.
+		Update("avm_transactions").
+		Set("memo", []byte("test memo")).
+		Where("id IN ?", []string{tx1.String(), tx3.String()}).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to set memo:", err.Error())
+	}
+
+	for _, test := range []struct {
+		startTime time.Time
+		endTime   time.Time
+		query     string
+		expected  []ids.ID
+	}{
+		{time.Time{}, time.Time{}, "", []ids.ID{tx1, tx2, tx3}},
+		{start, start.Add(time.Minute), "", []ids.ID{tx1, tx2}},
+		{start.Add(30 * time.Minute), time.Time{}, "", []ids.ID{tx3}},
+		{time.Time{}, start.Add(30 * time.Minute), "", []ids.ID{tx1, tx2}},
+		{time.Time{}, time.Time{}, "test", []ids.ID{tx1, tx3}},
+		{start.Add(30 * time.Minute), time.Time{}, "test", []ids.ID{tx3}},
+		{start.Add(2 * time.Hour), time.Time{}, "", nil},
+	} {
+		// A limit of 1 makes the count come from the count query
+		p := &params.ListTransactionsParams{
+			ListParams: params.ListParams{Limit: 1},
+			StartTime:  test.startTime,
+			EndTime:    test.endTime,
+			Query:      test.query,
+		}
+		txList, err := reader.ListTransactions(context.Background(), p)
+		if err != nil {
+			t.Fatal("Failed to list transactions:", err.Error())
+		}
+		if txList.Count != uint64(len(test.expected)) {
+			t.Fatalf("Incorrect count from %s to %s for %q: %d", test.startTime, test.endTime, test.query, txList.Count)
+		}
+
+		p = &params.ListTransactionsParams{StartTime: test.startTime, EndTime: test.endTime, Query: test.query}
+		txList, err = reader.ListTransactions(context.Background(), p)
+		if err != nil {
+			t.Fatal("Failed to list transactions:", err.Error())
+		}
+		if len(txList.Transactions) != len(test.expected) {
+			t.Fatalf("Incorrect number of transactions from %s to %s for %q: %d", test.startTime, test.endTime, test.query, len(txList.Transactions))
+		}
+		for i, tx := range txList.Transactions {
+			if !tx.ID.Equals(models.ToStringID(test.expected[i])) {
+				t.Fatalf("Incorrect transaction at %d: %s", i, tx.ID)
+			}
+		}
+	}
+
+	// Bounds within the same hour aren't cached together
+	p1 := &params.ListTransactionsParams{StartTime: start.Add(time.Minute)}
+	p2 := &params.ListTransactionsParams{StartTime: start.Add(2 * time.Minute)}
+	if strings.Join(p1.CacheKey(), "|") == strings.Join(p2.CacheKey(), "|") {
+		t.Fatal("Different start times have the same cache key")
+	}
+}
+
+func TestListTransactionsSortByVolume(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// tx2 is the largest and tx1 the smallest, which differs from both the
+	// timestamp order and the lexical order of the amounts
+	tx1, tx2, tx3 := testID(1), testID(2), testID(3)
+	insertTestTransaction(t, sess, tx1, now)
+	insertTestTransaction(t, sess, tx2, now.Add(time.Second))
+	insertTestTransaction(t, sess, tx3, now.Add(2*time.Second))
+	insertTestOutput(t, sess, tx1, 0, testID(101), 9, testShortID(1), now)
+	insertTestOutput(t, sess, tx2, 0, testID(101), 60, testShortID(1), now.Add(time.Second))
+	insertTestOutput(t, sess, tx2, 1, testID(102), 40, testShortID(1), now.Add(time.Second))
+	insertTestOutput(t, sess, tx3, 0, testID(101), 20, testShortID(1), now.Add(2*time.Second))
+
+	// Outputs of other chains aren't summed
+	insertTestOutput(t, sess, tx1, 1, testID(101), 1000, testShortID(1), now)
+	_, err := sess.
+		Update("avm_outputs").
+		Set("chain_id", testID(200).String()).
+		Where("id = ?", tx1.Prefix(1).String()).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to update output:", err.Error())
+	}
+
+	for sort, expected := range map[params.TransactionSort][]ids.ID{
+		params.TransactionSortVolumeDesc: {tx2, tx3, tx1},
+		params.TransactionSortVolumeAsc:  {tx1, tx3, tx2},
+	} {
+		txList, err := reader.ListTransactions(context.Background(), &params.ListTransactionsParams{Sort: sort})
+		if err != nil {
+			t.Fatal("Failed to list transactions:", err.Error())
+		}
+		if len(txList.Transactions) != len(expected) {
+			t.Fatal("Incorrect number of transactions:", len(txList.Transactions))
+		}
+		for i, tx := range txList.Transactions {
+			if !tx.ID.Equals(models.ToStringID(expected[i])) {
+				t.Fatalf("Incorrect transaction at %d for %s: %s", i, sort, tx.ID)
+			}
+		}
+	}
+}
+
+func TestListTransactionsSortTieBreaking(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// tx1 and tx2 share a timestamp and a volume so only their IDs order them
+	tx1, tx2, tx3 := testID(1), testID(2), testID(3)
+	insertTestTransaction(t, sess, tx2, now)
+	insertTestTransaction(t, sess, tx1, now)
+	insertTestTransaction(t, sess, tx3, now.Add(time.Second))
+	for _, txID := range []ids.ID{tx1, tx2, tx3} {
+		insertTestOutput(t, sess, txID, 0, testID(101), 10, testShortID(1), now)
+	}
+
+	first, second := tx1, tx2
+	if tx2.String() < tx1.String() {
+		first, second = tx2, tx1
+	}
+
+	ctx := context.Background()
+	for sort, expected := range map[params.TransactionSort][]ids.ID{
+		params.TransactionSortTimestampAsc:  {first, second, tx3},
+		params.TransactionSortTimestampDesc: {tx3, second, first},
+		params.TransactionSortVolumeAsc:     {first, second, tx3},
+		params.TransactionSortVolumeDesc:    {first, second, tx3},
+	} {
+		// Paging one transaction at a time must visit them in the same order
+		for i, expectedID := range expected {
+			p := &params.ListTransactionsParams{Sort: sort}
+			p.Limit = 1
+			p.Offset = i
+			txList, err := reader.ListTransactions(ctx, p)
+			if err != nil {
+				t.Fatal("Failed to list transactions:", err.Error())
+			}
+			if len(txList.Transactions) != 1 {
+				t.Fatal("Incorrect number of transactions:", len(txList.Transactions))
+			}
+			if !txList.Transactions[0].ID.Equals(models.ToStringID(expectedID)) {
+				t.Fatalf("Incorrect transaction at %d for %s: %s", i, sort, txList.Transactions[0].ID)
+			}
+		}
+	}
+}
+
+func TestListTransactionsSortWithQuery(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// The volumes order the matching transactions differently than their
+	// timestamps, and tx4 doesn't match the query
+	tx1, tx2, tx3, tx4 := testID(1), testID(2), testID(3), testID(4)
+	for i, txID := range []ids.ID{tx1, tx2, tx3, tx4} {
+		insertTestTransaction(t, sess, txID, now.Add(time.Duration(i)*time.Second))
+	}
+	for txID, amount := range map[ids.ID]uint64{tx1: 20, tx2: 30, tx3: 10, tx4: 40} {
+		insertTestOutput(t, sess, txID, 0, testID(101), amount, testShortID(1), now)
+	}
+	_, err := sess.
+		Update("avm_transactions").
+		Set("memo", []byte("payroll")).
+		Where("id IN ?", []string{tx1.String(), tx2.String(), tx3.String()}).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to set memo:", err.Error())
+	}
+
+	// A query doesn't disable sorting, so search results page stably
+	for sort, expected := range map[params.TransactionSort][]ids.ID{
+		params.TransactionSortTimestampAsc:  {tx1, tx2, tx3},
+		params.TransactionSortTimestampDesc: {tx3, tx2, tx1},
+		params.TransactionSortVolumeAsc:     {tx3, tx1, tx2},
+		params.TransactionSortVolumeDesc:    {tx2, tx1, tx3},
+	} {
+		p := &params.ListTransactionsParams{Query: "payroll", Sort: sort}
+		txList, err := reader.ListTransactions(context.Background(), p)
+		if err != nil {
+			t.Fatal("Failed to list transactions:", err.Error())
+		}
+		if len(txList.Transactions) != len(expected) {
+			t.Fatalf("Incorrect number of transactions for %s: %d", sort, len(txList.Transactions))
+		}
+		for i, tx := range txList.Transactions {
+			if !tx.ID.Equals(models.ToStringID(expected[i])) {
+				t.Fatalf("Incorrect transaction at %d for %s: %s", i, sort, tx.ID)
+			}
+		}
+	}
+}
+
+func TestGetTransactions(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// tx2 spends an output of tx0, which isn't requested
+	tx0, tx1, tx2, missing := testID(1), testID(2), testID(3), testID(4)
+	insertTestTransaction(t, sess, tx0, now)
+	insertTestTransaction(t, sess, tx1, now)
+	insertTestTransaction(t, sess, tx2, now.Add(time.Second))
+	insertTestOutput(t, sess, tx0, 0, testID(101), 100, testShortID(1), now)
+	insertTestOutput(t, sess, tx1, 0, testID(101), 50, testShortID(1), now)
+	insertTestOutput(t, sess, tx2, 0, testID(101), 90, testShortID(1), now.Add(time.Second))
+	spendTestOutput(t, sess, tx0.Prefix(0), tx2)
+
+	txs, err := reader.GetTransactions(context.Background(), []ids.ID{tx2, missing, tx1})
+	if err != nil {
+		t.Fatal("Failed to get transactions:", err.Error())
+	}
+	if len(txs) != 3 {
+		t.Fatal("Incorrect number of transactions:", len(txs))
+	}
+	if txs[0] == nil || !txs[0].ID.Equals(models.ToStringID(tx2)) {
+		t.Fatal("Incorrect first transaction:", txs[0])
+	}
+	if txs[1] != nil {
+		t.Fatal("Expected missing transaction to be nil:", txs[1].ID)
+	}
+	if txs[2] == nil || !txs[2].ID.Equals(models.ToStringID(tx1)) {
+		t.Fatal("Incorrect last transaction:", txs[2])
+	}
+
+	// Totals must be kept separate for each transaction
+	assetID := models.ToStringID(testID(101))
+	if txs[0].InputTotals[assetID].Amount != "100" || txs[0].OutputTotals[assetID].Amount != "90" {
+		t.Fatal("Incorrect totals:", txs[0].InputTotals, txs[0].OutputTotals)
+	}
+	if len(txs[2].InputTotals) != 0 || txs[2].OutputTotals[assetID].Amount != "50" {
+		t.Fatal("Incorrect totals:", txs[2].InputTotals, txs[2].OutputTotals)
+	}
+}
+
+func TestGetOutputs(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	insertTestTransaction(t, sess, testID(1), now)
+	insertTestOutput(t, sess, testID(1), 0, testID(101), 100, testShortID(1), now)
+	insertTestOutput(t, sess, testID(1), 1, testID(101), 50, testShortID(2), now)
+
+	out0, out1, missing := testID(1).Prefix(0), testID(1).Prefix(1), testID(2).Prefix(0)
+	outputs, err := reader.GetOutputs(context.Background(), []ids.ID{out1, missing, out0})
+	if err != nil {
+		t.Fatal("Failed to get outputs:", err.Error())
+	}
+	if len(outputs) != 2 {
+		t.Fatal("Incorrect number of outputs:", len(outputs))
+	}
+	if _, ok := outputs[models.ToStringID(missing)]; ok {
+		t.Fatal("Expected missing output to be omitted")
+	}
+	for id, expected := range map[ids.ID]struct {
+		amount string
+		addr   ids.ShortID
+	}{out0: {"100", testShortID(1)}, out1: {"50", testShortID(2)}} {
+		output := outputs[models.ToStringID(id)]
+		if output == nil || string(output.Amount) != expected.amount {
+			t.Fatal("Incorrect output:", output)
+		}
+		if len(output.Addresses) != 1 || output.Addresses[0] != models.ToAddress(expected.addr) {
+			t.Fatal("Incorrect addresses:", output.Addresses)
+		}
+	}
+
+	// Batches of IDs are merged into the same result
+	outputQueries := 0
+	batchedReader := NewReader(reader.conns, testXChainID.String(),
+		WithInQueryBatchSize(1),
+		WithQueryHook(func(_, sql string, _ []interface{}, _ time.Duration) {
+			if strings.Contains(sql, "avm_outputs.id IN") {
+				outputQueries++
+			}
+		}))
+	batched, err := batchedReader.GetOutputs(context.Background(), []ids.ID{out1, missing, out0})
+	if err != nil {
+		t.Fatal("Failed to get outputs:", err.Error())
+	}
+	if outputQueries != 3 {
+		t.Fatal("Incorrect number of output queries:", outputQueries)
+	}
+	if len(batched) != 2 || batched[models.ToStringID(out0)] == nil || batched[models.ToStringID(out1)] == nil {
+		t.Fatal("Incorrect batched outputs:", batched)
+	}
+
+	// No IDs need no query
+	outputs, err = reader.GetOutputs(context.Background(), nil)
+	if err != nil || len(outputs) != 0 {
+		t.Fatal("Expected no outputs:", outputs, err)
+	}
+}
+
+func TestFirstTransactionTimeCache(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+	chainIDs := []string{testXChainID.String()}
+
+	assertFirstTransactionTime := func(expected time.Time) {
+		ts, err := reader.getFirstTransactionTime(context.Background(), chainIDs)
+		if err != nil {
+			t.Fatal("Failed to get first transaction time:", err.Error())
+		}
+		if !ts.Equal(expected) {
+			t.Fatal("Incorrect first transaction time:", ts)
+		}
+	}
+
+	insertTestTransaction(t, sess, testID(1), now)
+	assertFirstTransactionTime(now)
+
+	// An earlier transaction isn't seen until the cache is invalidated, which
+	// shows that the DB is not queried again within the TTL
+	insertTestTransaction(t, sess, testID(2), now.Add(-time.Hour))
+	assertFirstTransactionTime(now)
+
+	reader.InvalidateFirstTransactionTime()
+	assertFirstTransactionTime(now.Add(-time.Hour))
+
+	// Without a TTL every call goes to the DB
+	uncachedReader := NewReader(reader.conns, testXChainID.String(), WithFirstTransactionTimeTTL(0))
+	insertTestTransaction(t, sess, testID(3), now.Add(-2*time.Hour))
+	ts, err := uncachedReader.getFirstTransactionTime(context.Background(), chainIDs)
+	if err != nil {
+		t.Fatal("Failed to get first transaction time:", err.Error())
+	}
+	if !ts.Equal(now.Add(-2 * time.Hour)) {
+		t.Fatal("Incorrect first transaction time:", ts)
+	}
+
+	// Several chains are cached together regardless of their order, with the
+	// earliest transaction of any of them
+	otherChainID := testID(200).String()
+	_, err = sess.
+		InsertInto("avm_transactions").
+		Pair("id", testID(4).String()).
+		Pair("chain_id", otherChainID).
+		Pair("type", models.TransactionTypeBase.String()).
+		Pair("canonical_serialization", []byte{}).
+		Pair("created_at", now.Add(-3*time.Hour)).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to insert transaction:", err.Error())
+	}
+
+	dbHits := 0
+	countingReader := NewReader(reader.conns, testXChainID.String(),
+		WithQueryHook(func(_, sql string, _ []interface{}, _ time.Duration) {
+			if strings.Contains(sql, "MIN(created_at)") {
+				dbHits++
+			}
+		}))
+	for _, chainIDs := range [][]string{
+		{testXChainID.String(), otherChainID},
+		{otherChainID, testXChainID.String()},
+	} {
+		ts, err = countingReader.getFirstTransactionTime(context.Background(), chainIDs)
+		if err != nil {
+			t.Fatal("Failed to get first transaction time:", err.Error())
+		}
+		if !ts.Equal(now.Add(-3 * time.Hour)) {
+			t.Fatal("Incorrect first transaction time:", ts)
+		}
+	}
+	if dbHits != 1 {
+		t.Fatal("Incorrect number of DB hits:", dbHits)
+	}
+}
+
+func TestSessionNamePrefix(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sessionName := func(r *Reader) string {
+		job, ok := r.newSession("list_assets").EventReceiver.(*health.Job)
+		if !ok {
+			t.Fatal("Session not instrumented by a job")
+		}
+		return job.JobName
+	}
+
+	if name := sessionName(reader); name != "list_assets" {
+		t.Fatal("Incorrect session name without prefix:", name)
+	}
+
+	prefixedReader := NewReader(reader.conns, testXChainID.String(), WithSessionNamePrefix("api-"))
+	if name := sessionName(prefixedReader); name != "api-list_assets" {
+		t.Fatal("Incorrect session name with prefix:", name)
+	}
+
+	// Queries still run with the prefix
+	if _, err := prefixedReader.ListAssets(context.Background(), &params.ListAssetsParams{}); err != nil {
+		t.Fatal("Failed to list assets:", err.Error())
+	}
+}
+
+func TestQueryHook(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	type query struct {
+		sessionName string
+		sql         string
+	}
+	var queries []query
+	hookedReader := NewReader(reader.conns, testXChainID.String(),
+		WithSessionNamePrefix("api-"),
+		WithQueryHook(func(sessionName, sql string, _ []interface{}, dur time.Duration) {
+			if dur <= 0 {
+				t.Error("Incorrect query duration:", dur)
+			}
+			queries = append(queries, query{sessionName, sql})
+		}))
+
+	p := &params.ListAssetsParams{Query: "test"}
+	p.DisableCounting = true
+	if _, err := hookedReader.ListAssets(context.Background(), p); err != nil {
+		t.Fatal("Failed to list assets:", err.Error())
+	}
+	if len(queries) != 1 {
+		t.Fatal("Incorrect number of queries:", len(queries))
+	}
+	if queries[0].sessionName != "api-list_assets" {
+		t.Fatal("Incorrect session name:", queries[0].sessionName)
+	}
+	if !strings.Contains(queries[0].sql, "FROM avm_assets") || !strings.Contains(queries[0].sql, "'test%'") {
+		t.Fatal("Incorrect SQL:", queries[0].sql)
+	}
+
+	// Readers without a hook aren't affected
+	queries = nil
+	if _, err := reader.ListAssets(context.Background(), &params.ListAssetsParams{}); err != nil {
+		t.Fatal("Failed to list assets:", err.Error())
+	}
+	if len(queries) != 0 {
+		t.Fatal("Unexpected queries:", queries)
+	}
+}
+
+func TestQueryTimeout(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	// An incoming deadline that has already passed fails the query
+	ctx, cancelFn := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancelFn()
+	time.Sleep(time.Millisecond)
+	if _, err := reader.ListTransactions(ctx, &params.ListTransactionsParams{}); err != ErrQueryTimeout {
+		t.Fatal("Expected ErrQueryTimeout, got:", err)
+	}
+
+	// slowQuery blocks until its context is done, like a query that is
+	// cancelled by the DB driver
+	slowQuery := func(ctx context.Context, r *Reader) (err error) {
+		ctx, cancelFn := r.queryContext(ctx)
+		defer endQuery(ctx, cancelFn, &err)
+
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	// The Reader's timeout applies when the incoming context has no deadline
+	timeoutReader := NewReader(reader.conns, testXChainID.String(), WithQueryTimeout(10*time.Millisecond))
+	if err := slowQuery(context.Background(), timeoutReader); err != ErrQueryTimeout {
+		t.Fatal("Expected ErrQueryTimeout, got:", err)
+	}
+
+	// A shorter incoming deadline takes precedence over the Reader's timeout
+	ctx, cancelFn = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelFn()
+	start := time.Now()
+	if err := slowQuery(ctx, reader); err != ErrQueryTimeout {
+		t.Fatal("Expected ErrQueryTimeout, got:", err)
+	}
+	if time.Since(start) > DefaultQueryTimeout/2 {
+		t.Fatal("Incoming deadline was not respected")
+	}
+
+	// Cancellations that aren't caused by a deadline are returned unchanged
+	ctx, cancelFn = context.WithCancel(context.Background())
+	cancelFn()
+	if err := slowQuery(ctx, reader); err != context.Canceled {
+		t.Fatal("Expected context.Canceled, got:", err)
+	}
+}
+
+func TestMaxExpensiveQueries(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+	insertTestTransaction(t, sess, testID(1), now)
+	insertTestOutput(t, sess, testID(1), 0, testID(101), 1, testShortID(1), now)
+
+	limitedReader := NewReader(reader.conns, testXChainID.String(), WithMaxExpensiveQueries(2))
+	aggregateParams := func() *params.AggregateParams {
+		return &params.AggregateParams{StartTime: now, EndTime: now.Add(time.Hour)}
+	}
+
+	// Fill the Reader's capacity
+	releases := make([]func(), 2)
+	for i := range releases {
+		release, err := limitedReader.acquireExpensiveQuery(context.Background())
+		if err != nil {
+			t.Fatal("Failed to acquire query:", err.Error())
+		}
+		releases[i] = release
+	}
+
+	// Expensive queries wait for capacity until their context is done
+	ctx, cancelFn := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelFn()
+	if _, err := limitedReader.Aggregate(ctx, aggregateParams()); err != ErrTooBusy {
+		t.Fatal("Expected ErrTooBusy, got:", err)
+	}
+	if _, err := limitedReader.ListOutputs(ctx, &params.ListOutputsParams{}); err != ErrTooBusy {
+		t.Fatal("Expected ErrTooBusy, got:", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := limitedReader.Aggregate(context.Background(), aggregateParams())
+		done <- err
+	}()
+
+	// Cheap lookups aren't limited
+	if tx, err := limitedReader.GetTransaction(context.Background(), testID(1)); err != nil || tx == nil {
+		t.Fatal("Failed to get transaction:", err)
+	}
+	if output, err := limitedReader.GetOutput(context.Background(), testID(1).Prefix(0)); err != nil || output == nil {
+		t.Fatal("Failed to get output:", err)
+	}
+
+	select {
+	case err := <-done:
+		t.Fatal("Aggregate ran over the limit:", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Releasing a query lets the waiting one run
+	releases[0]()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal("Failed to aggregate:", err.Error())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Aggregate didn't run after capacity was released")
+	}
+	releases[1]()
+
+	// Unlimited Readers never wait
+	if _, err := reader.Aggregate(context.Background(), aggregateParams()); err != nil {
+		t.Fatal("Failed to aggregate:", err.Error())
+	}
+}
+
+func TestHealth(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+
+	if err := reader.Health(context.Background()); err != nil {
+		t.Fatal("Expected healthy DB, got:", err.Error())
+	}
+
+	// A DB that doesn't respond in time is slow
+	ctx, cancelFn := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancelFn()
+	time.Sleep(time.Millisecond)
+	if err := reader.Health(ctx); !errors.Is(err, ErrDBSlow) {
+		t.Fatal("Expected ErrDBSlow, got:", err)
+	}
+
+	// A closed connection is unavailable
+	closeFn()
+	if err := reader.Health(context.Background()); !errors.Is(err, ErrDBUnavailable) {
+		t.Fatal("Expected ErrDBUnavailable, got:", err)
+	}
+}
+
+func TestSearchByMemo(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	tx1, tx2 := testID(1), testID(2)
+	insertTestTransaction(t, sess, tx1, now)
+	insertTestTransaction(t, sess, tx2, now)
+	_, err := sess.
+		Update("avm_transactions").
+		Set("memo", []byte("invoice #42: 100% paid")).
+		Where("id = ?", tx1.String()).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to set memo:", err.Error())
+	}
+
+	for query, expected := range map[string][]ids.ID{
+		"invoice #42":              {tx1},
+		"100% paid":                {tx1},
+		"%":                        {tx1},
+		"0_ p":                     {},
+		"invoice #43":              {},
+		string(make([]byte, 2000)): {},
+	} {
+		p := &params.SearchParams{Query: query}
+		p.Limit = 10
+
+		results, err := reader.Search(context.Background(), p)
+		if err != nil {
+			t.Fatal("Failed to search:", err.Error())
+		}
+
+		var txs []*models.Transaction
+		for _, result := range results.Results {
+			if result.SearchResultType == models.ResultTypeTransaction {
+				txs = append(txs, result.Data.(*models.Transaction))
+			}
+		}
+		assertTransactionIDs(t, txs, expected)
+	}
+}
+
+func TestSearchByTransactionIDPrefix(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// tx2's memo contains the start of tx1's ID, which only a normal search
+	// would match, and a reference that looks like the start of an ID
+	tx1, tx2 := testID(1), testID(2)
+	prefix := tx1.String()[:12]
+	insertTestTransaction(t, sess, tx1, now)
+	insertTestTransaction(t, sess, tx2, now)
+	_, err := sess.
+		Update("avm_transactions").
+		Set("memo", []byte("paymentRef123456 for "+prefix)).
+		Where("id = ?", tx2.String()).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to set memo:", err.Error())
+	}
+
+	for query, expected := range map[string][]ids.ID{
+		prefix:         {tx1},
+		"paymentRef12": {tx2},
+	} {
+		p := &params.SearchParams{Query: query}
+		p.Limit = 10
+
+		results, err := reader.Search(context.Background(), p)
+		if err != nil {
+			t.Fatal("Failed to search:", err.Error())
+		}
+		if results.Count != uint64(len(expected)) {
+			t.Fatalf("Incorrect count for %s: %d", query, results.Count)
+		}
+
+		var txs []*models.Transaction
+		for _, result := range results.Results {
+			if result.SearchResultType == models.ResultTypeTransaction {
+				txs = append(txs, result.Data.(*models.Transaction))
+			}
+		}
+		assertTransactionIDs(t, txs, expected)
+	}
+
+	if isIDPrefix(prefix[:MinTransactionIDPrefixLength-1]) || isIDPrefix("0OIl0OIl0OIl") {
+		t.Fatal("Short or non-base58 queries treated as ID prefixes")
+	}
+}
+
+func TestSearchByTransactionIDPrefixPagination(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// Five transactions share a prefix, in ID and creation order
+	prefix := "PrefixPage12"
+	var txIDs []models.StringID
+	for i, suffix := range "ABCDE" {
+		txID := models.StringID(prefix + string(suffix))
+		txIDs = append(txIDs, txID)
+		_, err := sess.
+			InsertInto("avm_transactions").
+			Pair("id", string(txID)).
+			Pair("chain_id", testXChainID.String()).
+			Pair("type", models.TransactionTypeBase.String()).
+			Pair("canonical_serialization", []byte{}).
+			Pair("created_at", now.Add(time.Duration(i)*time.Second)).
+			Exec()
+		if err != nil {
+			t.Fatal("Failed to insert transaction:", err.Error())
+		}
+	}
+
+	// Every page, including one past the end, counts all of the matches
+	for _, test := range []struct {
+		offset   int
+		expected []models.StringID
+	}{
+		{0, txIDs[:2]},
+		{2, txIDs[2:4]},
+		{4, txIDs[4:]},
+		{6, nil},
+	} {
+		p := &params.SearchParams{Query: prefix}
+		p.Offset, p.Limit = test.offset, 2
+
+		results, err := reader.Search(context.Background(), p)
+		if err != nil {
+			t.Fatal("Failed to search:", err.Error())
+		}
+		if results.Count != uint64(len(txIDs)) {
+			t.Fatalf("Incorrect count at offset %d: %d", test.offset, results.Count)
+		}
+		if len(results.Results) != len(test.expected) {
+			t.Fatalf("Incorrect number of results at offset %d: %d", test.offset, len(results.Results))
+		}
+		for i, result := range results.Results {
+			if tx, ok := result.Data.(*models.Transaction); !ok || tx.ID != test.expected[i] {
+				t.Fatalf("Incorrect result at offset %d: %v", test.offset, result.Data)
+			}
+		}
+	}
+
+	// Counting can be disabled
+	p := &params.SearchParams{Query: prefix}
+	p.Limit, p.DisableCounting = 2, true
+	results, err := reader.Search(context.Background(), p)
+	if err != nil {
+		t.Fatal("Failed to search:", err.Error())
+	}
+	if results.Count != 0 || len(results.Results) != 2 {
+		t.Fatal("Expected an uncounted page:", results.Count, len(results.Results))
+	}
+}
+
+func TestSearchByAssetAlias(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// Only asset1 has the alias, but a normal search would also match asset2's
+	// name
+	asset1, asset2 := testID(101), testID(102)
+	insertTestAsset(t, sess, asset1, testXChainID.String(), 9, now)
+	insertTestAsset(t, sess, asset2, testXChainID.String(), 9, now)
+	for id, column := range map[ids.ID]string{asset1: "alias", asset2: "name"} {
+		_, err := sess.
+			Update("avm_assets").
+			Set(column, "AVAX").
+			Where("id = ?", id.String()).
+			Exec()
+		if err != nil {
+			t.Fatal("Failed to update asset:", err.Error())
+		}
+	}
+
+	var queries []string
+	hookedReader := NewReader(reader.conns, testXChainID.String(),
+		WithQueryHook(func(_, sql string, _ []interface{}, _ time.Duration) {
+			queries = append(queries, sql)
+		}))
+
+	results, err := hookedReader.Search(context.Background(), &params.SearchParams{Query: "AVAX"})
+	if err != nil {
+		t.Fatal("Failed to search:", err.Error())
+	}
+	if len(queries) != 1 {
+		t.Fatal("Expected a single query, got:", queries)
+	}
+	if results.Count != 1 || len(results.Results) != 1 || results.Results[0].SearchResultType != models.ResultTypeAsset {
+		t.Fatal("Incorrect results:", results)
+	}
+	if asset := results.Results[0].Data.(*models.Asset); asset.ID != models.ToStringID(asset1) {
+		t.Fatal("Incorrect asset:", asset.ID)
+	}
+
+	// Other queries still fall back to a normal search
+	results, err = hookedReader.Search(context.Background(), &params.SearchParams{Query: "TEST"})
+	if err != nil {
+		t.Fatal("Failed to search:", err.Error())
+	}
+	if results.Count != 2 {
+		t.Fatal("Incorrect count:", results.Count)
+	}
+}
+
+func TestSearchByAddress(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	insertTestOutput(t, sess, testID(1), 0, testID(101), 100, testShortID(1), time.Now().UTC().Truncate(time.Second))
+
+	addr, err := formatting.FormatBech32("avax", testShortID(1).Bytes())
+	if err != nil {
+		t.Fatal("Failed to format address:", err.Error())
+	}
+
+	// A valid address finds the address
+	results, err := reader.Search(context.Background(), &params.SearchParams{Query: "X-" + addr})
+	if err != nil {
+		t.Fatal("Failed to search:", err.Error())
+	}
+	if results.Count != 1 || results.Results[0].SearchResultType != models.ResultTypeAddress {
+		t.Fatal("Incorrect results:", results)
+	}
+
+	// Other strings are searched for normally
+	results, err = reader.Search(context.Background(), &params.SearchParams{Query: "avax1notanaddress"})
+	if err != nil {
+		t.Fatal("Failed to search:", err.Error())
+	}
+	if results.Count != 0 {
+		t.Fatal("Incorrect results:", results)
+	}
+
+	// Changing a character of the address breaks its checksum
+	last := addr[len(addr)-1:]
+	replacement := "q"
+	if last == replacement {
+		replacement = "p"
+	}
+	for _, query := range []string{addr[:len(addr)-1] + replacement, "X-" + strings.ToUpper(addr[:len(addr)-1]+replacement)} {
+		_, err = reader.Search(context.Background(), &params.SearchParams{Query: query})
+		if err != params.ErrInvalidAddress {
+			t.Fatalf("Expected ErrInvalidAddress for %s, got: %v", query, err)
+		}
+	}
+}
+
+func TestSearchPagination(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// Insert 3 assets and 3 txs matching the query, each created out of order
+	for i, offset := range []time.Duration{2, 0, 1} {
+		createdAt := now.Add(offset * time.Second)
+		insertTestAsset(t, sess, testID(byte(101+i)), testXChainID.String(), 0, createdAt)
+		insertTestTransaction(t, sess, testID(byte(1+i)), createdAt)
+	}
+	_, err := sess.
+		Update("avm_transactions").
+		Set("memo", []byte("test memo")).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to set memo:", err.Error())
+	}
+
+	expected := []string{
+		testID(102).String(), testID(103).String(), testID(101).String(),
+		testID(2).String(), testID(3).String(), testID(1).String(),
+	}
+
+	for _, disableCounting := range []bool{false, true} {
+		var found []string
+		for offset := 0; offset <= len(expected); offset += 2 {
+			p := &params.SearchParams{Query: "test"}
+			p.Offset = offset
+			p.Limit = 2
+			p.DisableCounting = disableCounting
+
+			results, err := reader.Search(context.Background(), p)
+			if err != nil {
+				t.Fatal("Failed to search:", err.Error())
+			}
+
+			expectedCount := uint64(len(expected))
+			if disableCounting {
+				expectedCount = 0
+			}
+			if results.Count != expectedCount {
+				t.Fatalf("Incorrect count at offset %d: %d", offset, results.Count)
+			}
+
+			for _, result := range results.Results {
+				switch data := result.Data.(type) {
+				case *models.Asset:
+					found = append(found, string(data.ID))
+				case *models.Transaction:
+					found = append(found, string(data.ID))
+				default:
+					t.Fatal("Unexpected search result type:", result.SearchResultType)
+				}
+			}
+		}
+
+		if len(found) != len(expected) {
+			t.Fatalf("Incorrect number of results: %d", len(found))
+		}
+		for i := range expected {
+			if found[i] != expected[i] {
+				t.Fatalf("Incorrect result at %d: %s", i, found[i])
+			}
+		}
+	}
+}
+
+func TestSearchAssetRanking(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// The exact match has the smallest supply and was created last
+	exact, prefix, substring := testID(101), testID(102), testID(103)
+	for i, asset := range []struct {
+		id     ids.ID
+		name   string
+		symbol string
+		supply uint64
+	}{
+		{substring, "Wrapped AVAX", "WAVAX", 1000000},
+		{prefix, "AVAX Plus", "AVAXP", 5000},
+		{exact, "Avalanche", "AVAX", 100},
+	} {
+		insertTestAsset(t, sess, asset.id, testXChainID.String(), 0, now.Add(time.Duration(i)*time.Second))
+		_, err := sess.
+			Update("avm_assets").
+			Set("name", asset.name).
+			Set("symbol", asset.symbol).
+			Set("current_supply", asset.supply).
+			Where("id = ?", asset.id.String()).
+			Exec()
+		if err != nil {
+			t.Fatal("Failed to update asset:", err.Error())
+		}
+	}
+
+	// Substring matches rank below exact and prefix matches despite having the
+	// largest supply
+	assetList, err := reader.ListAssets(context.Background(), &params.ListAssetsParams{
+		Query:     "AVAX",
+		QueryMode: params.QueryModeSubstring,
+		Sort:      params.AssetSortRelevance,
+	})
+	if err != nil {
+		t.Fatal("Failed to list assets:", err.Error())
+	}
+	expected := []ids.ID{exact, prefix, substring}
+	if len(assetList.Assets) != len(expected) {
+		t.Fatal("Incorrect number of assets:", len(assetList.Assets))
+	}
+	for i, id := range expected {
+		if assetList.Assets[i].ID != models.ToStringID(id) {
+			t.Fatalf("Incorrect asset at %d: %s", i, assetList.Assets[i].ID)
+		}
+	}
+
+	// Search only matches prefixes but still puts the exact symbol match first
+	results, err := reader.Search(context.Background(), &params.SearchParams{Query: "AVAX"})
+	if err != nil {
+		t.Fatal("Failed to search:", err.Error())
+	}
+	if len(results.Results) != 2 {
+		t.Fatal("Incorrect number of results:", len(results.Results))
+	}
+	for i, id := range expected[:2] {
+		asset, ok := results.Results[i].Data.(*models.Asset)
+		if !ok || asset.ID != models.ToStringID(id) {
+			t.Fatalf("Incorrect result at %d: %v", i, results.Results[i].Data)
+		}
+	}
+	if results.Results[0].Score <= results.Results[1].Score {
+		t.Fatal("Incorrect scores:", results.Results[0].Score, results.Results[1].Score)
+	}
+}
+
+func TestResolveChainID(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	chainID := testXChainID.String()
+	aliasedReader := NewReader(reader.conns, chainID, WithChainAliases(map[string]string{"X": chainID}))
+
+	for _, test := range []struct {
+		aliasOrID string
+		expected  string
+		err       error
+	}{
+		{"X", chainID, nil},
+		{"x", chainID, nil},
+		{"X-Chain", chainID, nil},
+		{chainID, chainID, nil},
+		{testID(1).String(), testID(1).String(), nil},
+		{"P", "", ErrUnknownChainAlias},
+		{"", "", ErrUnknownChainAlias},
+	} {
+		resolved, err := aliasedReader.ResolveChainID(context.Background(), test.aliasOrID)
+		if err != test.err {
+			t.Fatalf("Incorrect error for %q: %v", test.aliasOrID, err)
+		}
+		if resolved != test.expected {
+			t.Fatalf("Incorrect chain ID for %q: %s", test.aliasOrID, resolved)
+		}
+	}
+
+	// Lists accept aliases in place of chain IDs
+	sess := reader.conns.DB().NewSession("test")
+	insertTestTransaction(t, sess, testID(1), time.Now().UTC().Truncate(time.Second))
+	txList, err := aliasedReader.ListTransactions(context.Background(), &params.ListTransactionsParams{ChainIDs: []string{"X-Chain"}})
+	if err != nil {
+		t.Fatal("Failed to list transactions:", err.Error())
+	}
+	if len(txList.Transactions) != 1 {
+		t.Fatal("Incorrect number of transactions:", len(txList.Transactions))
+	}
+}
+
+func TestIndexStatus(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+	otherChainID := testID(200).String()
+
+	// The Reader's chain is a minute behind and the other chain an hour
+	insertTestTransaction(t, sess, testID(1), now.Add(-2*time.Hour))
+	insertTestTransaction(t, sess, testID(2), now.Add(-time.Minute))
+	insertTestTransaction(t, sess, testID(3), now.Add(-time.Hour))
+	_, err := sess.
+		Update("avm_transactions").
+		Set("chain_id", otherChainID).
+		Where("id = ?", testID(3).String()).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to move to other chain:", err.Error())
+	}
+
+	status, err := reader.IndexStatus(context.Background())
+	if err != nil {
+		t.Fatal("Failed to get index status:", err.Error())
+	}
+	if len(status.Chains) != 2 || status.Chains[0].ChainID >= status.Chains[1].ChainID {
+		t.Fatal("Incorrect chains:", status.Chains)
+	}
+
+	expected := map[models.StringID]time.Time{
+		models.StringID(testXChainID.String()): now.Add(-time.Minute),
+		models.StringID(otherChainID):          now.Add(-time.Hour),
+	}
+	for _, chain := range status.Chains {
+		latest, ok := expected[chain.ChainID]
+		if !ok || !chain.LatestTransactionTime.Equal(latest) {
+			t.Fatalf("Incorrect latest transaction time of %s: %s", chain.ChainID, chain.LatestTransactionTime)
+		}
+		if chain.Lag != status.Time.Sub(latest) {
+			t.Fatalf("Incorrect lag of %s: %s", chain.ChainID, chain.Lag)
+		}
+	}
+}
+
+func TestReaderChainIsolation(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+	otherChainID := testID(200).String()
+
+	// tx1 and asset1 are on the Reader's chain, tx2 and asset2 on another
+	tx1, tx2 := testID(1), testID(2)
+	asset1, asset2 := testID(101), testID(102)
+	insertTestTransaction(t, sess, tx1, now)
+	insertTestTransaction(t, sess, tx2, now)
+	insertTestOutput(t, sess, tx1, 0, asset1, 100, testShortID(1), now)
+	insertTestOutput(t, sess, tx2, 0, asset2, 100, testShortID(2), now)
+	for _, table := range []string{"avm_transactions", "avm_outputs"} {
+		_, err := sess.
+			Update(table).
+			Set("chain_id", otherChainID).
+			Where("id = ? OR id = ?", tx2.String(), tx2.Prefix(0).String()).
+			Exec()
+		if err != nil {
+			t.Fatal("Failed to move to other chain:", err.Error())
+		}
+	}
+	insertTestAsset(t, sess, asset1, testXChainID.String(), 0, now)
+	insertTestAsset(t, sess, asset2, otherChainID, 0, now)
+
+	ctx := context.Background()
+	for _, test := range []struct {
+		chainIDs []string
+		expected []ids.ID
+	}{
+		{nil, []ids.ID{tx1}},
+		{[]string{otherChainID}, []ids.ID{tx2}},
+		{[]string{testXChainID.String(), otherChainID}, []ids.ID{tx1, tx2}},
+	} {
+		txList, err := reader.ListTransactions(ctx, &params.ListTransactionsParams{ChainIDs: test.chainIDs})
+		if err != nil {
+			t.Fatal("Failed to list transactions:", err.Error())
+		}
+		assertTransactionIDs(t, txList.Transactions, test.expected)
+
+		outputList, err := reader.ListOutputs(ctx, &params.ListOutputsParams{ChainIDs: test.chainIDs})
+		if err != nil {
+			t.Fatal("Failed to list outputs:", err.Error())
+		}
+		if len(outputList.Outputs) != len(test.expected) {
+			t.Fatal("Incorrect number of outputs:", len(outputList.Outputs))
+		}
+
+		assetList, err := reader.ListAssets(ctx, &params.ListAssetsParams{ChainIDs: test.chainIDs})
+		if err != nil {
+			t.Fatal("Failed to list assets:", err.Error())
+		}
+		if len(assetList.Assets) != len(test.expected) {
+			t.Fatal("Incorrect number of assets:", len(assetList.Assets))
+		}
+
+		addressList, err := reader.ListAddresses(ctx, &params.ListAddressesParams{ChainIDs: test.chainIDs})
+		if err != nil {
+			t.Fatal("Failed to list addresses:", err.Error())
+		}
+		if len(addressList.Addresses) != len(test.expected) {
+			t.Fatal("Incorrect number of addresses:", len(addressList.Addresses))
+		}
+	}
+}
+
+func TestStreamOutputs(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	insertTestTransactionChain(t, sess, 7)
+
+	for _, test := range []struct {
+		name  string
+		p     params.ListOutputsParams
+		count int
+	}{
+		{"all", params.ListOutputsParams{}, 7},
+		{"limited", params.ListOutputsParams{ListParams: params.ListParams{Limit: 5, Offset: 1}}, 5},
+		{"none", params.ListOutputsParams{Addresses: []ids.ShortID{testShortID(200)}}, 0},
+	} {
+		// Streaming in batches of 3 writes the same JSON as marshaling the list
+		streamParams := test.p
+		var streamed bytes.Buffer
+		if err := reader.streamOutputs(context.Background(), &streamParams, 3, &streamed); err != nil {
+			t.Fatalf("Failed to stream %s outputs: %s", test.name, err.Error())
+		}
+
+		listParams := test.p
+		outputList, err := reader.ListOutputs(context.Background(), &listParams)
+		if err != nil {
+			t.Fatalf("Failed to list %s outputs: %s", test.name, err.Error())
+		}
+		if len(outputList.Outputs) != test.count {
+			t.Fatalf("Incorrect number of %s outputs: %d", test.name, len(outputList.Outputs))
+		}
+		listed, err := json.Marshal(outputList.Outputs)
+		if err != nil {
+			t.Fatal("Failed to marshal outputs:", err.Error())
+		}
+
+		if streamed.String() != string(listed) {
+			t.Fatalf("Streamed %s outputs differ:\n%s\n%s", test.name, streamed.String(), listed)
+		}
+	}
+
+	// Only the default sort can be streamed
+	var streamed bytes.Buffer
+	err := reader.StreamOutputs(context.Background(), &params.ListOutputsParams{Sort: params.OutputSortAmountDesc}, &streamed)
+	if err != params.ErrCursorWithSort {
+		t.Fatal("Expected ErrCursorWithSort, got:", err)
+	}
+}
+
+func TestExportOutputsCSV(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	txID := testID(1)
+	insertTestOutput(t, sess, txID, 0, testID(101), 300, testShortID(1), now.Add(time.Second))
+	insertTestOutput(t, sess, txID, 1, testID(101), 100, testShortID(1), now)
+	insertTestOutput(t, sess, txID, 2, testID(101), 200, testShortID(2), now)
+
+	buf := &bytes.Buffer{}
+	if err := reader.ExportOutputsCSV(context.Background(), &params.ListOutputsParams{}, buf); err != nil {
+		t.Fatal("Failed to export outputs:", err.Error())
+	}
+
+	records, err := csv.NewReader(buf).ReadAll()
+	if err != nil {
+		t.Fatal("Failed to read CSV:", err.Error())
+	}
+	if len(records) != 4 {
+		t.Fatal("Incorrect number of records:", len(records))
+	}
+	if records[0][0] != "id" || records[0][5] != "amount" {
+		t.Fatal("Incorrect header:", records[0])
+	}
+
+	// Rows are ordered by timestamp and then id
+	expectedAmounts := []string{"100", "200", "300"}
+	if txID.Prefix(1).String() > txID.Prefix(2).String() {
+		expectedAmounts = []string{"200", "100", "300"}
+	}
+	for i, record := range records[1:] {
+		if record[5] != expectedAmounts[i] {
+			t.Fatalf("Incorrect amount for row %d: %s", i, record[5])
+		}
+	}
+
+	// A cancelled context stops the export
+	ctx, cancelFn := context.WithCancel(context.Background())
+	cancelFn()
+	if err = reader.ExportOutputsCSV(ctx, &params.ListOutputsParams{}, &bytes.Buffer{}); err == nil {
+		t.Fatal("Expected an error for a cancelled context")
+	}
+}
+
+func TestFormattedOutputAmounts(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// Only the first asset is known
+	txID, knownAsset, unknownAsset := testID(1), testID(101), testID(102)
+	insertTestTransaction(t, sess, txID, now)
+	insertTestAsset(t, sess, knownAsset, testXChainID.String(), 2, now)
+	insertTestOutput(t, sess, txID, 0, knownAsset, 12345, testShortID(1), now)
+	insertTestOutput(t, sess, txID, 1, unknownAsset, 12345, testShortID(1), now)
+
+	tx, err := reader.GetTransaction(context.Background(), txID)
+	if err != nil {
+		t.Fatal("Failed to get transaction:", err.Error())
+	}
+	if tx == nil || len(tx.Outputs) != 2 {
+		t.Fatal("Incorrect transaction:", tx)
+	}
+	for _, output := range tx.Outputs {
+		expected := ""
+		if output.AssetID.Equals(models.ToStringID(knownAsset)) {
+			expected = "123.45"
+		}
+		if output.FormattedAmount != expected {
+			t.Fatal("Incorrect formatted amount:", output.FormattedAmount)
+		}
+	}
+
+	// Totals carry the denomination of known assets
+	if denomination := tx.OutputTotals[models.ToStringID(knownAsset)].Denomination; denomination == nil || *denomination != 2 {
+		t.Fatal("Incorrect denomination of known asset:", denomination)
+	}
+	if denomination := tx.OutputTotals[models.ToStringID(unknownAsset)].Denomination; denomination != nil {
+		t.Fatal("Unexpected denomination of unknown asset:", *denomination)
+	}
+}
+
+func TestListMaxLimit(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	assetCount := params.PaginationMaxLimit + 10
+	for i := 0; i < assetCount; i++ {
+		insertTestAsset(t, sess, ids.NewID([32]byte{byte(i), byte(i >> 8)}), testXChainID.String(), 0, now)
+	}
+
+	for _, test := range []struct {
+		maxLimit int
+		expected int
+	}{
+		// The public API never sets MaxLimit and is capped by the global limit
+		{0, params.PaginationMaxLimit},
+		{params.PaginationMaxLimit + 5, params.PaginationMaxLimit + 5},
+		{params.PaginationHardMaxLimit, assetCount},
+	} {
+		p := &params.ListAssetsParams{ListParams: params.ListParams{Limit: assetCount, MaxLimit: test.maxLimit}}
+		assetList, err := reader.ListAssets(context.Background(), p)
+		if err != nil {
+			t.Fatal("Failed to list assets:", err.Error())
+		}
+		if len(assetList.Assets) != test.expected {
+			t.Fatalf("Incorrect number of assets for max limit %d: %d", test.maxLimit, len(assetList.Assets))
+		}
+	}
+
+	// MaxLimit can't exceed the hard cap
+	p := params.ListParams{Limit: 2 * params.PaginationHardMaxLimit, MaxLimit: 2 * params.PaginationHardMaxLimit}
+	if limit := p.EffectiveLimit(); limit != params.PaginationHardMaxLimit {
+		t.Fatal("Incorrect effective limit:", limit)
+	}
+}
+
+func TestListDefaultLimit(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+	for i := 0; i < 3; i++ {
+		insertTestAsset(t, sess, testID(byte(101+i)), testXChainID.String(), 0, now.Add(time.Duration(i)*time.Second))
+	}
+
+	params.SetDefaultLimit(2)
+	defer params.SetDefaultLimit(0)
+
+	// Requests without a limit get the configured default
+	p := &params.ListAssetsParams{}
+	if err := p.ForValues(url.Values{}); err != nil {
+		t.Fatal("Failed to parse params:", err.Error())
+	}
+	assetList, err := reader.ListAssets(context.Background(), p)
+	if err != nil {
+		t.Fatal("Failed to list assets:", err.Error())
+	}
+	if len(assetList.Assets) != 2 || !assetList.HasMore || assetList.Count != 3 {
+		t.Fatalf("Incorrect page: %d assets, hasMore %t, count %d", len(assetList.Assets), assetList.HasMore, assetList.Count)
+	}
+
+	// An explicit limit still wins
+	if err = p.ForValues(url.Values{params.KeyLimit: []string{"3"}}); err != nil {
+		t.Fatal("Failed to parse params:", err.Error())
+	}
+	if p.Limit != 3 {
+		t.Fatal("Incorrect limit:", p.Limit)
+	}
+
+	// The default is bounded by the max limit, and resetting it restores the
+	// built-in default
+	for _, test := range []struct {
+		defaultLimit int
+		expected     int
+	}{
+		{params.PaginationMaxLimit + 1, params.PaginationMaxLimit},
+		{0, params.PaginationDefaultLimit},
+	} {
+		params.SetDefaultLimit(test.defaultLimit)
+		if err = p.ForValues(url.Values{}); err != nil {
+			t.Fatal("Failed to parse params:", err.Error())
+		}
+		if p.Limit != test.expected {
+			t.Fatalf("Incorrect limit for default %d: %d", test.defaultLimit, p.Limit)
+		}
+	}
+}
+
+func TestListAssetsByTime(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := byte(0); i < 4; i++ {
+		insertTestAsset(t, sess, testID(101+i), testXChainID.String(), 0, start.Add(time.Duration(i)*time.Hour))
+	}
+
+	for _, test := range []struct {
+		startTime time.Time
+		endTime   time.Time
+		expected  int
+	}{
+		{time.Time{}, time.Time{}, 4},
+		{start.Add(time.Hour), time.Time{}, 3},
+		{time.Time{}, start.Add(time.Hour), 2},
+		{start.Add(30 * time.Minute), start.Add(150 * time.Minute), 2},
+		{start.Add(4 * time.Hour), time.Time{}, 0},
+	} {
+		// Use a limit equal to the expected count to force the count query to run
+		p := &params.ListAssetsParams{StartTime: test.startTime, EndTime: test.endTime}
+		p.Limit = test.expected
+
+		assetList, err := reader.ListAssets(context.Background(), p)
+		if err != nil {
+			t.Fatal("Failed to list assets:", err.Error())
+		}
+		if len(assetList.Assets) != test.expected {
+			t.Fatal("Incorrect number of assets:", len(assetList.Assets))
+		}
+		if assetList.Count != uint64(test.expected) {
+			t.Fatal("Incorrect count:", assetList.Count)
+		}
+		for _, asset := range assetList.Assets {
+			if asset.CreatedAt.Before(test.startTime) || (!test.endTime.IsZero() && asset.CreatedAt.After(test.endTime)) {
+				t.Fatal("Asset outside of time range:", asset.CreatedAt)
+			}
+		}
+	}
+}
+
+func TestListAssetsChainScoping(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+	otherChainID := testID(200).String()
+
+	// Two assets on the Reader's chain and three on another
+	for i := byte(0); i < 5; i++ {
+		chainID := testXChainID.String()
+		if i >= 2 {
+			chainID = otherChainID
+		}
+		insertTestAsset(t, sess, testID(101+i), chainID, 0, now.Add(time.Duration(i)*time.Second))
+	}
+
+	ctx := context.Background()
+	for _, test := range []struct {
+		chainIDs []string
+		expected int
+	}{
+		{nil, 2},
+		{[]string{otherChainID}, 3},
+		{[]string{testXChainID.String(), otherChainID}, 5},
+	} {
+		// A limit of one forces the count query to run
+		p := &params.ListAssetsParams{ChainIDs: test.chainIDs}
+		p.Limit = 1
+		assetList, err := reader.ListAssets(ctx, p)
+		if err != nil {
+			t.Fatal("Failed to list assets:", err.Error())
+		}
+		if assetList.Count != uint64(test.expected) {
+			t.Fatal("Incorrect count:", assetList.Count)
+		}
+
+		p = &params.ListAssetsParams{ChainIDs: test.chainIDs}
+		p.CountOnly = true
+		assetList, err = reader.ListAssets(ctx, p)
+		if err != nil {
+			t.Fatal("Failed to count assets:", err.Error())
+		}
+		if assetList.Count != uint64(test.expected) {
+			t.Fatal("Incorrect count only:", assetList.Count)
+		}
+
+		assetList, err = reader.ListAssets(ctx, &params.ListAssetsParams{ChainIDs: test.chainIDs})
+		if err != nil {
+			t.Fatal("Failed to list assets:", err.Error())
+		}
+		if len(assetList.Assets) != test.expected {
+			t.Fatal("Incorrect number of assets:", len(assetList.Assets))
+		}
+		for _, asset := range assetList.Assets {
+			if test.chainIDs == nil && string(asset.ChainID) != testXChainID.String() {
+				t.Fatal("Asset from another chain:", asset.ChainID)
+			}
+		}
+	}
+}
+
+func TestListRecentAssets(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Insert out of creation order so the results can't follow insertion order
+	for _, i := range []byte{2, 0, 3, 1} {
+		insertTestAsset(t, sess, testID(101+i), testXChainID.String(), 0, start.Add(time.Duration(i)*time.Hour))
+	}
+
+	countQueries := 0
+	hookedReader := NewReader(reader.conns, testXChainID.String(),
+		WithQueryHook(func(_, sql string, _ []interface{}, _ time.Duration) {
+			if strings.Contains(sql, "COUNT(") {
+				countQueries++
+			}
+		}))
+
+	for _, test := range []struct {
+		limit    int
+		expected []ids.ID
+	}{
+		{0, []ids.ID{testID(104), testID(103), testID(102), testID(101)}},
+		{2, []ids.ID{testID(104), testID(103)}},
+		{MaxRecentAssets + 1, []ids.ID{testID(104), testID(103), testID(102), testID(101)}},
+	} {
+		assets, err := hookedReader.ListRecentAssets(context.Background(), test.limit)
+		if err != nil {
+			t.Fatal("Failed to list recent assets:", err.Error())
+		}
+		if countQueries != 0 {
+			t.Fatal("Expected assets not to be counted:", countQueries)
+		}
+		if len(assets) != len(test.expected) {
+			t.Fatal("Incorrect number of assets:", len(assets))
+		}
+		for i, asset := range assets {
+			if !asset.ID.Equals(models.ToStringID(test.expected[i])) {
+				t.Fatalf("Incorrect asset at %d: %s", i, asset.ID)
+			}
+		}
+	}
+}
+
+func TestTotalSupply(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+	otherChainID := testID(200).String()
+
+	// asset1 and asset2 are on the Reader's chain, asset3 on another
+	asset1, asset2, asset3 := testID(101), testID(102), testID(103)
+	insertTestAsset(t, sess, asset1, testXChainID.String(), 9, now)
+	insertTestAsset(t, sess, asset2, testXChainID.String(), 0, now)
+	insertTestAsset(t, sess, asset3, otherChainID, 0, now)
+	for assetID, supply := range map[ids.ID]string{asset1: "1000000000000", asset2: "42", asset3: "7"} {
+		if _, err := sess.Update("avm_assets").Set("current_supply", supply).Where("id = ?", assetID.String()).Exec(); err != nil {
+			t.Fatal("Failed to set supply:", err.Error())
+		}
+	}
+
+	for _, test := range []struct {
+		chainID  string
+		expected map[ids.ID]models.TokenAmount
+	}{
+		{"", map[ids.ID]models.TokenAmount{asset1: "1000000000000", asset2: "42"}},
+		{otherChainID, map[ids.ID]models.TokenAmount{asset3: "7"}},
+	} {
+		supplies, err := reader.TotalSupply(context.Background(), test.chainID)
+		if err != nil {
+			t.Fatal("Failed to get total supply:", err.Error())
+		}
+		if len(supplies) != len(test.expected) {
+			t.Fatal("Incorrect number of supplies:", len(supplies))
+		}
+		for assetID, amount := range test.expected {
+			supply, ok := supplies[models.ToStringID(assetID)]
+			if !ok || supply.Amount != amount || supply.Denomination == nil {
+				t.Fatalf("Incorrect supply of %s: %+v", assetID, supply)
+			}
+		}
+	}
+
+	// The denomination is kept so the supply can be formatted
+	supplies, err := reader.TotalSupply(context.Background(), "")
+	if err != nil {
+		t.Fatal("Failed to get total supply:", err.Error())
+	}
+	if denomination := *supplies[models.ToStringID(asset1)].Denomination; denomination != 9 {
+		t.Fatal("Incorrect denomination:", denomination)
+	}
+}
+
+func TestListAssetsQueryMode(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// Only asset1's symbol starts with the query but both contain it
+	asset1, asset2 := testID(101), testID(102)
+	insertTestAsset(t, sess, asset1, testXChainID.String(), 0, now)
+	insertTestAsset(t, sess, asset2, testXChainID.String(), 0, now.Add(time.Second))
+	for assetID, symbol := range map[ids.ID]string{asset1: "QRYX", asset2: "XQRY"} {
+		_, err := sess.
+			Update("avm_assets").
+			Set("symbol", symbol).
+			Where("id = ?", assetID.String()).
+			Exec()
+		if err != nil {
+			t.Fatal("Failed to set symbol:", err.Error())
+		}
+	}
+
+	for _, test := range []struct {
+		mode     params.QueryMode
+		expected []ids.ID
+	}{
+		{"", []ids.ID{asset1}},
+		{params.QueryModePrefix, []ids.ID{asset1}},
+		{params.QueryModeSubstring, []ids.ID{asset1, asset2}},
+	} {
+		assetList, err := reader.ListAssets(context.Background(), &params.ListAssetsParams{Query: "QRY", QueryMode: test.mode})
+		if err != nil {
+			t.Fatal("Failed to list assets:", err.Error())
+		}
+		if len(assetList.Assets) != len(test.expected) {
+			t.Fatalf("Incorrect number of assets for %q mode: %d", test.mode, len(assetList.Assets))
+		}
+		for i, asset := range assetList.Assets {
+			if asset.ID != models.ToStringID(test.expected[i]) {
+				t.Fatalf("Incorrect asset %d for %q mode: %s", i, test.mode, asset.ID)
+			}
+		}
+	}
+
+	// The mode is parsed from the query string, defaulting to prefix matching
+	p := &params.ListAssetsParams{}
+	if err := p.ForValues(url.Values{params.KeySearchQuery: {"QRY"}}); err != nil || p.QueryMode != params.QueryModePrefix {
+		t.Fatal("Expected prefix mode by default, got:", p.QueryMode, err)
+	}
+	if err := p.ForValues(url.Values{params.KeyQueryMode: {"fuzzy"}}); !errors.Is(err, params.ErrUndefinedQueryMode) {
+		t.Fatal("Expected ErrUndefinedQueryMode, got:", err)
+	}
+}
+
+func TestGetAssetByAlias(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// asset1 and asset2 share an alias, asset3's is unique
+	asset1, asset2, asset3 := testID(101), testID(102), testID(103)
+	for i, asset := range []struct {
+		id    ids.ID
+		alias string
+	}{
+		{asset1, "SHARED"},
+		{asset2, "SHARED"},
+		{asset3, "UNIQUE"},
+	} {
+		insertTestAsset(t, sess, asset.id, testXChainID.String(), 0, now.Add(time.Duration(i)*time.Second))
+		_, err := sess.
+			Update("avm_assets").
+			Set("alias", asset.alias).
+			Where("id = ?", asset.id.String()).
+			Exec()
+		if err != nil {
+			t.Fatal("Failed to set alias:", err.Error())
+		}
+	}
+
+	ctx := context.Background()
+	asset, err := reader.GetAsset(ctx, "UNIQUE")
+	if err != nil {
+		t.Fatal("Failed to get asset:", err.Error())
+	}
+	if asset == nil || asset.ID != models.ToStringID(asset3) {
+		t.Fatal("Incorrect asset:", asset)
+	}
+
+	if _, err = reader.GetAsset(ctx, "SHARED"); err != ErrAmbiguousAlias {
+		t.Fatal("Expected ErrAmbiguousAlias, got:", err)
+	}
+
+	// IDs are unambiguous even when their alias is shared
+	asset, err = reader.GetAsset(ctx, asset2.String())
+	if err != nil {
+		t.Fatal("Failed to get asset:", err.Error())
+	}
+	if asset == nil || asset.ID != models.ToStringID(asset2) {
+		t.Fatal("Incorrect asset:", asset)
+	}
+
+	assets, err := reader.GetAssetsByAlias(ctx, "SHARED")
+	if err != nil {
+		t.Fatal("Failed to get assets by alias:", err.Error())
+	}
+	if len(assets) != 2 || assets[0].ID != models.ToStringID(asset1) || assets[1].ID != models.ToStringID(asset2) {
+		t.Fatal("Incorrect assets:", assets)
+	}
+
+	asset, err = reader.GetAsset(ctx, "MISSING")
+	if err != ErrAssetNotFound || asset != nil {
+		t.Fatal("Expected no asset, got:", asset, err)
+	}
+}
+
+func TestGetAddressActivityWindow(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	first := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	last := first.Add(36 * time.Hour)
+
+	// The address received outputs at two times, and another address received
+	// one later still
+	insertTestOutput(t, sess, testID(1), 0, testID(101), 100, testShortID(1), last)
+	insertTestOutput(t, sess, testID(1), 1, testID(101), 100, testShortID(1), first)
+	insertTestOutput(t, sess, testID(2), 0, testID(101), 100, testShortID(1), last)
+	insertTestOutput(t, sess, testID(3), 0, testID(101), 100, testShortID(2), last.Add(time.Hour))
+
+	firstSeen, lastSeen, err := reader.GetAddressActivityWindow(context.Background(), testShortID(1))
+	if err != nil {
+		t.Fatal("Failed to get activity window:", err.Error())
+	}
+	if !firstSeen.Equal(first) || !lastSeen.Equal(last) {
+		t.Fatal("Incorrect activity window:", firstSeen, lastSeen)
+	}
+
+	firstSeen, lastSeen, err = reader.GetAddressActivityWindow(context.Background(), testShortID(3))
+	if err != nil {
+		t.Fatal("Failed to get activity window:", err.Error())
+	}
+	if !firstSeen.IsZero() || !lastSeen.IsZero() {
+		t.Fatal("Expected zero times for an unknown address:", firstSeen, lastSeen)
+	}
+}
+
+func TestGetOutputsByTransaction(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// tx1 has two outputs inserted out of order, and tx2 has one of its own
+	insertTestTransaction(t, sess, testID(1), now)
+	insertTestOutput(t, sess, testID(1), 1, testID(101), 200, testShortID(2), now)
+	insertTestOutput(t, sess, testID(1), 0, testID(101), 100, testShortID(1), now)
+	insertTestTransaction(t, sess, testID(2), now)
+	insertTestOutput(t, sess, testID(2), 0, testID(101), 300, testShortID(3), now)
+
+	// The second output of tx1 is locked for an hour
+	unlocksAt := now.Add(time.Hour)
+	_, err := sess.
+		Update("avm_outputs").
+		Set("locktime", unlocksAt.Unix()).
+		Where("id = ?", testID(1).Prefix(1).String()).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to set locktime:", err.Error())
+	}
+
+	outputs, err := reader.GetOutputsByTransaction(context.Background(), testID(1))
+	if err != nil {
+		t.Fatal("Failed to get outputs:", err.Error())
+	}
+	if len(outputs) != 2 {
+		t.Fatal("Incorrect number of outputs:", len(outputs))
+	}
+	for i, output := range outputs {
+		if output.OutputIndex != uint64(i) || output.TransactionID != models.ToStringID(testID(1)) {
+			t.Fatal("Incorrect output:", output.ID)
+		}
+		if len(output.Addresses) != 1 || output.Addresses[0] != models.ToAddress(testShortID(byte(i+1))) {
+			t.Fatal("Incorrect addresses:", output.Addresses)
+		}
+	}
+	if outputs[0].Locked || outputs[0].UnlocksAt != nil {
+		t.Fatal("Expected the first output to be unlocked:", outputs[0].UnlocksAt)
+	}
+	if !outputs[1].Locked || outputs[1].UnlocksAt == nil || !outputs[1].UnlocksAt.Equal(unlocksAt) {
+		t.Fatal("Expected the second output to be locked until:", unlocksAt)
+	}
+
+	// Transactions without outputs have an empty list
+	outputs, err = reader.GetOutputsByTransaction(context.Background(), testID(3))
+	if err != nil {
+		t.Fatal("Failed to get outputs:", err.Error())
+	}
+	if outputs == nil || len(outputs) != 0 {
+		t.Fatal("Expected an empty list, got:", outputs)
+	}
+}
+
+func TestGetNotFound(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	ctx := context.Background()
+	missingID := testID(1)
+
+	tx, err := reader.GetTransaction(ctx, missingID)
+	if err != ErrTransactionNotFound || tx != nil {
+		t.Fatal("Expected ErrTransactionNotFound, got:", tx, err)
+	}
+	asset, err := reader.GetAsset(ctx, missingID.String())
+	if err != ErrAssetNotFound || asset != nil {
+		t.Fatal("Expected ErrAssetNotFound, got:", asset, err)
+	}
+	addr, err := reader.GetAddress(ctx, testShortID(1))
+	if err != ErrAddressNotFound || addr != nil {
+		t.Fatal("Expected ErrAddressNotFound, got:", addr, err)
+	}
+	output, err := reader.GetOutput(ctx, missingID.Prefix(0))
+	if err != ErrOutputNotFound || output != nil {
+		t.Fatal("Expected ErrOutputNotFound, got:", output, err)
+	}
+
+	// Each getter's error wraps ErrNotFound
+	for _, err := range []error{ErrTransactionNotFound, ErrAssetNotFound, ErrAddressNotFound, ErrOutputNotFound} {
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatal("Expected error to wrap ErrNotFound:", err)
+		}
+	}
+
+	// List methods still return empty lists
+	txList, err := reader.ListTransactions(ctx, &params.ListTransactionsParams{ID: &missingID})
+	if err != nil || len(txList.Transactions) != 0 {
+		t.Fatal("Expected an empty list, got:", txList, err)
+	}
+}
+
+func TestGetAssetsByIDs(t *testing.T) {
+	_, reader, closeFn := newTestIndex(t, 5, testXChainID)
+	defer closeFn()
+
+	sess := reader.conns.DB().NewSession("test")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	for i, denomination := range []uint8{0, 6, 9} {
+		insertTestAsset(t, sess, testID(byte(101+i)), testXChainID.String(), denomination, now)
+	}
+
+	// Asset 104 doesn't exist and asset 103 isn't requested
+	assets, err := reader.GetAssetsByIDs(context.Background(), []ids.ID{testID(101), testID(102), testID(104)})
+	if err != nil {
+		t.Fatal("Failed to get assets:", err.Error())
+	}
+	if len(assets) != 2 {
+		t.Fatal("Incorrect number of assets:", len(assets))
+	}
+	for id, denomination := range map[ids.ID]uint8{testID(101): 0, testID(102): 6} {
+		asset, ok := assets[models.ToStringID(id)]
+		if !ok {
+			t.Fatal("Missing asset:", id.String())
+		}
+		if asset.Denomination != denomination {
+			t.Fatal("Incorrect denomination:", asset.Denomination)
+		}
+	}
+	if _, ok := assets[models.ToStringID(testID(104))]; ok {
+		t.Fatal("Unexpected asset:", testID(104).String())
+	}
+
+	assets, err = reader.GetAssetsByIDs(context.Background(), nil)
+	if err != nil || assets == nil || len(assets) != 0 {
+		t.Fatal("Expected no assets without ids:", assets, err)
+	}
+}
+
+func newTestIndex(t testing.TB, networkID uint32, chainID ids.ID) (*Writer, *Reader, func()) {
 	// Start test redis
 	s, err := miniredis.Run()
 	if err != nil {
@@ -90,3 +4983,120 @@ func newTestContext() context.Context {
 	time.AfterFunc(5*time.Second, cancelFn)
 	return ctx
 }
+
+func testID(i byte) ids.ID {
+	return ids.NewID([32]byte{i})
+}
+
+func testShortID(i byte) ids.ShortID {
+	return ids.NewShortID([20]byte{i})
+}
+
+func insertTestTransaction(t testing.TB, sess dbr.SessionRunner, id ids.ID, createdAt time.Time) {
+	_, err := sess.
+		InsertInto("avm_transactions").
+		Pair("id", id.String()).
+		Pair("chain_id", testXChainID.String()).
+		Pair("type", models.TransactionTypeBase.String()).
+		Pair("canonical_serialization", []byte{}).
+		Pair("created_at", createdAt).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to insert transaction:", err.Error())
+	}
+}
+
+func insertTestOutput(t testing.TB, sess dbr.SessionRunner, txID ids.ID, idx uint64, assetID ids.ID, amount uint64, addr ids.ShortID, createdAt time.Time) {
+	outputID := txID.Prefix(idx)
+	_, err := sess.
+		InsertInto("avm_outputs").
+		Pair("id", outputID.String()).
+		Pair("chain_id", testXChainID.String()).
+		Pair("transaction_id", txID.String()).
+		Pair("output_index", idx).
+		Pair("asset_id", assetID.String()).
+		Pair("output_type", models.OutputTypesSECP2556K1Transfer).
+		Pair("amount", amount).
+		Pair("locktime", 0).
+		Pair("threshold", 1).
+		Pair("created_at", createdAt).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to insert output:", err.Error())
+	}
+
+	_, err = sess.
+		InsertInto("avm_output_addresses").
+		Pair("output_id", outputID.String()).
+		Pair("address", addr.String()).
+		Pair("created_at", createdAt).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to insert output address:", err.Error())
+	}
+}
+
+func insertTestAsset(t *testing.T, sess dbr.SessionRunner, id ids.ID, chainID string, denomination uint8, createdAt time.Time) {
+	_, err := sess.
+		InsertInto("avm_assets").
+		Pair("id", id.String()).
+		Pair("chain_id", chainID).
+		Pair("name", "test").
+		Pair("symbol", "TEST").
+		Pair("alias", "").
+		Pair("denomination", denomination).
+		Pair("current_supply", 0).
+		Pair("created_at", createdAt).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to insert asset:", err.Error())
+	}
+}
+
+func spendTestOutput(t testing.TB, sess dbr.SessionRunner, outputID ids.ID, txID ids.ID) {
+	_, err := sess.
+		Update("avm_outputs").
+		Set("redeeming_transaction_id", txID.String()).
+		Where("id = ?", outputID.String()).
+		Exec()
+	if err != nil {
+		t.Fatal("Failed to spend output:", err.Error())
+	}
+}
+
+func assertTransactionIDs(t *testing.T, txs []*models.Transaction, expected []ids.ID) {
+	if len(txs) != len(expected) {
+		t.Fatalf("Incorrect number of transactions: expected %d, got %d", len(expected), len(txs))
+	}
+
+	found := make(map[models.StringID]struct{}, len(txs))
+	for _, tx := range txs {
+		found[tx.ID] = struct{}{}
+	}
+	for _, id := range expected {
+		if _, ok := found[models.ToStringID(id)]; !ok {
+			t.Fatal("Missing transaction:", id.String())
+		}
+	}
+}
+
+func TestReaderErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{ErrQueryTimeout, 503},
+		{ErrTooBusy, 503},
+		{errIntervalCountTooLarge(MaxAggregateIntervalCount + 1), 400},
+		{params.ErrInvalidAmountRange, 400},
+		{params.ErrIntervalRangeNotAligned, 400},
+		{fmt.Errorf("%w: %d", params.ErrUndefinedOutputType, 99), 400},
+		{ErrDBUnavailable, 0},
+		{errors.New("connection refused"), 0},
+	}
+	for _, test := range tests {
+		if code := readerErrorCode(test.err); code != test.code {
+			t.Errorf("%v: expected code %d, got %d", test.err, test.code, code)
+		}
+	}
+}
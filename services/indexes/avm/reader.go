@@ -5,16 +5,27 @@ package avm
 
 import (
 	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/big"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/gocraft/dbr/v2"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/ava-labs/ortelius/services"
+	"github.com/ava-labs/ortelius/services/cache"
+	"github.com/ava-labs/ortelius/services/indexes/avax"
 	"github.com/ava-labs/ortelius/services/indexes/models"
 	"github.com/ava-labs/ortelius/services/indexes/params"
 )
@@ -22,15 +33,99 @@ import (
 const (
 	MaxAggregateIntervalCount = 20000
 	MinSearchQueryLength      = 1
+
+	// MinTransactionIDPrefixLength is the shortest search query that's looked
+	// up as the start of a transaction ID
+	MinTransactionIDPrefixLength = 8
+
+	DefaultFirstTransactionTimeTTL = 24 * time.Hour
+
+	// DefaultAggregateCacheTTL is the longest an asset's aggregates are cached
+	// for, in case the Writer's invalidation of them is missed
+	DefaultAggregateCacheTTL = 10 * time.Minute
+
+	// MaxAggregateCacheEntries is the most aggregate histograms a Reader caches
+	MaxAggregateCacheEntries = 1000
+
+	// MaxCountCacheEntries is the most approximate counts a Reader caches
+	MaxCountCacheEntries = 1000
+
+	// DefaultQueryTimeout bounds the time each Reader method spends querying
+	DefaultQueryTimeout = 30 * time.Second
+
+	// HealthCheckTimeout is the time the DB has to respond to a health check
+	// before it's considered too slow
+	HealthCheckTimeout = 1 * time.Second
+
+	// CSVExportBatchSize is the number of rows written between flushes when
+	// exporting to CSV
+	CSVExportBatchSize = 1000
+
+	// UTXOSnapshotBatchSize is the number of outputs loaded at a time when
+	// snapshotting UTXOs
+	UTXOSnapshotBatchSize = 1000
+
+	// StreamOutputsBatchSize is the number of outputs loaded at a time when
+	// streaming them as JSON
+	StreamOutputsBatchSize = 500
+
+	// IntegrityScanBatchSize is the number of outputs or transactions checked
+	// at a time when scanning for inconsistencies
+	IntegrityScanBatchSize = 1000
+
+	// MaxIntegrityScanResults is the most inconsistencies of each kind that
+	// DetectInconsistentOutputs returns
+	MaxIntegrityScanResults = 1000
+
+	// DefaultInQueryBatchSize is the most IDs put in the IN list of a single
+	// query when loading related rows, such as the addresses of outputs
+	DefaultInQueryBatchSize = 1000
+
+	// MaxRecentAssets is the most assets ListRecentAssets returns
+	MaxRecentAssets = 100
+
+	// MaxTopAssetsByVolume is the most assets TopAssetsByVolume returns
+	MaxTopAssetsByVolume = 100
+
+	// VelocityPrecision is the number of decimal places velocities are rounded
+	// to
+	VelocityPrecision = 8
 )
 
 var (
+	// ErrNotFound is wrapped by the errors the Get methods return when nothing
+	// matches, so callers can check for any of them with errors.Is
+	ErrNotFound = errors.New("not found")
+
+	ErrOutputNotFound      = fmt.Errorf("output %w", ErrNotFound)
+	ErrTransactionNotFound = fmt.Errorf("transaction %w", ErrNotFound)
+	ErrAssetNotFound       = fmt.Errorf("asset %w", ErrNotFound)
+	ErrAddressNotFound     = fmt.Errorf("address %w", ErrNotFound)
+
 	ErrAggregateIntervalCountTooLarge = errors.New("requesting too many intervals")
 	ErrFailedToParseStringAsBigInt    = errors.New("failed to parse string to big.Int")
 	ErrSearchQueryTooShort            = errors.New("search query too short")
+	ErrQueryTimeout                   = errors.New("query timed out")
+	ErrDBUnavailable                  = errors.New("db unavailable")
+	ErrDBSlow                         = errors.New("db too slow")
+	ErrAmbiguousAlias                 = errors.New("alias matches multiple assets")
+	ErrTooBusy                        = errors.New("too many concurrent expensive queries")
+	ErrUnknownChainAlias              = errors.New("unknown chain alias")
+	ErrSpentOnOtherChain              = errors.New("output spent by a transaction on a chain that isn't indexed")
 )
 
 var (
+	// feelessTransactionTypes are the types of transactions that may output
+	// more than they spend, by creating or minting assets or by importing them
+	// from another chain that may not be indexed
+	feelessTransactionTypes = []string{
+		models.TransactionTypeCreateAsset.String(),
+		models.TransactionTypeOperation.String(),
+		models.TransactionTypeAVMImport.String(),
+		models.TransactionTypePVMImport.String(),
+		models.TransactionTypeCVMImport.String(),
+	}
+
 	outputSelectColumns = []string{
 		"avm_outputs.id",
 		"avm_outputs.transaction_id",
@@ -47,350 +142,2745 @@ var (
 	}
 )
 
+// base58Alphabet holds the characters IDs are encoded with
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// Reader queries the index for a single chain. List methods are scoped to the
+// Reader's chain unless their params contain ChainIDs, which take precedence.
 type Reader struct {
-	chainID string
-	conns   *services.Connections
+	chainID      string
+	conns        *services.Connections
+	queryTimeout time.Duration
+
+	// inQueryBatchSize is the most IDs put in a single IN list, or < 1 for no
+	// limit
+	inQueryBatchSize int
+
+	sessionNamePrefix string
+	queryHook         QueryHook
+
+	// chainAliases maps lowercase chain aliases to chain IDs
+	chainAliases map[string]string
+
+	// expensiveQueries holds a token for each expensive query in progress. It's
+	// nil when they aren't limited.
+	expensiveQueries chan struct{}
+
+	firstTxTimeTTL   time.Duration
+	firstTxTimeLock  sync.Mutex
+	firstTxTimeCache map[string]firstTxTimeCacheEntry
+
+	aggregateCacheTTL  time.Duration
+	aggregateCacheLock sync.Mutex
+	aggregateCache     map[string]aggregateCacheEntry
+
+	// countCacheTTL is how old a cached count may get before it's refreshed in
+	// the background. Counts aren't cached when it's < 1.
+	countCacheTTL  time.Duration
+	countCacheLock sync.Mutex
+	countCache     map[string]*countCacheEntry
+}
+
+type firstTxTimeCacheEntry struct {
+	ts        time.Time
+	expiresAt time.Time
+}
+
+type aggregateCacheEntry struct {
+	histogram   *models.AggregatesHistogram
+	generations string
+	expiresAt   time.Time
 }
 
-func NewReader(conns *services.Connections, chainID string) *Reader {
-	return &Reader{
-		conns:   conns,
-		chainID: chainID,
+type countCacheEntry struct {
+	count      uint64
+	loadedAt   time.Time
+	refreshing bool
+}
+
+// ReaderOption configures optional behavior of a Reader
+type ReaderOption func(*Reader)
+
+// WithFirstTransactionTimeTTL sets how long the time of the first transaction
+// is cached for. A ttl < 1 disables the cache.
+func WithFirstTransactionTimeTTL(ttl time.Duration) ReaderOption {
+	return func(r *Reader) { r.firstTxTimeTTL = ttl }
+}
+
+// WithAggregateCacheTTL sets how long the aggregates of an asset are cached
+// for. They're invalidated sooner when the Writer of one of their chains
+// writes. A ttl < 1, or having no redis connection, disables the cache.
+func WithAggregateCacheTTL(ttl time.Duration) ReaderOption {
+	return func(r *Reader) { r.aggregateCacheTTL = ttl }
+}
+
+// WithApproximateCounts makes the List methods serve the count of a page past
+// the first from a cache instead of counting on every request, flagging the
+// list as approximate. A count older than ttl is refreshed in the background
+// while the cached one is served, one refresh per query at a time. The first
+// request for a query and those setting ExactCount are counted exactly. A
+// ttl < 1 disables approximate counts, which is the default.
+func WithApproximateCounts(ttl time.Duration) ReaderOption {
+	return func(r *Reader) { r.countCacheTTL = ttl }
+}
+
+// WithQueryTimeout sets the maximum time each Reader method may spend querying,
+// unless the context passed to it has an earlier deadline. A timeout < 1
+// removes the limit. Exports aren't limited since they're expected to be long.
+func WithQueryTimeout(timeout time.Duration) ReaderOption {
+	return func(r *Reader) { r.queryTimeout = timeout }
+}
+
+// WithSessionNamePrefix prefixes the name of every DB session the Reader
+// creates, which attributes its queries in the DB's instrumentation and logs
+func WithSessionNamePrefix(prefix string) ReaderOption {
+	return func(r *Reader) { r.sessionNamePrefix = prefix }
+}
+
+// WithMaxExpensiveQueries limits the number of aggregate and output list
+// queries the Reader runs at once, so that they can't exhaust the DB's
+// connections and block cheap lookups. A limit < 1 removes the limit.
+func WithMaxExpensiveQueries(limit int) ReaderOption {
+	return func(r *Reader) {
+		r.expensiveQueries = nil
+		if limit > 0 {
+			r.expensiveQueries = make(chan struct{}, limit)
+		}
+	}
+}
+
+// QueryHook is called after each query the Reader loads with the session's
+// name, the SQL, and how long the query took. dbr interpolates the bound
+// values into the SQL before running it, so they appear there and args is
+// currently always nil.
+type QueryHook func(sessionName, sql string, args []interface{}, dur time.Duration)
+
+// WithQueryHook calls hook after every select query the Reader runs, whether
+// it succeeded or not, for example to log slow queries. A nil hook disables
+// it.
+func WithQueryHook(hook QueryHook) ReaderOption {
+	return func(r *Reader) { r.queryHook = hook }
+}
+
+// WithInQueryBatchSize sets the most IDs put in the IN list of a single query
+// when loading the addresses and inputs and outputs of listed items, or the
+// balances of listed addresses. Longer lists are split into several queries
+// whose results are merged. A size < 1 puts all IDs in a single query.
+func WithInQueryBatchSize(size int) ReaderOption {
+	return func(r *Reader) { r.inQueryBatchSize = size }
+}
+
+// WithChainAliases lets the Reader resolve the aliases, such as "X", to the
+// chain IDs they map to. Aliases are case insensitive.
+func WithChainAliases(aliases map[string]string) ReaderOption {
+	return func(r *Reader) {
+		for alias, chainID := range aliases {
+			r.chainAliases[strings.ToLower(alias)] = chainID
+		}
+	}
+}
+
+func NewReader(conns *services.Connections, chainID string, opts ...ReaderOption) *Reader {
+	r := &Reader{
+		conns:            conns,
+		chainID:          chainID,
+		queryTimeout:     DefaultQueryTimeout,
+		inQueryBatchSize: DefaultInQueryBatchSize,
+
+		chainAliases: map[string]string{},
+
+		firstTxTimeTTL:   DefaultFirstTransactionTimeTTL,
+		firstTxTimeCache: map[string]firstTxTimeCacheEntry{},
+
+		aggregateCacheTTL: DefaultAggregateCacheTTL,
+		aggregateCache:    map[string]aggregateCacheEntry{},
+
+		countCache: map[string]*countCacheEntry{},
+	}
+	for _, opt := range opts {
+		opt(r)
 	}
+	return r
 }
 
-func (r *Reader) Search(ctx context.Context, p *params.SearchParams) (*models.SearchResults, error) {
+func (r *Reader) Search(ctx context.Context, p *params.SearchParams) (_ *models.SearchResults, err error) {
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
+
 	if len(p.Query) < MinSearchQueryLength {
 		return nil, ErrSearchQueryTooShort
 	}
 
 	// See if the query string is an id or shortID. If so we can search on them
-	// directly. Otherwise we treat the query as a normal query-string.
+	// directly. Otherwise we treat the query as a normal query-string, unless
+	// it's an address with an invalid checksum.
 	if shortID, err := params.AddressFromString(p.Query); err == nil {
 		return r.searchByShortID(ctx, shortID)
+	} else if errors.Is(err, params.ErrInvalidAddress) {
+		return nil, err
 	}
 	if id, err := ids.FromString(p.Query); err == nil {
 		return r.searchByID(ctx, id)
 	}
-
-	// copy the list params, and inject DisableCounting for subsequent List* calls.
-	cpListParams := p.ListParams
-	cpListParams.DisableCounting = true
+	if isIDPrefix(p.Query) {
+		results, err := r.searchByTransactionIDPrefix(ctx, p)
+		if err != nil || results != nil {
+			return results, err
+		}
+	}
+	if results, err := r.searchByAssetAlias(ctx, p.Query); err != nil || results != nil {
+		return results, err
+	}
 
 	// The query string was not an id/shortid so perform a regular search against
-	// all models
-	assets, err := r.ListAssets(ctx, &params.ListAssetsParams{ListParams: cpListParams, Query: p.Query})
+	// all models. Results are ordered by type and then by each type's own
+	// ordering, so the combined list is paged through category by category.
+	listers := []searchLister{
+		func(ctx context.Context, lp params.ListParams) ([]models.SearchResult, uint64, error) {
+			assets, err := r.ListAssets(ctx, &params.ListAssetsParams{ListParams: lp, Query: p.Query, Sort: params.AssetSortRelevance})
+			if err != nil {
+				return nil, 0, err
+			}
+			results := make([]models.SearchResult, len(assets.Assets))
+			for i, asset := range assets.Assets {
+				results[i] = models.SearchResult{SearchResultType: models.ResultTypeAsset, Data: asset, Score: asset.Score}
+			}
+			return results, assets.Count, nil
+		},
+		func(ctx context.Context, lp params.ListParams) ([]models.SearchResult, uint64, error) {
+			addresses, err := r.ListAddresses(ctx, &params.ListAddressesParams{ListParams: lp, Query: p.Query})
+			if err != nil {
+				return nil, 0, err
+			}
+			results := make([]models.SearchResult, len(addresses.Addresses))
+			for i, address := range addresses.Addresses {
+				results[i] = models.SearchResult{SearchResultType: models.ResultTypeAddress, Data: address}
+			}
+			return results, addresses.Count, nil
+		},
+		func(ctx context.Context, lp params.ListParams) ([]models.SearchResult, uint64, error) {
+			transactions, err := r.ListTransactions(ctx, &params.ListTransactionsParams{ListParams: lp, Query: p.Query})
+			if err != nil {
+				return nil, 0, err
+			}
+			results := make([]models.SearchResult, len(transactions.Transactions))
+			for i, tx := range transactions.Transactions {
+				results[i] = models.SearchResult{SearchResultType: models.ResultTypeTransaction, Data: tx}
+			}
+			return results, transactions.Count, nil
+		},
+	}
+
+	offset, limit := p.Offset, p.EffectiveLimit()
+	if limit == 0 {
+		limit = p.EffectiveMaxLimit()
+	}
+
+	searchResults := &models.SearchResults{Results: make([]models.SearchResult, 0, limit)}
+	for _, list := range listers {
+		// Once the page is full the remaining categories are only needed for
+		// the total count
+		if limit == 0 && p.DisableCounting {
+			break
+		}
+
+		// The size of each category is needed to find where the page starts
+		// in the next one, even when counting is disabled
+		lp := params.ListParams{Offset: offset, Limit: limit, DisableCounting: p.DisableCounting && offset == 0, MaxLimit: p.MaxLimit}
+		if limit == 0 {
+			lp = params.ListParams{Limit: 1}
+		}
+
+		results, count, err := list(ctx, lp)
+		if err != nil {
+			return nil, err
+		}
+
+		// Counting reports the offset for pages past the end of a category, so
+		// it must be recounted from the start
+		if lp.Offset > 0 && len(results) == 0 {
+			if _, count, err = list(ctx, params.ListParams{Limit: 1}); err != nil {
+				return nil, err
+			}
+		}
+		if limit == 0 {
+			results = nil
+		}
+
+		searchResults.Count += count
+		searchResults.Results = append(searchResults.Results, results...)
+		limit -= len(results)
+		if offset -= int(count); offset < 0 {
+			offset = 0
+		}
+	}
+
+	if p.DisableCounting {
+		searchResults.Count = 0
+	}
+
+	return searchResults, nil
+}
+
+// searchLister returns a page of the search results of a single type along
+// with the total number of results of that type
+type searchLister func(context.Context, params.ListParams) ([]models.SearchResult, uint64, error)
+
+// Aggregate computes the aggregates of the outputs created in the time range.
+// The volume sums the amounts of every asset as if they were fungible, so it's
+// only meaningful when the params restrict it to a single asset. Use
+// AggregateByAsset for the volume of each asset.
+func (r *Reader) Aggregate(ctx context.Context, params *params.AggregateParams) (_ *models.AggregatesHistogram, err error) {
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
+
+	// Without an end time the latest interval is still changing, so only
+	// aggregates of an asset over a fixed time range are cached
+	cacheable := params.AssetID != nil && !params.EndTime.IsZero()
+
+	requestedIntervalCount, err := r.prepareAggregateParams(ctx, params)
 	if err != nil {
 		return nil, err
 	}
-	if len(assets.Assets) >= p.Limit {
-		return collateSearchResults(assets, nil, nil, nil)
+
+	var cacheKey, generations string
+	if cacheable {
+		cacheKey = aggregateCacheKey(params)
+		generations, cacheable = r.aggregateGenerations(ctx, params.ChainIDs, params.StartTime, params.EndTime)
+	}
+	if cacheable {
+		if aggs, ok := r.getCachedAggregates(cacheKey, generations); ok {
+			return aggs, nil
+		}
+	}
+
+	release, err := r.acquireExpensiveQuery(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer release()
 
-	transactions, err := r.ListTransactions(ctx, &params.ListTransactionsParams{ListParams: cpListParams, Query: p.Query})
+	aggs, err := r.loadAggregatesHistogram(ctx, params, requestedIntervalCount)
 	if err != nil {
 		return nil, err
 	}
-	if len(transactions.Transactions) >= p.Limit {
-		return collateSearchResults(assets, nil, transactions, nil)
+
+	if cacheable {
+		r.cacheAggregates(cacheKey, generations, aggs)
 	}
+	return aggs, nil
+}
 
-	addresses, err := r.ListAddresses(ctx, &params.ListAddressesParams{ListParams: cpListParams, Query: p.Query})
+// loadAggregatesHistogram loads the histogram of the aggregates matching the
+// prepared params
+func (r *Reader) loadAggregatesHistogram(ctx context.Context, params *params.AggregateParams, requestedIntervalCount int) (*models.AggregatesHistogram, error) {
+	// Load the base data
+	dbRunner := r.newSession("get_transaction_aggregates_histogram")
+
+	rows, err := r.loadAggregates(ctx, dbRunner, params, requestedIntervalCount, "", uint64(requestedIntervalCount))
 	if err != nil {
 		return nil, err
 	}
-	if len(addresses.Addresses) >= p.Limit {
-		return collateSearchResults(assets, addresses, transactions, nil)
+
+	intervals := make([]models.Aggregates, len(rows))
+	for i, row := range rows {
+		intervals[i] = row.Aggregates
 	}
 
-	return collateSearchResults(assets, addresses, transactions, nil)
-}
+	aggs, err := buildAggregatesHistogram(params, requestedIntervalCount, intervals)
+	if err != nil {
+		return nil, err
+	}
 
-func (r *Reader) Aggregate(ctx context.Context, params *params.AggregateParams) (*models.AggregatesHistogram, error) {
-	// Validate params and set defaults if necessary
-	if params.StartTime.IsZero() {
-		var err error
-		params.StartTime, err = r.getFirstTransactionTime(ctx, params.ChainIDs)
+	if params.GroupByChain {
+		aggs.Chains, err = r.aggregateByChain(ctx, dbRunner, params)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	// Ensure the interval count requested isn't too large
-	intervalSeconds := int64(params.IntervalSize.Seconds())
-	requestedIntervalCount := 0
-	if intervalSeconds != 0 {
-		requestedIntervalCount = int(math.Ceil(params.EndTime.Sub(params.StartTime).Seconds() / params.IntervalSize.Seconds()))
-		if requestedIntervalCount > MaxAggregateIntervalCount {
-			return nil, ErrAggregateIntervalCountTooLarge
-		}
-		if requestedIntervalCount < 1 {
-			requestedIntervalCount = 1
+	if params.AssetID != nil {
+		if err = r.addVelocities(ctx, dbRunner, *params.AssetID, aggs); err != nil {
+			return nil, err
 		}
 	}
+	return aggs, nil
+}
 
-	// Build the query and load the base data
-	dbRunner := r.conns.DB().NewSession("get_transaction_aggregates_histogram")
+// aggregateCacheKey returns the key of the aggregates matching the prepared
+// params. Unlike params.CacheKey it uses the exact time range.
+func aggregateCacheKey(p *params.AggregateParams) string {
+	return cache.KeyFromParts(append(p.CacheKey(),
+		params.CacheKey("exactStartTime", p.StartTime.UnixNano()),
+		params.CacheKey("exactEndTime", p.EndTime.UnixNano()),
+	)...)
+}
 
-	columns := []string{
-		"COALESCE(SUM(avm_outputs.amount), 0) AS transaction_volume",
+// aggregateGenerations returns the current generations of the chains, or of
+// all chains if none are given, for each period of the time range. Writes
+// outside the range don't change them, so aggregates of closed ranges stay
+// cached. It returns false if the aggregates can't be cached because the cache
+// is disabled or redis is unavailable.
+func (r *Reader) aggregateGenerations(ctx context.Context, chainIDs []string, start time.Time, end time.Time) (string, bool) {
+	redisConn := r.conns.Redis()
+	if r.aggregateCacheTTL < 1 || redisConn == nil {
+		return "", false
+	}
 
-		"COUNT(DISTINCT(avm_outputs.transaction_id)) AS transaction_count",
-		"COUNT(DISTINCT(avm_output_addresses.address)) AS address_count",
-		"COUNT(DISTINCT(avm_outputs.asset_id)) AS asset_count",
-		"COUNT(avm_outputs.id) AS output_count",
+	generationChainIDs := []string{""}
+	if len(chainIDs) > 0 {
+		generationChainIDs = r.chainIDs(chainIDs)
+	}
+	keys := []string{}
+	for period := start.UTC().Truncate(avax.AggregatesGenerationPeriod); !period.After(end); period = period.Add(avax.AggregatesGenerationPeriod) {
+		for _, chainID := range generationChainIDs {
+			keys = append(keys, avax.AggregatesGenerationKey(chainID, period))
+		}
 	}
 
-	if requestedIntervalCount > 0 {
-		columns = append(columns, fmt.Sprintf(
-			"FLOOR((UNIX_TIMESTAMP(avm_outputs.created_at)-%d) / %d) AS idx",
-			params.StartTime.Unix(),
-			intervalSeconds))
+	values, err := redisConn.MGet(ctx, keys...).Result()
+	if err != nil {
+		return "", false
 	}
+	generations := make([]string, len(values))
+	for i, value := range values {
+		generations[i] = fmt.Sprint(value)
+	}
+	return strings.Join(generations, cache.CacheSeparator), true
+}
 
-	builder := params.Apply(dbRunner.
-		Select(columns...).
-		From("avm_outputs").
-		LeftJoin("avm_output_addresses", "avm_output_addresses.output_id = avm_outputs.id"))
+// getCachedAggregates returns a copy of the cached aggregates for key if they
+// haven't expired and were loaded at the given generations
+func (r *Reader) getCachedAggregates(key string, generations string) (*models.AggregatesHistogram, bool) {
+	r.aggregateCacheLock.Lock()
+	entry, ok := r.aggregateCache[key]
+	r.aggregateCacheLock.Unlock()
 
-	if requestedIntervalCount > 0 {
-		builder.
-			GroupBy("idx").
-			OrderAsc("idx").
-			Limit(uint64(requestedIntervalCount))
+	if !ok || entry.generations != generations || time.Now().After(entry.expiresAt) {
+		return nil, false
 	}
+	return copyAggregatesHistogram(entry.histogram), true
+}
 
-	intervals := []models.Aggregates{}
-	_, err := builder.LoadContext(ctx, &intervals)
-	if err != nil {
-		return nil, err
+// cacheAggregates caches a copy of aggs for key. If the cache is full then
+// expired entries are removed first, and then arbitrary ones if necessary.
+func (r *Reader) cacheAggregates(key string, generations string, aggs *models.AggregatesHistogram) {
+	now := time.Now()
+
+	r.aggregateCacheLock.Lock()
+	defer r.aggregateCacheLock.Unlock()
+
+	if len(r.aggregateCache) >= MaxAggregateCacheEntries {
+		for k, entry := range r.aggregateCache {
+			if now.After(entry.expiresAt) {
+				delete(r.aggregateCache, k)
+			}
+		}
+		for k := range r.aggregateCache {
+			if len(r.aggregateCache) < MaxAggregateCacheEntries {
+				break
+			}
+			delete(r.aggregateCache, k)
+		}
 	}
 
-	// If no intervals were requested then the total aggregate is equal to the
-	// first (and only) interval, and we're done
-	if requestedIntervalCount == 0 {
-		// This check should never fail if the SQL query is correct, but added for
-		// robustness to prevent panics if the invariant does not hold.
-		if len(intervals) > 0 {
-			intervals[0].StartTime = params.StartTime
-			intervals[0].EndTime = params.EndTime
-			return &models.AggregatesHistogram{Aggregates: intervals[0]}, nil
+	r.aggregateCache[key] = aggregateCacheEntry{
+		histogram:   copyAggregatesHistogram(aggs),
+		generations: generations,
+		expiresAt:   now.Add(r.aggregateCacheTTL),
+	}
+}
+
+// copyAggregatesHistogram copies aggs so that callers can't modify a cached
+// histogram
+func copyAggregatesHistogram(aggs *models.AggregatesHistogram) *models.AggregatesHistogram {
+	histogramCopy := *aggs
+	if aggs.Intervals != nil {
+		histogramCopy.Intervals = append([]models.Aggregates(nil), aggs.Intervals...)
+	}
+	if aggs.Chains != nil {
+		histogramCopy.Chains = make(map[string]models.Aggregates, len(aggs.Chains))
+		for chainID, chainAggs := range aggs.Chains {
+			histogramCopy.Chains[chainID] = chainAggs
 		}
-		return &models.AggregatesHistogram{}, nil
 	}
+	return &histogramCopy
+}
 
-	// We need to return multiple intervals so build them now.
-	// Intervals without any data will not return anything so we pad our results
-	// with empty aggregates.
-	//
-	// We also add the start and end times of each interval to that interval
-	aggs := &models.AggregatesHistogram{IntervalSize: params.IntervalSize}
+// addVelocities sets the velocity of the histogram of a single asset, overall
+// and for each interval with a volume. The velocity is the volume divided by
+// the asset's current supply, computed exactly and rounded half away from zero
+// to VelocityPrecision decimal places. It's left unset if the asset isn't
+// indexed or has no supply.
+func (r *Reader) addVelocities(ctx context.Context, dbRunner dbr.SessionRunner, assetID ids.ID, aggs *models.AggregatesHistogram) error {
+	var supplies []models.TokenAmount
+	_, err := dbRunner.
+		Select("current_supply").
+		From("avm_assets").
+		Where("id = ?", assetID.String()).
+		LoadContext(ctx, &supplies)
+	if err != nil || len(supplies) == 0 {
+		return err
+	}
 
-	var startTS int64
-	timesForInterval := func(intervalIdx int) (time.Time, time.Time) {
-		// An interval's start Time is its index Time the interval size, plus the
-		// starting Time. The end Time is (interval size - 1) seconds after the
-		// start Time.
-		startTS = params.StartTime.Unix() + (int64(intervalIdx) * intervalSeconds)
-		return time.Unix(startTS, 0).UTC(),
-			time.Unix(startTS+intervalSeconds-1, 0).UTC()
+	supply, ok := new(big.Int).SetString(string(supplies[0]), 10)
+	if !ok {
+		return ErrFailedToParseStringAsBigInt
+	}
+	if supply.Sign() <= 0 {
+		return nil
 	}
 
-	padTo := func(slice []models.Aggregates, to int) []models.Aggregates {
-		for i := len(slice); i < to; i = len(slice) {
-			slice = append(slice, models.Aggregates{Idx: i})
-			slice[i].StartTime, slice[i].EndTime = timesForInterval(i)
+	velocity := func(aggregates *models.Aggregates) error {
+		if aggregates.TransactionVolume == "" {
+			return nil
 		}
-		return slice
+		volume, ok := new(big.Int).SetString(string(aggregates.TransactionVolume), 10)
+		if !ok {
+			return ErrFailedToParseStringAsBigInt
+		}
+		aggregates.Velocity = new(big.Rat).SetFrac(volume, supply).FloatString(VelocityPrecision)
+		return nil
 	}
 
-	// Collect the overall counts and pad the intervals to include empty intervals
-	// which are not returned by the db
-	aggs.Aggregates = models.Aggregates{StartTime: params.StartTime, EndTime: params.EndTime}
-	var (
-		bigIntFromStringOK bool
-		totalVolume        = big.NewInt(0)
-		intervalVolume     = big.NewInt(0)
-	)
+	if err = velocity(&aggs.Aggregates); err != nil {
+		return err
+	}
+	for i := range aggs.Intervals {
+		if err = velocity(&aggs.Intervals[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	// Add each interval, but first pad up to that interval's index
-	aggs.Intervals = make([]models.Aggregates, 0, requestedIntervalCount)
-	for _, interval := range intervals {
-		// Pad up to this interval's position
-		aggs.Intervals = padTo(aggs.Intervals, interval.Idx)
+// aggregateByChain computes the aggregates of each chain over the whole time
+// range. Chains without any outputs in the range are omitted.
+func (r *Reader) aggregateByChain(ctx context.Context, dbRunner dbr.SessionRunner, params *params.AggregateParams) (map[string]models.Aggregates, error) {
+	rows, err := r.loadAggregates(ctx, dbRunner, params, 0, "avm_outputs.chain_id", 0)
+	if err != nil {
+		return nil, err
+	}
 
-		// Format this interval
-		interval.StartTime, interval.EndTime = timesForInterval(interval.Idx)
+	chains := make(map[string]models.Aggregates, len(rows))
+	for _, row := range rows {
+		row.Aggregates.StartTime = params.StartTime
+		row.Aggregates.EndTime = params.EndTime
+		chains[row.GroupKey] = row.Aggregates
+	}
+	return chains, nil
+}
 
-		// Parse volume into a big.Int
-		_, bigIntFromStringOK = intervalVolume.SetString(string(interval.TransactionVolume), 10)
-		if !bigIntFromStringOK {
-			return nil, ErrFailedToParseStringAsBigInt
-		}
+// AggregateByAsset computes the same histogram as Aggregate but broken down by
+// asset. Each asset's intervals are padded independently, and the
+// MaxAggregateIntervalCount limit applies to the total number of intervals
+// across all assets.
+func (r *Reader) AggregateByAsset(ctx context.Context, params *params.AggregateParams) (_ map[models.StringID]*models.AggregatesHistogram, err error) {
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
 
-		// Add to the overall aggregates counts
-		totalVolume.Add(totalVolume, intervalVolume)
-		aggs.Aggregates.TransactionCount += interval.TransactionCount
-		aggs.Aggregates.OutputCount += interval.OutputCount
-		aggs.Aggregates.AddressCount += interval.AddressCount
-		aggs.Aggregates.AssetCount += interval.AssetCount
+	release, err := r.acquireExpensiveQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
-		// Add to the list of intervals
-		aggs.Intervals = append(aggs.Intervals, interval)
+	requestedIntervalCount, err := r.prepareAggregateParams(ctx, params)
+	if err != nil {
+		return nil, err
 	}
-	// Add total aggregated token amounts
-	aggs.Aggregates.TransactionVolume = models.TokenAmount(totalVolume.String())
 
-	// Add any missing trailing intervals
-	aggs.Intervals = padTo(aggs.Intervals, requestedIntervalCount)
+	dbRunner := r.newSession("get_transaction_aggregates_histogram_by_asset")
 
-	return aggs, nil
+	rows, err := r.loadAggregates(ctx, dbRunner, params, requestedIntervalCount, "avm_outputs.asset_id", MaxAggregateIntervalCount+1)
+	if err != nil {
+		return nil, err
+	}
+	// Only one row past the max is loaded, so the total is unknown here
+	if len(rows) > MaxAggregateIntervalCount {
+		return nil, fmt.Errorf("%w: more than the max of %d intervals across all assets", ErrAggregateIntervalCountTooLarge, MaxAggregateIntervalCount)
+	}
+
+	// Group the rows by asset and ensure the padded total is still within bounds
+	intervalsByAsset := map[models.StringID][]models.Aggregates{}
+	for _, row := range rows {
+		assetID := models.StringID(row.GroupKey)
+		intervalsByAsset[assetID] = append(intervalsByAsset[assetID], row.Aggregates)
+	}
+	if count := len(intervalsByAsset) * requestedIntervalCount; count > MaxAggregateIntervalCount {
+		return nil, errIntervalCountTooLarge(count)
+	}
+
+	histograms := make(map[models.StringID]*models.AggregatesHistogram, len(intervalsByAsset))
+	for assetID, intervals := range intervalsByAsset {
+		histograms[assetID], err = buildAggregatesHistogram(params, requestedIntervalCount, intervals)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return histograms, nil
 }
 
-func (r *Reader) ListTransactions(ctx context.Context, p *params.ListTransactionsParams) (*models.TransactionList, error) {
-	dbRunner := r.conns.DB().NewSession("get_transactions")
+// TopAssetsByVolume returns the limit assets of the Reader's chain with the
+// largest volume of outputs created in [start, end), largest first with ties
+// broken by asset ID. A zero start or end leaves the window open on that side,
+// and the limit is capped at MaxTopAssetsByVolume.
+func (r *Reader) TopAssetsByVolume(ctx context.Context, start, end time.Time, limit int) (_ []models.AssetVolume, err error) {
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
 
-	txs := []*models.Transaction{}
-	builder := p.Apply(dbRunner.
-		Select("avm_transactions.id", "avm_transactions.chain_id", "avm_transactions.type", "avm_transactions.memo", "avm_transactions.created_at").
-		From("avm_transactions"))
-	if p.NeedsDistinct() {
-		builder = builder.Distinct()
+	release, err := r.acquireExpensiveQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if limit < 1 || limit > MaxTopAssetsByVolume {
+		limit = MaxTopAssetsByVolume
+	}
+
+	// The window is a single interval, so no histogram is built
+	p := &params.AggregateParams{StartTime: start, EndTime: end, ChainIDs: r.chainIDs(nil)}
+	if _, err = r.prepareAggregateParams(ctx, p); err != nil {
+		return nil, err
+	}
+
+	builder := r.newSession("top_assets_by_volume").
+		Select("avm_outputs.asset_id").
+		From("avm_outputs").
+		GroupBy("avm_outputs.asset_id").
+		OrderDesc("transaction_volume").
+		OrderAsc("avm_outputs.asset_id").
+		Limit(uint64(limit))
+	builder.Column = append(builder.Column, transactionVolumeColumn(p))
+
+	volumes := []models.AssetVolume{}
+	if _, err = p.Apply(builder).LoadContext(ctx, &volumes); err != nil {
+		return nil, err
+	}
+	return volumes, nil
+}
+
+// AggregateActiveAddresses computes only the number of distinct addresses
+// receiving outputs in each interval. It's much cheaper than Aggregate because
+// it doesn't compute volumes or count outputs, and only joins the outputs when
+// filtering by chain or asset. Intervals are padded the same way as Aggregate,
+// and the overall address count is the sum of the intervals' counts.
+func (r *Reader) AggregateActiveAddresses(ctx context.Context, params *params.AggregateParams) (_ *models.AggregatesHistogram, err error) {
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
+
+	release, err := r.acquireExpensiveQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	requestedIntervalCount, err := r.prepareAggregateParams(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := []string{"COUNT(DISTINCT(avm_output_addresses.address)) AS address_count"}
+	if requestedIntervalCount > 0 {
+		columns = append(columns, intervalIndexColumn(params, "avm_output_addresses.created_at"))
+	}
+
+	builder := r.newSession("get_active_addresses_histogram").
+		Select(columns...).
+		From("avm_output_addresses").
+		Where("avm_output_addresses.created_at >= ?", params.StartTime).
+		Where("avm_output_addresses.created_at < ?", params.EndTime)
+
+	if params.AssetID != nil || len(params.ChainIDs) > 0 {
+		builder.Join("avm_outputs", "avm_outputs.id = avm_output_addresses.output_id")
+		if params.AssetID != nil {
+			builder.Where("avm_outputs.asset_id = ?", params.AssetID.String())
+		}
+		if len(params.ChainIDs) > 0 {
+			builder.Where("avm_outputs.chain_id IN ?", params.ChainIDs)
+		}
+	}
+
+	if requestedIntervalCount > 0 {
+		builder.
+			GroupBy("idx").
+			OrderAsc("idx").
+			Limit(uint64(requestedIntervalCount))
+	}
+
+	intervals := []models.Aggregates{}
+	_, err = builder.LoadContext(ctx, &intervals)
+	if err != nil {
+		return nil, err
+	}
+
+	aggs, err := buildAggregatesHistogram(params, requestedIntervalCount, intervals)
+	if err != nil {
+		return nil, err
+	}
+
+	// No volume was selected so don't report one
+	aggs.Aggregates.TransactionVolume = ""
+	return aggs, nil
+}
+
+// AssetSupplyHistory computes the net change in the asset's supply over time,
+// which is the amount of the asset in outputs created during each interval
+// minus the amount in outputs spent during it. The params' AssetID is ignored.
+//
+// Only SECP256K1 transfer outputs are counted since mint outputs only grant
+// the right to mint and carry no amount. The asset's initial supply is created
+// by its CreateAssetTx and so counts as minted when the asset is created.
+// Transactions that consume more of the asset than they create, such as
+// transactions paying AVAX fees, count as burning the difference. Imports and
+// exports are counted the same way, by the outputs they create and spend on
+// the indexed chains. Outputs minted by operations aren't indexed and so
+// aren't reflected.
+func (r *Reader) AssetSupplyHistory(ctx context.Context, assetID ids.ID, params *params.AggregateParams) (_ *models.AggregatesHistogram, err error) {
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
+
+	release, err := r.acquireExpensiveQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	requestedIntervalCount, err := r.prepareAggregateParams(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	dbRunner := r.newSession("get_asset_supply_history")
+
+	// loadAmounts sums the amounts of the asset's outputs into each interval of
+	// the given timestamp column
+	loadAmounts := func(builder *dbr.SelectBuilder, timestampColumn string) (map[int]*big.Int, error) {
+		if requestedIntervalCount > 0 {
+			builder.Column = append(builder.Column, intervalIndexColumn(params, timestampColumn))
+			builder.GroupBy("idx")
+		}
+		if len(params.ChainIDs) > 0 {
+			builder.Where("avm_outputs.chain_id IN ?", params.ChainIDs)
+		}
+
+		rows := []*struct {
+			Idx    int
+			Amount models.TokenAmount
+		}{}
+		_, err := builder.
+			Where("avm_outputs.asset_id = ?", assetID.String()).
+			Where("avm_outputs.output_type = ?", models.OutputTypesSECP2556K1Transfer).
+			Where(timestampColumn+" >= ?", params.StartTime).
+			Where(timestampColumn+" < ?", params.EndTime).
+			LoadContext(ctx, &rows)
+		if err != nil {
+			return nil, err
+		}
+
+		amounts := make(map[int]*big.Int, len(rows))
+		for _, row := range rows {
+			amount, ok := new(big.Int).SetString(string(row.Amount), 10)
+			if !ok {
+				return nil, ErrFailedToParseStringAsBigInt
+			}
+			amounts[row.Idx] = amount
+		}
+		return amounts, nil
+	}
+
+	created, err := loadAmounts(dbRunner.
+		Select("COALESCE(SUM(avm_outputs.amount), 0) AS amount").
+		From("avm_outputs"), "avm_outputs.created_at")
+	if err != nil {
+		return nil, err
+	}
+
+	spent, err := loadAmounts(dbRunner.
+		Select("COALESCE(SUM(avm_outputs.amount), 0) AS amount").
+		From("avm_outputs").
+		Join("avm_transactions", "avm_transactions.id = avm_outputs.redeeming_transaction_id"), "avm_transactions.created_at")
+	if err != nil {
+		return nil, err
+	}
+
+	// Net the created and spent amounts of each interval
+	changes := make(map[int]*big.Int, len(created))
+	for idx, amount := range created {
+		changes[idx] = amount
+	}
+	for idx, amount := range spent {
+		if _, ok := changes[idx]; !ok {
+			changes[idx] = big.NewInt(0)
+		}
+		changes[idx].Sub(changes[idx], amount)
+	}
+
+	idxs := make([]int, 0, len(changes))
+	for idx := range changes {
+		idxs = append(idxs, idx)
+	}
+	sort.Ints(idxs)
+
+	total := big.NewInt(0)
+	intervals := make([]models.Aggregates, len(idxs))
+	for i, idx := range idxs {
+		total.Add(total, changes[idx])
+		intervals[i] = models.Aggregates{Idx: idx, SupplyChange: models.TokenAmount(changes[idx].String())}
+	}
+
+	aggs, err := buildAggregatesHistogram(params, requestedIntervalCount, intervals)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the supply change was computed
+	aggs.Aggregates.TransactionVolume = ""
+	aggs.Aggregates.SupplyChange = models.TokenAmount(total.String())
+	return aggs, nil
+}
+
+// AggregateNFTGroups returns the number of NFT outputs, distinct current
+// holders, and last activity time of each NFT group of the asset, ordered by
+// group ID. Only NFT transfer outputs are counted; mint outputs carry a group
+// ID too but don't hold an NFT. Every other output type is stored with a
+// group_id of 0, so they're excluded rather than reported as group 0, while an
+// NFT actually minted into group 0 is still reported.
+func (r *Reader) AggregateNFTGroups(ctx context.Context, assetID ids.ID) (_ []models.NFTGroupStats, err error) {
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
+
+	release, err := r.acquireExpensiveQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	rows := []*struct {
+		GroupID        uint32
+		OutputCount    uint64
+		HolderCount    uint64
+		LastCreatedAt  time.Time
+		LastRedeemedAt *time.Time
+	}{}
+	_, err = r.newSession("aggregate_nft_groups").
+		Select(
+			"avm_outputs.group_id",
+			"COUNT(DISTINCT avm_outputs.id) AS output_count",
+			"COUNT(DISTINCT CASE WHEN avm_outputs.redeeming_transaction_id = '' THEN avm_output_addresses.address END) AS holder_count",
+			"MAX(avm_outputs.created_at) AS last_created_at",
+			"MAX(avm_transactions.created_at) AS last_redeemed_at",
+		).
+		From("avm_outputs").
+		LeftJoin("avm_output_addresses", "avm_output_addresses.output_id = avm_outputs.id").
+		LeftJoin("avm_transactions", "avm_transactions.id = avm_outputs.redeeming_transaction_id").
+		Where("avm_outputs.chain_id IN ?", r.chainIDs(nil)).
+		Where("avm_outputs.asset_id = ?", assetID.String()).
+		Where("avm_outputs.output_type = ?", models.OutputTypesNFTTransfer).
+		GroupBy("avm_outputs.group_id").
+		OrderAsc("avm_outputs.group_id").
+		LoadContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]models.NFTGroupStats, 0, len(rows))
+	for _, row := range rows {
+		lastActivity := row.LastCreatedAt
+		if row.LastRedeemedAt != nil && row.LastRedeemedAt.After(lastActivity) {
+			lastActivity = *row.LastRedeemedAt
+		}
+		groups = append(groups, models.NFTGroupStats{
+			GroupID:      row.GroupID,
+			OutputCount:  row.OutputCount,
+			HolderCount:  row.HolderCount,
+			LastActivity: lastActivity,
+		})
+	}
+	return groups, nil
+}
+
+// prepareAggregateParams validates the params, sets defaults if necessary, and
+// returns the number of intervals requested
+func (r *Reader) prepareAggregateParams(ctx context.Context, params *params.AggregateParams) (int, error) {
+	if params.StartTime.IsZero() {
+		var err error
+		params.StartTime, err = r.getFirstTransactionTime(ctx, params.ChainIDs)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if params.EndTime.IsZero() {
+		params.EndTime = time.Now().UTC()
+	}
+
+	// Interval boundaries are computed and returned in UTC, matching the
+	// session time zone, regardless of the location the caller gave
+	params.StartTime = params.StartTime.UTC()
+	params.EndTime = params.EndTime.UTC()
+	params.AlignEndTime()
+
+	if err := params.Validate(); err != nil {
+		return 0, err
+	}
+
+	// Ensure the interval count requested isn't too large
+	intervalSeconds := int64(params.IntervalSize.Seconds())
+	requestedIntervalCount := 0
+	if intervalSeconds != 0 {
+		requestedIntervalCount = int(math.Ceil(params.EndTime.Sub(params.StartTime).Seconds() / params.IntervalSize.Seconds()))
+		if requestedIntervalCount > MaxAggregateIntervalCount {
+			return 0, errIntervalCountTooLarge(requestedIntervalCount)
+		}
+		if requestedIntervalCount < 1 {
+			requestedIntervalCount = 1
+		}
+	}
+	return requestedIntervalCount, nil
+}
+
+// errIntervalCountTooLarge wraps ErrAggregateIntervalCountTooLarge with the
+// number of intervals requested so clients can tell how to narrow the range
+func errIntervalCountTooLarge(requested int) error {
+	return fmt.Errorf("%w: requested %d intervals, max %d", ErrAggregateIntervalCountTooLarge, requested, MaxAggregateIntervalCount)
+}
+
+// aggregateColumns are the aggregates computed from the outputs alone, other
+// than the transaction volume which depends on the params. The address count
+// is loaded separately by loadAggregates.
+var aggregateColumns = []string{
+	"COUNT(DISTINCT(avm_outputs.transaction_id)) AS transaction_count",
+	"COUNT(DISTINCT(avm_outputs.asset_id)) AS asset_count",
+	"COUNT(avm_outputs.id) AS output_count",
+}
+
+// aggregateRow holds the aggregates of a single interval of a group of outputs
+type aggregateRow struct {
+	GroupKey string
+	models.Aggregates
+}
+
+// loadAggregates loads the aggregates of each interval of each group of
+// outputs matching params, ordered by group and then interval. groupColumn
+// may be empty to aggregate all outputs together, and a limit of 0 loads every
+// row. Outputs have a row in avm_output_addresses for each of their addresses,
+// so the addresses are counted by a separate query to avoid counting each
+// output, and its amount, once per address.
+func (r *Reader) loadAggregates(ctx context.Context, dbRunner dbr.SessionRunner, params *params.AggregateParams, requestedIntervalCount int, groupColumn string, limit uint64) ([]*aggregateRow, error) {
+	group := func(b *dbr.SelectBuilder) *dbr.SelectBuilder {
+		var groupBy []string
+		if groupColumn != "" {
+			b.Column = append(b.Column, groupColumn+" AS group_key")
+			groupBy = append(groupBy, "group_key")
+			b.OrderAsc("group_key")
+		}
+		if requestedIntervalCount > 0 {
+			b.Column = append(b.Column, intervalIndexColumn(params, "avm_outputs.created_at"))
+			groupBy = append(groupBy, "idx")
+			b.OrderAsc("idx")
+		}
+		if len(groupBy) > 0 {
+			b.GroupBy(groupBy...)
+		}
+		if limit > 0 {
+			b.Limit(limit)
+		}
+		return params.Apply(b)
+	}
+
+	rows := []*aggregateRow{}
+	builder := dbRunner.
+		Select(aggregateColumns...).
+		From("avm_outputs")
+	builder.Column = append(builder.Column, transactionVolumeColumn(params))
+	_, err := group(builder).LoadContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every group with addresses also has outputs and both are ordered the
+	// same way, so the same limit loads the address count of every row
+	addressCounts := []*aggregateRow{}
+	_, err = group(dbRunner.
+		Select("COUNT(DISTINCT(avm_output_addresses.address)) AS address_count").
+		From("avm_outputs").
+		Join("avm_output_addresses", "avm_output_addresses.output_id = avm_outputs.id")).
+		LoadContext(ctx, &addressCounts)
+	if err != nil {
+		return nil, err
+	}
+
+	type rowKey struct {
+		group string
+		idx   int
+	}
+	addressCountsByKey := make(map[rowKey]uint64, len(addressCounts))
+	for _, row := range addressCounts {
+		addressCountsByKey[rowKey{row.GroupKey, row.Idx}] = row.AddressCount
+	}
+	for _, row := range rows {
+		row.AddressCount = addressCountsByKey[rowKey{row.GroupKey, row.Idx}]
+	}
+	return rows, nil
+}
+
+// transactionVolumeColumn returns the column summing the amounts of the
+// outputs, other than those of the types excluded from the volume
+func transactionVolumeColumn(params *params.AggregateParams) interface{} {
+	if len(params.VolumeExcludedOutputTypes) == 0 {
+		return "COALESCE(SUM(avm_outputs.amount), 0) AS transaction_volume"
+	}
+	return dbr.Expr("COALESCE(SUM(CASE WHEN avm_outputs.output_type IN ? THEN 0 ELSE avm_outputs.amount END), 0) AS transaction_volume",
+		params.VolumeExcludedOutputTypes)
+}
+
+// intervalIndexColumn returns the column selecting the index of the interval
+// that the timestamp column falls in
+func intervalIndexColumn(params *params.AggregateParams, timestampColumn string) string {
+	return fmt.Sprintf(
+		"FLOOR((UNIX_TIMESTAMP(%s)-%d) / %d) AS idx",
+		timestampColumn,
+		params.StartTime.Unix(),
+		int64(params.IntervalSize.Seconds()))
+}
+
+// buildAggregatesHistogram turns the intervals loaded from the db into a
+// histogram, padding out any intervals for which the db returned no data
+func buildAggregatesHistogram(params *params.AggregateParams, requestedIntervalCount int, intervals []models.Aggregates) (*models.AggregatesHistogram, error) {
+	// If no intervals were requested then the total aggregate is equal to the
+	// first (and only) interval, and we're done
+	if requestedIntervalCount == 0 {
+		// This check should never fail if the SQL query is correct, but added for
+		// robustness to prevent panics if the invariant does not hold.
+		if len(intervals) > 0 {
+			intervals[0].StartTime = params.StartTime
+			intervals[0].EndTime = params.EndTime
+			return &models.AggregatesHistogram{Aggregates: intervals[0]}, nil
+		}
+		return &models.AggregatesHistogram{}, nil
+	}
+
+	// We need to return multiple intervals so build them now.
+	// Intervals without any data will not return anything so we pad our results
+	// with empty aggregates.
+	//
+	// We also add the start and end times of each interval to that interval
+	aggs := &models.AggregatesHistogram{IntervalSize: params.IntervalSize}
+	if !params.RequestedEndTime.IsZero() {
+		requestedEndTime := params.RequestedEndTime
+		aggs.RequestedEndTime = &requestedEndTime
+	}
+	intervalSeconds := int64(params.IntervalSize.Seconds())
+
+	var startTS int64
+	timesForInterval := func(intervalIdx int) (time.Time, time.Time) {
+		// An interval's start Time is its index Time the interval size, plus the
+		// starting Time. The end Time is (interval size - 1) seconds after the
+		// start Time.
+		startTS = params.StartTime.Unix() + (int64(intervalIdx) * intervalSeconds)
+		return time.Unix(startTS, 0).UTC(),
+			time.Unix(startTS+intervalSeconds-1, 0).UTC()
+	}
+
+	// Only the requested window of intervals is kept, so padding starts at the
+	// window's first interval and stops at its end
+	start, end := params.IntervalWindow(requestedIntervalCount)
+	aggs.IntervalCount = requestedIntervalCount
+	padTo := func(slice []models.Aggregates, to int) []models.Aggregates {
+		if to > end {
+			to = end
+		}
+		for i := start + len(slice); i < to; i = start + len(slice) {
+			interval := models.Aggregates{Idx: i}
+			interval.StartTime, interval.EndTime = timesForInterval(i)
+			slice = append(slice, interval)
+		}
+		return slice
+	}
+
+	// Collect the overall counts and pad the intervals to include empty intervals
+	// which are not returned by the db
+	aggs.Aggregates = models.Aggregates{StartTime: params.StartTime, EndTime: params.EndTime}
+	var (
+		totalVolume    = &models.Amount{}
+		intervalVolume = &models.Amount{}
+	)
+
+	// Add each interval in the window, but first pad up to that interval's
+	// index. Every interval is added to the overall counts.
+	aggs.Intervals = make([]models.Aggregates, 0, end-start)
+	for _, interval := range intervals {
+		// Parse volume into an Amount. It's empty if it wasn't selected.
+		intervalVolume.Int().SetInt64(0)
+		if interval.TransactionVolume != "" {
+			if err := intervalVolume.SetTokenAmount(interval.TransactionVolume); err != nil {
+				return nil, ErrFailedToParseStringAsBigInt
+			}
+		}
+
+		// Add to the overall aggregates counts
+		totalVolume.Add(intervalVolume)
+		aggs.Aggregates.TransactionCount += interval.TransactionCount
+		aggs.Aggregates.OutputCount += interval.OutputCount
+		aggs.Aggregates.AddressCount += interval.AddressCount
+		aggs.Aggregates.AssetCount += interval.AssetCount
+
+		if interval.Idx < start || interval.Idx >= end {
+			continue
+		}
+
+		// Pad up to this interval's position
+		aggs.Intervals = padTo(aggs.Intervals, interval.Idx)
+
+		// Format this interval and add it to the list of intervals
+		interval.StartTime, interval.EndTime = timesForInterval(interval.Idx)
+		aggs.Intervals = append(aggs.Intervals, interval)
+	}
+	// Add total aggregated token amounts
+	aggs.Aggregates.TransactionVolume = totalVolume.TokenAmount()
+
+	// Add any missing trailing intervals
+	aggs.Intervals = padTo(aggs.Intervals, end)
+
+	return aggs, nil
+}
+
+func (r *Reader) ListTransactions(ctx context.Context, p *params.ListTransactionsParams) (_ *models.TransactionList, err error) {
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	dbRunner := r.newSession("get_transactions")
+	p.ChainIDs = r.chainIDs(p.ChainIDs)
+
+	if p.CountOnly {
+		count, _, err := r.countTransactions(ctx, dbRunner, *p, true)
+		if err != nil {
+			return nil, err
+		}
+		return &models.TransactionList{ListMetadata: models.ListMetadata{Count: count}, Transactions: []*models.Transaction{}}, nil
+	}
+
+	columns := []string{"avm_transactions.id", "avm_transactions.chain_id", "avm_transactions.type", "avm_transactions.memo", "avm_transactions.created_at", "avm_transactions.height"}
+	if p.IncludeSerialization {
+		columns = append(columns, "avm_transactions.canonical_serialization")
+	}
+
+	txs := []*models.Transaction{}
+	builder := p.Apply(dbRunner.
+		Select(columns...).
+		From("avm_transactions"))
+	if p.NeedsDistinct() {
+		builder = builder.Distinct()
 	}
 
 	var applySort func(sort params.TransactionSort)
 	applySort = func(sort params.TransactionSort) {
-		if p.Query != "" {
-			return
+		switch sort {
+		case params.TransactionSortTimestampAsc:
+			builder.OrderAsc("avm_transactions.chain_id")
+			builder.OrderAsc("avm_transactions.created_at")
+			builder.OrderAsc("avm_transactions.id")
+		case params.TransactionSortTimestampDesc:
+			builder.OrderAsc("avm_transactions.chain_id")
+			builder.OrderDesc("avm_transactions.created_at")
+			builder.OrderDesc("avm_transactions.id")
+		case params.TransactionSortVolumeAsc, params.TransactionSortVolumeDesc:
+			// The volume is the sum of all output amounts regardless of asset.
+			// It's selected so that it can be ordered by when using DISTINCT.
+			// Outputs are on the chain of the transaction creating them, so only
+			// the listed chains' outputs are summed.
+			builder.Column = append(builder.Column, "COALESCE(avm_transaction_volumes.volume, 0) AS volume")
+			builder.LeftJoin(dbRunner.
+				Select("transaction_id", "CAST(SUM(amount) AS DECIMAL(65)) AS volume").
+				From("avm_outputs").
+				Where("avm_outputs.chain_id IN ?", p.ChainIDs).
+				GroupBy("transaction_id").
+				As("avm_transaction_volumes"),
+				"avm_transaction_volumes.transaction_id = avm_transactions.id")
+			if sort == params.TransactionSortVolumeAsc {
+				builder.OrderAsc("volume")
+			} else {
+				builder.OrderDesc("volume")
+			}
+			builder.OrderAsc("avm_transactions.created_at")
+			builder.OrderAsc("avm_transactions.id")
+		default:
+			applySort(params.TransactionSortDefault)
+		}
+	}
+	// Syncing by height needs a deterministic order to page through
+	if p.MinHeight != nil {
+		builder.OrderAsc("avm_transactions.height")
+		builder.OrderAsc("avm_transactions.id")
+	} else {
+		applySort(p.Sort)
+	}
+
+	if _, err := applyPeekLimit(builder, p.ListParams).LoadContext(ctx, &txs); err != nil {
+		return nil, err
+	}
+
+	pageLen, hasMore := trimPage(p.ListParams, len(txs))
+	txs = txs[:pageLen]
+
+	var (
+		count       uint64
+		approximate bool
+	)
+	if !p.DisableCounting {
+		count = uint64(p.Offset) + uint64(len(txs))
+		if len(txs) >= p.Limit {
+			if count, approximate, err = r.countTransactions(ctx, dbRunner, *p, p.ExactCount); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Add all the addition information we might want
+	if err := r.dressTransactions(ctx, dbRunner, txs, p.Light, p.IncludeAssets); err != nil {
+		return nil, err
+	}
+
+	return &models.TransactionList{ListMetadata: models.ListMetadata{Count: count, HasMore: hasMore, Approximate: approximate}, Transactions: txs}, nil
+}
+
+// countTransactions counts every transaction matching p, ignoring pagination.
+// The count may be approximate unless exact is set, see loadCount.
+func (r *Reader) countTransactions(ctx context.Context, dbRunner dbr.SessionRunner, p params.ListTransactionsParams, exact bool) (count uint64, approximate bool, err error) {
+	p.ListParams = params.ListParams{}
+	countColumn := "COUNT(avm_transactions.id)"
+	if p.NeedsDistinct() {
+		countColumn = "COUNT(DISTINCT(avm_transactions.id))"
+	}
+	return r.loadCount(ctx, p.Apply(dbRunner.
+		Select(countColumn).
+		From("avm_transactions")), exact)
+}
+
+func (r *Reader) ListAssets(ctx context.Context, p *params.ListAssetsParams) (_ *models.AssetList, err error) {
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	dbRunner := r.newSession("list_assets")
+	p.ChainIDs = r.chainIDs(p.ChainIDs)
+
+	if p.CountOnly {
+		count, _, err := r.countAssets(ctx, dbRunner, *p, true)
+		if err != nil {
+			return nil, err
+		}
+		return &models.AssetList{ListMetadata: models.ListMetadata{Count: count}, Assets: []*models.Asset{}}, nil
+	}
+
+	// An asset's ID is the ID of the transaction that created it
+	assets := []*models.Asset{}
+	builder := p.Apply(dbRunner.
+		Select("id", "chain_id", "name", "symbol", "alias", "denomination", "current_supply", "created_at", "id AS created_by_transaction_id").
+		From("avm_assets"))
+
+	if p.Sort == params.AssetSortRelevance {
+		// Exact symbol matches score 2 and symbol or name prefix matches 1, so
+		// searching for a symbol surfaces that asset before larger assets
+		// that merely contain it
+		query := strings.ToUpper(p.Query)
+		builder.Column = append(builder.Column, dbr.Expr("CASE "+
+			"WHEN UPPER(avm_assets.symbol) = ? THEN 2 "+
+			"WHEN LOCATE(?, UPPER(avm_assets.symbol)) = 1 OR LOCATE(?, UPPER(avm_assets.name)) = 1 THEN 1 "+
+			"ELSE 0 END AS score", query, query, query))
+		builder.OrderDesc("score")
+		builder.OrderDesc("avm_assets.current_supply")
+	}
+	if p.Sort == params.AssetSortCreatedDesc {
+		builder.OrderDesc("avm_assets.created_at")
+		builder.OrderDesc("avm_assets.id")
+	} else {
+		builder.OrderAsc("avm_assets.created_at")
+		builder.OrderAsc("avm_assets.id")
+	}
+
+	if _, err = applyPeekLimit(builder, p.ListParams).LoadContext(ctx, &assets); err != nil {
+		return nil, err
+	}
+
+	pageLen, hasMore := trimPage(p.ListParams, len(assets))
+	assets = assets[:pageLen]
+
+	var nextCursor string
+	if p.StartAfter != nil && hasMore {
+		last := assets[len(assets)-1]
+		nextCursor = params.Cursor{CreatedAt: last.CreatedAt, ID: string(last.ID)}.String()
+	}
+
+	var (
+		count       uint64
+		approximate bool
+	)
+	if !p.DisableCounting {
+		count = uint64(p.Offset) + uint64(len(assets))
+
+		// When paginating by cursor the previous pages are unknown so we always
+		// count, ignoring the cursor position
+		if len(assets) >= p.Limit || p.StartAfter != nil {
+			if count, approximate, err = r.countAssets(ctx, dbRunner, *p, p.ExactCount); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &models.AssetList{ListMetadata: models.ListMetadata{Count: count, HasMore: hasMore, Approximate: approximate}, Assets: assets, NextCursor: nextCursor}, nil
+}
+
+// ListRecentAssets returns up to limit of the most recently created assets,
+// newest first. The limit is capped at MaxRecentAssets, and a limit < 1 returns
+// MaxRecentAssets assets. Counting is skipped.
+func (r *Reader) ListRecentAssets(ctx context.Context, limit int) ([]*models.Asset, error) {
+	if limit < 1 || limit > MaxRecentAssets {
+		limit = MaxRecentAssets
+	}
+
+	p := &params.ListAssetsParams{Sort: params.AssetSortCreatedDesc}
+	p.Limit = limit
+	p.DisableCounting = true
+	assetList, err := r.ListAssets(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return assetList.Assets, nil
+}
+
+// TotalSupply returns the current supply of every asset of the chain, which is
+// the Reader's chain if chainID is empty. Supplies of different assets aren't
+// comparable so they're not summed.
+func (r *Reader) TotalSupply(ctx context.Context, chainID string) (_ models.AssetTokenCounts, err error) {
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
+
+	var chainIDs []string
+	if chainID != "" {
+		chainIDs = []string{chainID}
+	}
+
+	var assets []*struct {
+		ID            models.StringID
+		Denomination  uint8
+		CurrentSupply models.TokenAmount
+	}
+	_, err = r.newSession("total_supply").
+		Select("id", "denomination", "current_supply").
+		From("avm_assets").
+		Where("chain_id IN ?", r.chainIDs(chainIDs)).
+		LoadContext(ctx, &assets)
+	if err != nil {
+		return nil, err
+	}
+
+	supplies := make(models.AssetTokenCounts, len(assets))
+	for _, asset := range assets {
+		denomination := asset.Denomination
+		supplies[asset.ID] = models.AssetTokenCount{Amount: asset.CurrentSupply, Denomination: &denomination}
+	}
+	return supplies, nil
+}
+
+// countAssets counts every asset matching p, ignoring pagination and the
+// cursor position. The count may be approximate unless exact is set, see
+// loadCount.
+func (r *Reader) countAssets(ctx context.Context, dbRunner dbr.SessionRunner, p params.ListAssetsParams, exact bool) (count uint64, approximate bool, err error) {
+	p.ListParams = params.ListParams{}
+	p.StartAfter = nil
+	return r.loadCount(ctx, p.Apply(dbRunner.
+		Select("COUNT(avm_assets.id)").
+		From("avm_assets")), exact)
+}
+
+func (r *Reader) ListAddresses(ctx context.Context, p *params.ListAddressesParams) (_ *models.AddressList, err error) {
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
+
+	dbRunner := r.newSession("list_addresses")
+	p.ChainIDs = r.chainIDs(p.ChainIDs)
+
+	if p.CountOnly {
+		count, _, err := r.countAddresses(ctx, dbRunner, *p, true)
+		if err != nil {
+			return nil, err
+		}
+		return &models.AddressList{ListMetadata: models.ListMetadata{Count: count}, Addresses: []*models.AddressInfo{}}, nil
+	}
+
+	builder := p.Apply(dbRunner.
+		Select("avm_output_addresses.address", "addresses.public_key").
+		Distinct().
+		From("avm_output_addresses").
+		LeftJoin("addresses", "addresses.address = avm_output_addresses.address"))
+
+	// The balance is selected so the DISTINCT query can be ordered by it. Ties
+	// are broken by address so that pages are stable.
+	if p.SortByBalance && p.AssetID != nil {
+		builder.Column = append(builder.Column, params.UnspentBalanceColumn+" AS balance")
+		builder.OrderDesc("balance")
+	}
+	builder.OrderAsc("avm_output_addresses.address")
+
+	addresses := []*models.AddressInfo{}
+	if _, err = applyPeekLimit(builder, p.ListParams).LoadContext(ctx, &addresses); err != nil {
+		return nil, err
+	}
+
+	pageLen, hasMore := trimPage(p.ListParams, len(addresses))
+	addresses = addresses[:pageLen]
+
+	var (
+		count       uint64
+		approximate bool
+	)
+	if !p.DisableCounting {
+		count = uint64(p.Offset) + uint64(len(addresses))
+		if len(addresses) >= p.Limit {
+			if count, approximate, err = r.countAddresses(ctx, dbRunner, *p, p.ExactCount); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Add all the addition information we might want
+	if err = r.dressAddresses(ctx, dbRunner, addresses); err != nil {
+		return nil, err
+	}
+
+	return &models.AddressList{ListMetadata: models.ListMetadata{Count: count, HasMore: hasMore, Approximate: approximate}, Addresses: addresses}, nil
+}
+
+// countAddresses counts every address matching p, ignoring pagination. The
+// count may be approximate unless exact is set, see loadCount.
+func (r *Reader) countAddresses(ctx context.Context, dbRunner dbr.SessionRunner, p params.ListAddressesParams, exact bool) (count uint64, approximate bool, err error) {
+	p.ListParams = params.ListParams{}
+
+	// Grouped queries return one row per address, so they must be counted from
+	// a subquery to honor the HAVING clause
+	var countBuilder *dbr.SelectBuilder
+	if p.NeedsGrouping() {
+		countBuilder = dbRunner.
+			Select("COUNT(*)").
+			From(p.Apply(dbRunner.
+				Select("avm_output_addresses.address").
+				From("avm_output_addresses")).
+				As("addresses_with_balance"))
+	} else {
+		countBuilder = p.Apply(dbRunner.
+			Select("COUNT(DISTINCT(avm_output_addresses.address))").
+			From("avm_output_addresses"))
+	}
+	return r.loadCount(ctx, countBuilder, exact)
+}
+
+func (r *Reader) ListOutputs(ctx context.Context, p *params.ListOutputsParams) (_ *models.OutputList, err error) {
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
+
+	release, err := r.acquireExpensiveQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return r.listOutputs(ctx, p)
+}
+
+// listOutputs lists outputs without being limited as an expensive query, for
+// cheap lookups
+func (r *Reader) listOutputs(ctx context.Context, p *params.ListOutputsParams) (_ *models.OutputList, err error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	dbRunner := r.newSession("list_transaction_outputs")
+	p.ChainIDs = r.chainIDs(p.ChainIDs)
+
+	if p.CountOnly {
+		count, _, err := r.countOutputs(ctx, dbRunner, *p, true)
+		if err != nil {
+			return nil, err
+		}
+		return &models.OutputList{ListMetadata: models.ListMetadata{Count: count}, Outputs: []*models.Output{}}, nil
+	}
+
+	outputs := []*models.Output{}
+	builder := p.Apply(dbRunner.
+		Select(outputSelectColumns...).
+		From("avm_outputs"))
+	if p.NeedsDistinct() {
+		builder = builder.Distinct()
+	}
+	if p.IncludeRedeemedAt {
+		builder.Column = append(builder.Column, "redeeming_transactions.created_at AS redeemed_at")
+		builder.LeftJoin(dbr.I("avm_transactions").As("redeeming_transactions"), "redeeming_transactions.id = avm_outputs.redeeming_transaction_id")
+	}
+
+	// The amount is an unsigned integer column so it's ordered numerically
+	switch p.Sort {
+	case params.OutputSortTimestampDesc:
+		builder.
+			OrderDesc("avm_outputs.created_at").
+			OrderDesc("avm_outputs.id")
+	case params.OutputSortAmountAsc, params.OutputSortAmountDesc:
+		if p.Sort == params.OutputSortAmountAsc {
+			builder.OrderAsc("avm_outputs.amount")
+		} else {
+			builder.OrderDesc("avm_outputs.amount")
+		}
+		builder.
+			OrderAsc("avm_outputs.created_at").
+			OrderAsc("avm_outputs.id")
+	default:
+		builder.
+			OrderAsc("avm_outputs.created_at").
+			OrderAsc("avm_outputs.id")
+	}
+	_, err = applyPeekLimit(builder, p.ListParams).LoadContext(ctx, &outputs)
+	if err != nil {
+		return nil, err
+	}
+
+	pageLen, hasMore := trimPage(p.ListParams, len(outputs))
+	outputs = outputs[:pageLen]
+
+	var nextCursor string
+	if p.StartAfter != nil && hasMore {
+		last := outputs[len(outputs)-1]
+		nextCursor = params.Cursor{CreatedAt: last.CreatedAt, ID: string(last.ID)}.String()
+	}
+
+	if len(outputs) < 1 && p.StartAfter == nil {
+		return &models.OutputList{Outputs: outputs}, nil
+	}
+
+	if p.AddressesIncluded() {
+		if err = r.loadOutputAddresses(ctx, dbRunner, outputs); err != nil {
+			return nil, err
+		}
+	}
+	setOutputLockStatuses(outputs, time.Now())
+	if p.DecodePayloads {
+		decodeOutputPayloads(outputs)
+	}
+
+	var (
+		count       uint64
+		approximate bool
+	)
+	if !p.DisableCounting {
+		count = uint64(p.Offset) + uint64(len(outputs))
+
+		// When paginating by cursor the previous pages are unknown so we always
+		// count, ignoring the cursor position
+		if len(outputs) >= p.Limit || p.StartAfter != nil {
+			if count, approximate, err = r.countOutputs(ctx, dbRunner, *p, p.ExactCount); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &models.OutputList{ListMetadata: models.ListMetadata{Count: count, HasMore: hasMore, Approximate: approximate}, Outputs: outputs, NextCursor: nextCursor}, err
+}
+
+// countOutputs counts every output matching p, ignoring pagination and the
+// cursor position. The count may be approximate unless exact is set, see
+// loadCount.
+func (r *Reader) countOutputs(ctx context.Context, dbRunner dbr.SessionRunner, p params.ListOutputsParams, exact bool) (count uint64, approximate bool, err error) {
+	p.ListParams = params.ListParams{}
+	p.StartAfter = nil
+	countColumn := "COUNT(avm_outputs.id)"
+	if p.NeedsDistinct() {
+		countColumn = "COUNT(DISTINCT(avm_outputs.id))"
+	}
+	return r.loadCount(ctx, p.Apply(dbRunner.
+		Select(countColumn).
+		From("avm_outputs")), exact)
+}
+
+// ExportOutputsCSV streams the outputs matching p to w as CSV, ordered by
+// (created_at, id). The header row contains the names of outputSelectColumns
+// without the table prefix: id, transaction_id, output_index, asset_id,
+// output_type, amount, locktime, threshold, created_at,
+// redeeming_transaction_id, group_id, payload. Timestamps are RFC3339 and
+// payloads are hex encoded. Addresses are not included so that memory use
+// doesn't grow with the number of outputs. A zero limit exports all outputs.
+func (r *Reader) ExportOutputsCSV(ctx context.Context, p *params.ListOutputsParams, w io.Writer) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	release, err := r.acquireExpensiveQuery(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	dbRunner := r.newSession("export_outputs_csv")
+	p.ChainIDs = r.chainIDs(p.ChainIDs)
+
+	builder := p.Apply(dbRunner.
+		Select(outputSelectColumns...).
+		From("avm_outputs")).
+		OrderAsc("avm_outputs.created_at").
+		OrderAsc("avm_outputs.id")
+	if p.NeedsDistinct() {
+		builder = builder.Distinct()
+	}
+
+	rows, err := builder.RowsContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	csvWriter := csv.NewWriter(w)
+
+	// Write the header and find the binary payload column
+	payloadIdx := -1
+	record := make([]string, len(outputSelectColumns))
+	for i, column := range outputSelectColumns {
+		record[i] = strings.TrimPrefix(column, "avm_outputs.")
+		if record[i] == "payload" {
+			payloadIdx = i
+		}
+	}
+	if err = csvWriter.Write(record); err != nil {
+		return err
+	}
+
+	values := make([]sql.NullString, len(outputSelectColumns))
+	dest := make([]interface{}, len(values))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	for rowCount := 1; rows.Next(); rowCount++ {
+		if err = rows.Scan(dest...); err != nil {
+			return err
+		}
+
+		for i, value := range values {
+			record[i] = value.String
+		}
+		if payloadIdx >= 0 {
+			record[payloadIdx] = hex.EncodeToString([]byte(values[payloadIdx].String))
+		}
+
+		if err = csvWriter.Write(record); err != nil {
+			return err
+		}
+
+		// Flush each batch and stop if the caller has gone away
+		if rowCount%CSVExportBatchSize == 0 {
+			if err = flushCSV(csvWriter); err != nil {
+				return err
+			}
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	return flushCSV(csvWriter)
+}
+
+// StreamOutputs writes the outputs matching p to w as a JSON array, the same as
+// marshaling the Outputs listed by ListOutputs, but loads and writes them in
+// batches of StreamOutputsBatchSize so memory use doesn't grow with the number
+// of outputs. Outputs are ordered by (created_at, id), starting after
+// p.StartAfter if it's set, so other sorts are rejected. A zero limit streams
+// all outputs, and counting is skipped.
+func (r *Reader) StreamOutputs(ctx context.Context, p *params.ListOutputsParams, w io.Writer) error {
+	return r.streamOutputs(ctx, p, StreamOutputsBatchSize, w)
+}
+
+func (r *Reader) streamOutputs(ctx context.Context, p *params.ListOutputsParams, batchSize int, w io.Writer) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	release, err := r.acquireExpensiveQuery(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if _, err = io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	// Each batch starts after the last output of the previous one, and only
+	// the first skips the offset
+	batch := *p
+	batch.ListParams = params.ListParams{Offset: p.Offset, DisableCounting: true}
+	batch.StartAfter = &params.Cursor{}
+	if p.StartAfter != nil {
+		*batch.StartAfter = *p.StartAfter
+	}
+	for written := 0; p.Limit == 0 || written < p.Limit; {
+		batch.Limit = batchSize
+		if p.Limit != 0 && p.Limit-written < batchSize {
+			batch.Limit = p.Limit - written
+		}
+
+		outputList, err := r.listOutputs(ctx, &batch)
+		if err != nil {
+			return err
+		}
+
+		for _, output := range outputList.Outputs {
+			if written > 0 {
+				if _, err = io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+			outputJSON, err := json.Marshal(output)
+			if err != nil {
+				return err
+			}
+			if _, err = w.Write(outputJSON); err != nil {
+				return err
+			}
+			written++
+		}
+
+		if !outputList.HasMore {
+			break
+		}
+		last := outputList.Outputs[len(outputList.Outputs)-1]
+		batch.StartAfter = &params.Cursor{CreatedAt: last.CreatedAt, ID: string(last.ID)}
+		batch.Offset = 0
+
+		// Stop if the caller has gone away
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	_, err = io.WriteString(w, "]")
+	return err
+}
+
+// SnapshotUTXOs calls fn with each unspent output of the asset, with its
+// addresses, in (created_at, id) order. Outputs are loaded in batches of
+// UTXOSnapshotBatchSize so memory use doesn't grow with the number of UTXOs,
+// and no DB connection is held while fn runs. Outputs created or spent during
+// the snapshot may or may not be included. The snapshot stops at the first
+// error returned by fn, which is returned.
+func (r *Reader) SnapshotUTXOs(ctx context.Context, assetID ids.ID, fn func(*models.Output) error) error {
+	return r.snapshotUTXOs(ctx, assetID, UTXOSnapshotBatchSize, fn)
+}
+
+func (r *Reader) snapshotUTXOs(ctx context.Context, assetID ids.ID, batchSize int, fn func(*models.Output) error) error {
+	dbRunner := r.newSession("snapshot_utxos")
+
+	cursor := params.Cursor{}
+	for {
+		outputs := []*models.Output{}
+		_, err := cursor.Apply(dbRunner.
+			Select(outputSelectColumns...).
+			From("avm_outputs").
+			Where("avm_outputs.asset_id = ?", assetID.String()).
+			Where("avm_outputs.chain_id IN ?", r.chainIDs(nil)).
+			Where("avm_outputs.redeeming_transaction_id = ''"), "avm_outputs").
+			OrderAsc("avm_outputs.created_at").
+			OrderAsc("avm_outputs.id").
+			Limit(uint64(batchSize)).
+			LoadContext(ctx, &outputs)
+		if err != nil {
+			return err
+		}
+
+		if err = r.loadOutputAddresses(ctx, dbRunner, outputs); err != nil {
+			return err
+		}
+
+		for _, output := range outputs {
+			if err = fn(output); err != nil {
+				return err
+			}
+		}
+
+		if len(outputs) < batchSize {
+			return nil
+		}
+		last := outputs[len(outputs)-1]
+		cursor = params.Cursor{CreatedAt: last.CreatedAt, ID: string(last.ID)}
+	}
+}
+
+// DetectInconsistentOutputs scans the index for records that contradict each
+// other. Accepted transactions are final, so these point to indexing bugs. It
+// reports spent outputs whose redeeming transaction isn't indexed or was
+// accepted before the output was created, and transactions whose outputs of an
+// asset add up to more than they spend of it. Transactions of
+// feelessTransactionTypes aren't checked for fees.
+//
+// The scan is read only and checks IntegrityScanBatchSize rows at a time. At
+// most limit inconsistencies of each kind are returned, with HasMore set if
+// there may be more. The limit is capped at MaxIntegrityScanResults, and a
+// limit < 1 returns up to MaxIntegrityScanResults.
+func (r *Reader) DetectInconsistentOutputs(ctx context.Context, limit int) (*models.IntegrityReport, error) {
+	return r.detectInconsistentOutputs(ctx, limit, IntegrityScanBatchSize)
+}
+
+func (r *Reader) detectInconsistentOutputs(ctx context.Context, limit int, batchSize int) (*models.IntegrityReport, error) {
+	if limit < 1 || limit > MaxIntegrityScanResults {
+		limit = MaxIntegrityScanResults
+	}
+
+	release, err := r.acquireExpensiveQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	dbRunner := r.newSession("detect_inconsistent_outputs")
+
+	// One more than the limit is scanned for to tell whether there are more
+	report := &models.IntegrityReport{}
+	if report.Outputs, err = r.scanRedeemedOutputs(ctx, dbRunner, limit+1, batchSize); err != nil {
+		return nil, err
+	}
+	if report.Transactions, err = r.scanTransactionFees(ctx, dbRunner, limit+1, batchSize); err != nil {
+		return nil, err
+	}
+
+	if len(report.Outputs) > limit {
+		report.Outputs = report.Outputs[:limit]
+		report.HasMore = true
+	}
+	if len(report.Transactions) > limit {
+		report.Transactions = report.Transactions[:limit]
+		report.HasMore = true
+	}
+	return report, nil
+}
+
+// scanRedeemedOutputs finds spent outputs whose redeeming transaction isn't
+// indexed or was accepted before them, in ID order, stopping once it has found
+// at least limit
+func (r *Reader) scanRedeemedOutputs(ctx context.Context, dbRunner dbr.SessionRunner, limit int, batchSize int) ([]*models.OutputInconsistency, error) {
+	inconsistencies := []*models.OutputInconsistency{}
+	lastID := ""
+	for len(inconsistencies) < limit {
+		outputs := []*models.Output{}
+		_, err := dbRunner.
+			Select("id", "transaction_id", "redeeming_transaction_id", "created_at").
+			From("avm_outputs").
+			Where("chain_id IN ?", r.chainIDs(nil)).
+			Where("redeeming_transaction_id != ''").
+			Where("id > ?", lastID).
+			OrderAsc("id").
+			Limit(uint64(batchSize)).
+			LoadContext(ctx, &outputs)
+		if err != nil {
+			return nil, err
 		}
-		switch sort {
-		case params.TransactionSortTimestampAsc:
-			builder.OrderAsc("avm_transactions.chain_id")
-			builder.OrderAsc("avm_transactions.created_at")
-		case params.TransactionSortTimestampDesc:
-			builder.OrderAsc("avm_transactions.chain_id")
-			builder.OrderDesc("avm_transactions.created_at")
+		if len(outputs) == 0 {
+			break
+		}
+
+		// Redeeming transactions may be on other chains, such as a P-chain
+		// import of an X-chain export, so they're looked up on any chain
+		redeemerIDs := make([]string, 0, len(outputs))
+		seen := make(map[models.StringID]struct{}, len(outputs))
+		for _, output := range outputs {
+			if _, ok := seen[output.RedeemingTransactionID]; !ok {
+				seen[output.RedeemingTransactionID] = struct{}{}
+				redeemerIDs = append(redeemerIDs, string(output.RedeemingTransactionID))
+			}
+		}
+		redeemers := []*models.Transaction{}
+		_, err = dbRunner.
+			Select("id", "created_at").
+			From("avm_transactions").
+			Where("id IN ?", redeemerIDs).
+			LoadContext(ctx, &redeemers)
+		if err != nil {
+			return nil, err
+		}
+		redeemedAt := make(map[models.StringID]time.Time, len(redeemers))
+		for _, redeemer := range redeemers {
+			redeemedAt[redeemer.ID] = redeemer.CreatedAt
+		}
+
+		for _, output := range outputs {
+			var reason models.InconsistencyReason
+			switch ts, ok := redeemedAt[output.RedeemingTransactionID]; {
+			case !ok:
+				reason = models.InconsistencyRedeemerNotIndexed
+			case ts.Before(output.CreatedAt):
+				reason = models.InconsistencyRedeemedBeforeCreated
+			default:
+				continue
+			}
+			inconsistencies = append(inconsistencies, &models.OutputInconsistency{
+				ID:                     output.ID,
+				TransactionID:          output.TransactionID,
+				RedeemingTransactionID: output.RedeemingTransactionID,
+				Reason:                 reason,
+			})
+		}
+
+		if len(outputs) < batchSize {
+			break
+		}
+		lastID = string(outputs[len(outputs)-1].ID)
+	}
+	return inconsistencies, nil
+}
+
+// scanTransactionFees finds transactions whose outputs of an asset add up to
+// more than the outputs of it they spend, in transaction ID order, stopping
+// once it has found at least limit
+func (r *Reader) scanTransactionFees(ctx context.Context, dbRunner dbr.SessionRunner, limit int, batchSize int) ([]*models.FeeInconsistency, error) {
+	type assetAmount struct {
+		TransactionID models.StringID
+		AssetID       models.StringID
+		Amount        models.TokenAmount
+	}
+
+	inconsistencies := []*models.FeeInconsistency{}
+	lastID := ""
+	for len(inconsistencies) < limit {
+		txIDs := []string{}
+		_, err := dbRunner.
+			Select("id").
+			From("avm_transactions").
+			Where("chain_id IN ?", r.chainIDs(nil)).
+			Where("type NOT IN ?", feelessTransactionTypes).
+			Where("id > ?", lastID).
+			OrderAsc("id").
+			Limit(uint64(batchSize)).
+			LoadContext(ctx, &txIDs)
+		if err != nil {
+			return nil, err
+		}
+		if len(txIDs) == 0 {
+			break
+		}
+
+		// Spent outputs may be on other chains, such as the X-chain outputs
+		// spent by a P-chain import, so they're summed on any chain
+		inputs := []*assetAmount{}
+		_, err = dbRunner.
+			Select("redeeming_transaction_id AS transaction_id", "asset_id", "COALESCE(SUM(amount), 0) AS amount").
+			From("avm_outputs").
+			Where("redeeming_transaction_id IN ?", txIDs).
+			GroupBy("redeeming_transaction_id", "asset_id").
+			LoadContext(ctx, &inputs)
+		if err != nil {
+			return nil, err
+		}
+		outputs := []*assetAmount{}
+		_, err = dbRunner.
+			Select("transaction_id", "asset_id", "COALESCE(SUM(amount), 0) AS amount").
+			From("avm_outputs").
+			Where("transaction_id IN ?", txIDs).
+			GroupBy("transaction_id", "asset_id").
+			OrderAsc("transaction_id").
+			OrderAsc("asset_id").
+			LoadContext(ctx, &outputs)
+		if err != nil {
+			return nil, err
+		}
+
+		inputAmounts := make(map[[2]models.StringID]models.TokenAmount, len(inputs))
+		for _, input := range inputs {
+			inputAmounts[[2]models.StringID{input.TransactionID, input.AssetID}] = input.Amount
+		}
+		for _, output := range outputs {
+			inputAmount, ok := inputAmounts[[2]models.StringID{output.TransactionID, output.AssetID}]
+			if !ok {
+				inputAmount = "0"
+			}
+			in, err := models.ParseAmount(inputAmount)
+			if err != nil {
+				return nil, err
+			}
+			out, err := models.ParseAmount(output.Amount)
+			if err != nil {
+				return nil, err
+			}
+			if out.Int().Cmp(in.Int()) <= 0 {
+				continue
+			}
+			inconsistencies = append(inconsistencies, &models.FeeInconsistency{
+				TransactionID: output.TransactionID,
+				AssetID:       output.AssetID,
+				InputAmount:   inputAmount,
+				OutputAmount:  output.Amount,
+			})
+		}
+
+		if len(txIDs) < batchSize {
+			break
+		}
+		lastID = txIDs[len(txIDs)-1]
+	}
+	return inconsistencies, nil
+}
+
+func flushCSV(w *csv.Writer) error {
+	w.Flush()
+	return w.Error()
+}
+
+// GetTransaction returns the dressed transaction with the given ID.
+// ErrTransactionNotFound is returned if the transaction isn't indexed.
+func (r *Reader) GetTransaction(ctx context.Context, id ids.ID) (*models.Transaction, error) {
+	txList, err := r.ListTransactions(ctx, &params.ListTransactionsParams{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	if len(txList.Transactions) == 0 {
+		return nil, ErrTransactionNotFound
+	}
+	return txList.Transactions[0], nil
+}
+
+// GetTransactions returns the transactions with the given IDs using a single
+// query. The result has the same length and order as txIDs, with nil entries for
+// transactions that were not found.
+func (r *Reader) GetTransactions(ctx context.Context, txIDs []ids.ID) ([]*models.Transaction, error) {
+	txs := make([]*models.Transaction, len(txIDs))
+	if len(txIDs) == 0 {
+		return txs, nil
+	}
+
+	p := &params.ListTransactionsParams{IDs: txIDs}
+	p.DisableCounting = true
+	txList, err := r.ListTransactions(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	txsByID := make(map[models.StringID]*models.Transaction, len(txList.Transactions))
+	for _, tx := range txList.Transactions {
+		txsByID[tx.ID] = tx
+	}
+	for i, id := range txIDs {
+		txs[i] = txsByID[models.ToStringID(id)]
+	}
+	return txs, nil
+}
+
+// GetRawTransaction returns the transaction's canonical serialization, which is
+// the raw bytes produced by the avm codec and accepted by the node's issueTx
+// API once CB58 encoded. The bytes aren't encoded in any way. Transactions
+// larger than avax.MaxSerializationLen are indexed without their bytes, in
+// which case an empty slice is returned.
+func (r *Reader) GetRawTransaction(ctx context.Context, id ids.ID) (_ []byte, err error) {
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
+
+	rawTxs := [][]byte{}
+	_, err = r.newSession("get_raw_transaction").
+		Select("canonical_serialization").
+		From("avm_transactions").
+		Where("id = ?", id.String()).
+		Where("chain_id IN ?", r.chainIDs(nil)).
+		LoadContext(ctx, &rawTxs)
+	if err != nil {
+		return nil, err
+	}
+	if len(rawTxs) == 0 {
+		return nil, ErrTransactionNotFound
+	}
+	if rawTxs[0] == nil {
+		return []byte{}, nil
+	}
+	return rawTxs[0], nil
+}
+
+// ListAddressTransactions lists the transactions that spent from or sent to
+// the address, newest first. Each transaction is listed once even when the
+// address appears in both its inputs and outputs.
+func (r *Reader) ListAddressTransactions(ctx context.Context, addr ids.ShortID, p *params.ListParams) (*models.TransactionList, error) {
+	txParams := &params.ListTransactionsParams{
+		Addresses: []ids.ShortID{addr},
+		Sort:      params.TransactionSortTimestampDesc,
+	}
+	if p != nil {
+		txParams.ListParams = *p
+	}
+	return r.ListTransactions(ctx, txParams)
+}
+
+// GetAsset returns the asset with the given ID or, if it isn't an ID, alias.
+// ErrAmbiguousAlias is returned if more than one asset has the alias, in which
+// case GetAssetsByAlias lists them, and ErrAssetNotFound if none match.
+func (r *Reader) GetAsset(ctx context.Context, idStrOrAlias string) (*models.Asset, error) {
+	id, err := ids.FromString(idStrOrAlias)
+	if err != nil {
+		assets, err := r.GetAssetsByAlias(ctx, idStrOrAlias)
+		if err != nil {
+			return nil, err
+		}
+		switch len(assets) {
+		case 0:
+			return nil, ErrAssetNotFound
+		case 1:
+			return assets[0], nil
 		default:
-			applySort(params.TransactionSortDefault)
+			return nil, ErrAmbiguousAlias
+		}
+	}
+
+	assetList, err := r.ListAssets(ctx, &params.ListAssetsParams{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	if len(assetList.Assets) == 0 {
+		return nil, ErrAssetNotFound
+	}
+	return assetList.Assets[0], nil
+}
+
+// GetAssetsByAlias returns every asset with the given alias, oldest first
+func (r *Reader) GetAssetsByAlias(ctx context.Context, alias string) ([]*models.Asset, error) {
+	p := &params.ListAssetsParams{Alias: alias}
+	p.DisableCounting = true
+	assetList, err := r.ListAssets(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return assetList.Assets, nil
+}
+
+// GetAssetsByIDs loads the given assets with a single query. Assets that
+// don't exist are missing from the returned map.
+func (r *Reader) GetAssetsByIDs(ctx context.Context, assetIDs []ids.ID) (map[models.StringID]*models.Asset, error) {
+	assets := make(map[models.StringID]*models.Asset, len(assetIDs))
+	if len(assetIDs) == 0 {
+		return assets, nil
+	}
+
+	p := &params.ListAssetsParams{IDs: assetIDs}
+	p.DisableCounting = true
+	assetList, err := r.ListAssets(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, asset := range assetList.Assets {
+		assets[asset.ID] = asset
+	}
+	return assets, nil
+}
+
+// GetAddress returns the address's info. ErrAddressNotFound is returned if
+// the address has never been used in an output.
+func (r *Reader) GetAddress(ctx context.Context, id ids.ShortID) (*models.AddressInfo, error) {
+	addressList, err := r.ListAddresses(ctx, &params.ListAddressesParams{Address: &id})
+	if err != nil {
+		return nil, err
+	}
+	if len(addressList.Addresses) == 0 {
+		return nil, ErrAddressNotFound
+	}
+	return addressList.Addresses[0], nil
+}
+
+// GetAddressBalances returns the unspent balance of each asset held by the
+// address, optionally restricted to the given assets. Unlike GetAddress it
+// only sums the address's UTXOs and doesn't load any other address info.
+func (r *Reader) GetAddressBalances(ctx context.Context, id ids.ShortID, assetIDs []ids.ID) (_ map[models.StringID]models.TokenAmount, err error) {
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
+
+	rows := []*struct {
+		AssetID models.StringID    `json:"assetID"`
+		Balance models.TokenAmount `json:"balance"`
+	}{}
+
+	builder := r.newSession("get_address_balances").
+		Select("avm_outputs.asset_id", "COALESCE(SUM(avm_outputs.amount), 0) AS balance").
+		From("avm_outputs").
+		Join("avm_output_addresses", "avm_output_addresses.output_id = avm_outputs.id").
+		Where("avm_output_addresses.address = ?", id.String()).
+		Where("avm_outputs.chain_id IN ?", r.chainIDs(nil)).
+		Where("avm_outputs.redeeming_transaction_id = ''").
+		GroupBy("avm_outputs.asset_id")
+
+	if len(assetIDs) > 0 {
+		assetIDStrs := make([]string, len(assetIDs))
+		for i, assetID := range assetIDs {
+			assetIDStrs[i] = assetID.String()
+		}
+		builder.Where("avm_outputs.asset_id IN ?", assetIDStrs)
+	}
+
+	if _, err := builder.LoadContext(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	balances := make(map[models.StringID]models.TokenAmount, len(rows))
+	for _, row := range rows {
+		balances[row.AssetID] = row.Balance
+	}
+	return balances, nil
+}
+
+// GetPortfolio returns the address's current balance of every asset it holds,
+// summing its UTXOs in a single query. Addresses with no holdings get an empty
+// map.
+func (r *Reader) GetPortfolio(ctx context.Context, addr ids.ShortID) (map[models.StringID]models.TokenAmount, error) {
+	return r.GetAddressBalances(ctx, addr, nil)
+}
+
+// ListAddressAssets returns the IDs of every asset the address has ever
+// received, spent or not, in a single query. It's much cheaper than loading
+// the address's balances. Unknown addresses get an empty list.
+func (r *Reader) ListAddressAssets(ctx context.Context, addr ids.ShortID) (_ []models.StringID, err error) {
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
+
+	assetIDs := []models.StringID{}
+	_, err = r.newSession("list_address_assets").
+		Select("avm_outputs.asset_id").
+		Distinct().
+		From("avm_outputs").
+		Join("avm_output_addresses", "avm_output_addresses.output_id = avm_outputs.id").
+		Where("avm_output_addresses.address = ?", addr.String()).
+		Where("avm_outputs.chain_id IN ?", r.chainIDs(nil)).
+		OrderAsc("avm_outputs.asset_id").
+		LoadContext(ctx, &assetIDs)
+	if err != nil {
+		return nil, err
+	}
+	return assetIDs, nil
+}
+
+// GetAddressActivityWindow returns the times the address received its first
+// and latest outputs, in a single query. Both are zero for unknown addresses.
+func (r *Reader) GetAddressActivityWindow(ctx context.Context, addr ids.ShortID) (first, last time.Time, err error) {
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
+
+	var window struct {
+		FirstSeen *time.Time
+		LastSeen  *time.Time
+	}
+	err = r.newSession("get_address_activity_window").
+		Select(
+			"MIN(avm_outputs.created_at) AS first_seen",
+			"MAX(avm_outputs.created_at) AS last_seen",
+		).
+		From("avm_outputs").
+		Join("avm_output_addresses", "avm_output_addresses.output_id = avm_outputs.id").
+		Where("avm_output_addresses.address = ?", addr.String()).
+		Where("avm_outputs.chain_id IN ?", r.chainIDs(nil)).
+		LoadOneContext(ctx, &window)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if window.FirstSeen != nil {
+		first = window.FirstSeen.UTC()
+	}
+	if window.LastSeen != nil {
+		last = window.LastSeen.UTC()
+	}
+	return first, last, nil
+}
+
+// GetOutput returns the output with the given ID. ErrOutputNotFound is
+// returned if the output isn't indexed.
+func (r *Reader) GetOutput(ctx context.Context, id ids.ID) (_ *models.Output, err error) {
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
+
+	outputList, err := r.listOutputs(ctx, &params.ListOutputsParams{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	if len(outputList.Outputs) == 0 {
+		return nil, ErrOutputNotFound
+	}
+	return outputList.Outputs[0], nil
+}
+
+// GetOutputs returns the outputs with the given IDs and their addresses, keyed
+// by output ID. The IDs are queried in batches of the reader's IN query batch
+// size. Outputs that aren't indexed are omitted.
+func (r *Reader) GetOutputs(ctx context.Context, outputIDs []ids.ID) (_ map[models.StringID]*models.Output, err error) {
+	outputsByID := make(map[models.StringID]*models.Output, len(outputIDs))
+	if len(outputIDs) == 0 {
+		return outputsByID, nil
+	}
+
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
+
+	idStrs := make([]models.StringID, len(outputIDs))
+	for i, id := range outputIDs {
+		idStrs[i] = models.ToStringID(id)
+	}
+
+	dbRunner := r.newSession("get_outputs")
+
+	outputs := []*models.Output{}
+	for _, batch := range batchIDs(idStrs, r.inQueryBatchSize) {
+		var batchOutputs []*models.Output
+		_, err = dbRunner.
+			Select(outputSelectColumns...).
+			From("avm_outputs").
+			Where("avm_outputs.id IN ?", batch).
+			Where("avm_outputs.chain_id IN ?", r.chainIDs(nil)).
+			LoadContext(ctx, &batchOutputs)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, batchOutputs...)
+	}
+
+	if err = r.loadOutputAddresses(ctx, dbRunner, outputs); err != nil {
+		return nil, err
+	}
+	setOutputLockStatuses(outputs, time.Now())
+
+	for _, output := range outputs {
+		outputsByID[output.ID] = output
+	}
+	return outputsByID, nil
+}
+
+// GetOutputsByTransaction returns the outputs created by the transaction in
+// index order with their addresses and lock statuses, like ListOutputs. It's
+// lighter than GetTransaction when only
+// the outputs are needed. A transaction without outputs, or one that isn't
+// indexed, has an empty list.
+func (r *Reader) GetOutputsByTransaction(ctx context.Context, txID ids.ID) (_ []*models.Output, err error) {
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
+
+	dbRunner := r.newSession("get_outputs_by_transaction")
+
+	outputs := []*models.Output{}
+	_, err = dbRunner.
+		Select(outputSelectColumns...).
+		From("avm_outputs").
+		Where("avm_outputs.transaction_id = ?", txID.String()).
+		Where("avm_outputs.chain_id IN ?", r.chainIDs(nil)).
+		OrderAsc("avm_outputs.output_index").
+		LoadContext(ctx, &outputs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.loadOutputAddresses(ctx, dbRunner, outputs); err != nil {
+		return nil, err
+	}
+	setOutputLockStatuses(outputs, time.Now())
+	return outputs, nil
+}
+
+// GetSpendingTransaction returns the transaction that spent the output, or nil
+// if the output is unspent. The transaction is returned whichever chain it's on,
+// such as an atomic import on another chain. ErrOutputNotFound is returned if
+// the output isn't indexed, and ErrSpentOnOtherChain, with the spending
+// transaction's ID, if the spending transaction isn't indexed on any chain.
+func (r *Reader) GetSpendingTransaction(ctx context.Context, outputID ids.ID) (*models.Transaction, error) {
+	output, err := r.GetOutput(ctx, outputID)
+	if err != nil {
+		return nil, err
+	}
+	if output.RedeemingTransactionID == "" {
+		return nil, nil
+	}
+
+	txID, err := ids.FromString(string(output.RedeemingTransactionID))
+	if err != nil {
+		return nil, err
+	}
+
+	chainID, err := r.getTransactionChainID(ctx, txID)
+	if err != nil {
+		return nil, err
+	}
+	if chainID == "" {
+		return nil, fmt.Errorf("%w: %s", ErrSpentOnOtherChain, txID)
+	}
+
+	txList, err := r.ListTransactions(ctx, &params.ListTransactionsParams{ID: &txID, ChainIDs: []string{chainID}})
+	if err != nil {
+		return nil, err
+	}
+	if len(txList.Transactions) == 0 {
+		return nil, ErrTransactionNotFound
+	}
+	return txList.Transactions[0], nil
+}
+
+// getTransactionChainID returns the chain the transaction is indexed on, on any
+// chain, or an empty string if it isn't indexed
+func (r *Reader) getTransactionChainID(ctx context.Context, txID ids.ID) (_ string, err error) {
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
+
+	chainIDs := []string{}
+	_, err = r.newSession("get_transaction_chain_id").
+		Select("chain_id").
+		From("avm_transactions").
+		Where("id = ?", txID.String()).
+		Limit(1).
+		LoadContext(ctx, &chainIDs)
+	if err != nil || len(chainIDs) == 0 {
+		return "", err
+	}
+	return chainIDs[0], nil
+}
+
+// GetAssetCreationTransaction returns the dressed transaction that created the
+// asset. ErrAssetNotFound is returned if the asset isn't indexed.
+func (r *Reader) GetAssetCreationTransaction(ctx context.Context, assetID ids.ID) (*models.Transaction, error) {
+	assetList, err := r.ListAssets(ctx, &params.ListAssetsParams{ID: &assetID, ListParams: params.ListParams{DisableCounting: true}})
+	if err != nil {
+		return nil, err
+	}
+	if len(assetList.Assets) == 0 {
+		return nil, ErrAssetNotFound
+	}
+
+	txID, err := ids.FromString(string(assetList.Assets[0].CreatedByTransactionID))
+	if err != nil {
+		return nil, err
+	}
+	return r.GetTransaction(ctx, txID)
+}
+
+// Health checks that the DB can be queried. It doesn't touch any tables so it's
+// cheap enough for liveness and readiness probes. ErrDBSlow is returned if the
+// DB doesn't respond within HealthCheckTimeout, and ErrDBUnavailable if the
+// query fails for any other reason. Both wrap the underlying error.
+func (r *Reader) Health(ctx context.Context) error {
+	ctx, cancelFn := context.WithTimeout(ctx, HealthCheckTimeout)
+	defer cancelFn()
+
+	var one int
+	err := r.newSession("health").
+		SelectBySql("SELECT 1").
+		LoadOneContext(ctx, &one)
+	switch {
+	case err == nil:
+		return nil
+	case ctx.Err() == context.DeadlineExceeded:
+		return fmt.Errorf("%w: %v", ErrDBSlow, err)
+	default:
+		return fmt.Errorf("%w: %v", ErrDBUnavailable, err)
+	}
+}
+
+// IndexStatus returns the time of the latest transaction indexed on each chain,
+// ordered by chain ID, and how far behind the current time it is. Chains
+// without any transactions aren't included.
+func (r *Reader) IndexStatus(ctx context.Context) (_ *models.IndexStatus, err error) {
+	ctx, cancelFn := r.queryContext(ctx)
+	defer endQuery(ctx, cancelFn, &err)
+
+	chains := []models.ChainIndexStatus{}
+	_, err = r.newSession("index_status").
+		Select("chain_id", "MAX(created_at) AS latest_transaction_time").
+		From("avm_transactions").
+		GroupBy("chain_id").
+		OrderAsc("chain_id").
+		LoadContext(ctx, &chains)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.IndexStatus{Time: time.Now().UTC(), Chains: chains}
+	for i := range status.Chains {
+		status.Chains[i].Lag = status.Time.Sub(status.Chains[i].LatestTransactionTime)
+	}
+	return status, nil
+}
+
+// queryContext returns a context bounded by the Reader's query timeout
+func (r *Reader) queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.queryTimeout < 1 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, r.queryTimeout)
+}
+
+// acquireExpensiveQuery waits for the Reader to have capacity for another
+// expensive query, and returns a func to release it once the query is done.
+// ErrTooBusy is returned if the context is done before there's capacity.
+func (r *Reader) acquireExpensiveQuery(ctx context.Context) (func(), error) {
+	if r.expensiveQueries == nil {
+		return func() {}, nil
+	}
+	select {
+	case r.expensiveQueries <- struct{}{}:
+		return func() { <-r.expensiveQueries }, nil
+	case <-ctx.Done():
+		return nil, ErrTooBusy
+	}
+}
+
+// endQuery releases the context of a query, replacing the query's error with
+// ErrQueryTimeout if it failed because the context's deadline passed
+func endQuery(ctx context.Context, cancelFn context.CancelFunc, err *error) {
+	if *err != nil && *err != ErrTooBusy && ctx.Err() == context.DeadlineExceeded {
+		*err = ErrQueryTimeout
+	}
+	cancelFn()
+}
+
+// InvalidateFirstTransactionTime clears the cached first transaction times
+func (r *Reader) InvalidateFirstTransactionTime() {
+	r.firstTxTimeLock.Lock()
+	defer r.firstTxTimeLock.Unlock()
+	r.firstTxTimeCache = map[string]firstTxTimeCacheEntry{}
+}
+
+// loadCount loads the count selected by query. Unless exact is set, or
+// approximate counts are disabled, a cached count of the query is returned
+// and flagged as approximate. A count older than the Reader's countCacheTTL is
+// refreshed in the background. Queries without a cached count are counted
+// exactly and cached.
+func (r *Reader) loadCount(ctx context.Context, query *dbr.SelectBuilder, exact bool) (count uint64, approximate bool, err error) {
+	if exact || r.countCacheTTL < 1 {
+		err = query.LoadOneContext(ctx, &count)
+		return count, false, err
+	}
+
+	// The interpolated SQL identifies the count, including its table, chains
+	// and filters
+	buf := dbr.NewBuffer()
+	if err = query.Build(query.Dialect, buf); err != nil {
+		return 0, false, err
+	}
+	key, err := dbr.InterpolateForDialect(buf.String(), buf.Value(), query.Dialect)
+	if err != nil {
+		return 0, false, err
+	}
+
+	r.countCacheLock.Lock()
+	entry, ok := r.countCache[key]
+	if ok {
+		count = entry.count
+		if !entry.refreshing && time.Since(entry.loadedAt) >= r.countCacheTTL {
+			entry.refreshing = true
+			go r.refreshCount(key, query)
 		}
 	}
-	applySort(p.Sort)
+	r.countCacheLock.Unlock()
+	if ok {
+		return count, true, nil
+	}
 
-	if _, err := builder.LoadContext(ctx, &txs); err != nil {
-		return nil, err
+	if err = query.LoadOneContext(ctx, &count); err != nil {
+		return 0, false, err
 	}
+	r.cacheCount(key, count)
+	return count, false, nil
+}
 
+// refreshCount reloads the cached count for key in the background. It counts as
+// an expensive query so refreshes can't crowd out requests. The cached count
+// is kept if the refresh fails, and a later request retries it.
+func (r *Reader) refreshCount(key string, query *dbr.SelectBuilder) {
 	var count uint64
-	if !p.DisableCounting {
-		count = uint64(p.Offset) + uint64(len(txs))
-		if len(txs) >= p.Limit {
-			p.ListParams = params.ListParams{}
-			var selector *dbr.SelectStmt
-			if p.NeedsDistinct() {
-				selector = p.Apply(dbRunner.
-					Select("COUNT(DISTINCT(avm_transactions.id))").
-					From("avm_transactions"))
-			} else {
-				selector = p.Apply(dbRunner.
-					Select("COUNT(avm_transactions.id)").
-					From("avm_transactions"))
-			}
-			err := selector.
-				LoadOneContext(ctx, &count)
-			if err != nil {
-				return nil, err
-			}
+	err := func() error {
+		ctx, cancelFn := r.queryContext(context.Background())
+		defer cancelFn()
+
+		release, err := r.acquireExpensiveQuery(ctx)
+		if err != nil {
+			return err
 		}
-	}
+		defer release()
 
-	// Add all the addition information we might want
-	if err := r.dressTransactions(ctx, dbRunner, txs); err != nil {
-		return nil, err
+		return query.LoadOneContext(ctx, &count)
+	}()
+
+	r.countCacheLock.Lock()
+	defer r.countCacheLock.Unlock()
+
+	// The entry may have been evicted while it was refreshing
+	entry, ok := r.countCache[key]
+	if !ok {
+		return
+	}
+	entry.refreshing = false
+	if err == nil {
+		entry.count = count
+		entry.loadedAt = time.Now()
 	}
+}
+
+// cacheCount caches count for key. If the cache is full then arbitrary entries
+// are removed first.
+func (r *Reader) cacheCount(key string, count uint64) {
+	r.countCacheLock.Lock()
+	defer r.countCacheLock.Unlock()
 
-	return &models.TransactionList{ListMetadata: models.ListMetadata{Count: count}, Transactions: txs}, nil
+	for k := range r.countCache {
+		if len(r.countCache) < MaxCountCacheEntries {
+			break
+		}
+		delete(r.countCache, k)
+	}
+	r.countCache[key] = &countCacheEntry{count: count, loadedAt: time.Now()}
 }
 
-func (r *Reader) ListAssets(ctx context.Context, p *params.ListAssetsParams) (*models.AssetList, error) {
-	dbRunner := r.conns.DB().NewSession("list_assets")
+// applyPeekLimit raises the limit set by p by one so that the query loads a
+// row past the end of the page if there is one, which tells whether there's
+// another page without counting. The extra row is removed with trimPage.
+func applyPeekLimit(b *dbr.SelectBuilder, p params.ListParams) *dbr.SelectBuilder {
+	if limit := p.EffectiveLimit(); limit > 0 {
+		b.Limit(uint64(limit) + 1)
+	}
+	return b
+}
 
-	assets := []*models.Asset{}
-	_, err := p.Apply(dbRunner.
-		Select("id", "chain_id", "name", "symbol", "alias", "denomination", "current_supply", "created_at").
-		From("avm_assets")).
-		LoadContext(ctx, &assets)
-	if err != nil {
-		return nil, err
+// trimPage returns the number of the n loaded rows that belong in the page and
+// whether there are more rows after it
+func trimPage(p params.ListParams, n int) (int, bool) {
+	if limit := p.EffectiveLimit(); limit > 0 && n > limit {
+		return limit, true
 	}
+	return n, false
+}
 
-	var count uint64
-	if !p.DisableCounting {
-		count = uint64(p.Offset) + uint64(len(assets))
-		if len(assets) >= p.Limit {
-			p.ListParams = params.ListParams{}
-			err := p.Apply(dbRunner.
-				Select("COUNT(avm_assets.id)").
-				From("avm_assets")).
-				LoadOneContext(ctx, &count)
-			if err != nil {
-				return nil, err
-			}
+// newSession creates a DB session named with the Reader's session name prefix
+func (r *Reader) newSession(name string) *dbr.Session {
+	name = r.sessionNamePrefix + name
+	sess := r.conns.DB().NewSession(name)
+	if r.queryHook != nil {
+		sess.EventReceiver = &queryHookReceiver{
+			EventReceiver: sess.EventReceiver,
+			sessionName:   name,
+			hook:          r.queryHook,
 		}
 	}
+	return sess
+}
 
-	return &models.AssetList{ListMetadata: models.ListMetadata{Count: count}, Assets: assets}, nil
+// queryHookReceiver passes the timing of each select query to a QueryHook
+// before forwarding it to the session's own instrumentation
+type queryHookReceiver struct {
+	dbr.EventReceiver
+	sessionName string
+	hook        QueryHook
 }
 
-func (r *Reader) ListAddresses(ctx context.Context, p *params.ListAddressesParams) (*models.AddressList, error) {
-	dbRunner := r.conns.DB().NewSession("list_addresses")
+func (q *queryHookReceiver) TimingKv(eventName string, nanoseconds int64, kvs map[string]string) {
+	if eventName == "dbr.select" {
+		q.hook(q.sessionName, kvs["sql"], nil, time.Duration(nanoseconds))
+	}
+	q.EventReceiver.TimingKv(eventName, nanoseconds, kvs)
+}
 
-	addresses := []*models.AddressInfo{}
-	_, err := p.Apply(dbRunner.
-		Select("DISTINCT(avm_output_addresses.address)", "addresses.public_key").
-		From("avm_output_addresses").
-		LeftJoin("addresses", "addresses.address = avm_output_addresses.address")).
-		LoadContext(ctx, &addresses)
-	if err != nil {
-		return nil, err
+// chainIDs returns the chains a query is scoped to, resolving any aliases in
+// override. Unknown aliases are kept so that they match nothing.
+func (r *Reader) chainIDs(override []string) []string {
+	if len(override) == 0 {
+		return []string{r.chainID}
 	}
 
-	var count uint64
-	if !p.DisableCounting {
-		count = uint64(p.Offset) + uint64(len(addresses))
-		if len(addresses) >= p.Limit {
-			p.ListParams = params.ListParams{}
-			err = p.Apply(dbRunner.
-				Select("COUNT(DISTINCT(avm_output_addresses.address))").
-				From("avm_output_addresses")).
-				LoadOneContext(ctx, &count)
-			if err != nil {
-				return nil, err
-			}
+	chainIDs := make([]string, len(override))
+	for i, aliasOrID := range override {
+		chainID, err := r.ResolveChainID(context.Background(), aliasOrID)
+		if err != nil {
+			chainID = aliasOrID
 		}
+		chainIDs[i] = chainID
 	}
+	return chainIDs
+}
 
-	// Add all the addition information we might want
-	if err = r.dressAddresses(ctx, dbRunner, addresses); err != nil {
-		return nil, err
+// ResolveChainID returns the chain ID of a chain alias, such as "X" or
+// "X-Chain", or aliasOrID itself if it's a valid ID. ErrUnknownChainAlias is
+// returned for anything else.
+func (r *Reader) ResolveChainID(_ context.Context, aliasOrID string) (string, error) {
+	if _, err := ids.FromString(aliasOrID); err == nil {
+		return aliasOrID, nil
 	}
 
-	return &models.AddressList{ListMetadata: models.ListMetadata{Count: count}, Addresses: addresses}, nil
+	alias := strings.ToLower(aliasOrID)
+	if chainID, ok := r.chainAliases[alias]; ok {
+		return chainID, nil
+	}
+	if chainID, ok := r.chainAliases[strings.TrimSuffix(alias, "-chain")]; ok {
+		return chainID, nil
+	}
+	return "", ErrUnknownChainAlias
 }
 
-func (r *Reader) ListOutputs(ctx context.Context, p *params.ListOutputsParams) (*models.OutputList, error) {
-	dbRunner := r.conns.DB().NewSession("list_transaction_outputs")
+func (r *Reader) getFirstTransactionTime(ctx context.Context, chainIDs []string) (time.Time, error) {
+	if r.firstTxTimeTTL < 1 {
+		return r.loadFirstTransactionTime(ctx, chainIDs)
+	}
 
-	outputs := []*models.Output{}
-	_, err := p.Apply(dbRunner.
-		Select(outputSelectColumns...).
-		From("avm_outputs")).
-		LoadContext(ctx, &outputs)
+	// The order of the chainIDs doesn't affect the result
+	sortedChainIDs := append([]string{}, chainIDs...)
+	sort.Strings(sortedChainIDs)
+	key := strings.Join(sortedChainIDs, "|")
+
+	r.firstTxTimeLock.Lock()
+	entry, ok := r.firstTxTimeCache[key]
+	r.firstTxTimeLock.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.ts, nil
+	}
+
+	ts, err := r.loadFirstTransactionTime(ctx, chainIDs)
 	if err != nil {
-		return nil, err
+		return time.Time{}, err
 	}
 
-	if len(outputs) < 1 {
-		return &models.OutputList{Outputs: outputs}, nil
+	// Don't cache the result until the chain has transactions
+	if ts.Unix() == 0 {
+		return ts, nil
+	}
+
+	r.firstTxTimeLock.Lock()
+	r.firstTxTimeCache[key] = firstTxTimeCacheEntry{ts: ts, expiresAt: time.Now().Add(r.firstTxTimeTTL)}
+	r.firstTxTimeLock.Unlock()
+
+	return ts, nil
+}
+
+func (r *Reader) loadFirstTransactionTime(ctx context.Context, chainIDs []string) (time.Time, error) {
+	var ts int64
+	builder := r.newSession("get_first_transaction_time").
+		Select("COALESCE(UNIX_TIMESTAMP(MIN(created_at)), 0)").
+		From("avm_transactions")
+
+	if len(chainIDs) > 0 {
+		builder.Where("avm_transactions.chain_id IN ?", chainIDs)
+	}
+
+	err := builder.LoadOneContext(ctx, &ts)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(ts, 0).UTC(), nil
+}
+
+// loadOutputAddresses adds the addresses of each output to it
+func (r *Reader) loadOutputAddresses(ctx context.Context, dbRunner dbr.SessionRunner, outputs []*models.Output) error {
+	if len(outputs) == 0 {
+		return nil
 	}
 
 	outputIDs := make([]models.StringID, len(outputs))
@@ -401,18 +2891,22 @@ func (r *Reader) ListOutputs(ctx context.Context, p *params.ListOutputsParams) (
 	}
 
 	addresses := []*models.OutputAddress{}
-	_, err = dbRunner.
-		Select(
-			"avm_output_addresses.output_id",
-			"avm_output_addresses.address",
-			"avm_output_addresses.redeeming_signature AS signature",
-			"avm_output_addresses.created_at",
-		).
-		From("avm_output_addresses").
-		Where("avm_output_addresses.output_id IN ?", outputIDs).
-		LoadContext(ctx, &addresses)
-	if err != nil {
-		return nil, err
+	for _, batch := range batchIDs(outputIDs, r.inQueryBatchSize) {
+		var batchAddresses []*models.OutputAddress
+		_, err := dbRunner.
+			Select(
+				"avm_output_addresses.output_id",
+				"avm_output_addresses.address",
+				"avm_output_addresses.redeeming_signature AS signature",
+				"avm_output_addresses.created_at",
+			).
+			From("avm_output_addresses").
+			Where("avm_output_addresses.output_id IN ?", batch).
+			LoadContext(ctx, &batchAddresses)
+		if err != nil {
+			return err
+		}
+		addresses = append(addresses, batchAddresses...)
 	}
 
 	for _, address := range addresses {
@@ -422,96 +2916,156 @@ func (r *Reader) ListOutputs(ctx context.Context, p *params.ListOutputsParams) (
 		}
 		output.Addresses = append(output.Addresses, address.Address)
 	}
+	return nil
+}
 
-	var count uint64
-	if !p.DisableCounting {
-		count = uint64(p.Offset) + uint64(len(outputs))
-		if len(outputs) >= p.Limit {
-			p.ListParams = params.ListParams{}
-			err = p.Apply(dbRunner.
-				Select("COUNT(avm_outputs.id)").
-				From("avm_outputs")).
-				LoadOneContext(ctx, &count)
-			if err != nil {
-				return nil, err
-			}
-		}
+// loadDenominations returns the denominations of the known assets of the
+// outputs
+func (r *Reader) loadDenominations(ctx context.Context, dbRunner dbr.SessionRunner, outputs []*models.Output) (map[models.StringID]uint8, error) {
+	if len(outputs) == 0 {
+		return map[models.StringID]uint8{}, nil
 	}
 
-	return &models.OutputList{ListMetadata: models.ListMetadata{Count: count}, Outputs: outputs}, err
-}
+	assetIDSet := make(map[models.StringID]struct{}, len(outputs))
+	assetIDs := make([]models.StringID, 0, len(outputs))
+	for _, output := range outputs {
+		if _, ok := assetIDSet[output.AssetID]; !ok {
+			assetIDSet[output.AssetID] = struct{}{}
+			assetIDs = append(assetIDs, output.AssetID)
+		}
+	}
 
-func (r *Reader) GetTransaction(ctx context.Context, id ids.ID) (*models.Transaction, error) {
-	txList, err := r.ListTransactions(ctx, &params.ListTransactionsParams{ID: &id})
+	var assets []*struct {
+		ID           models.StringID
+		Denomination uint8
+	}
+	_, err := dbRunner.
+		Select("id", "denomination").
+		From("avm_assets").
+		Where("id IN ?", assetIDs).
+		LoadContext(ctx, &assets)
 	if err != nil {
 		return nil, err
 	}
-	if len(txList.Transactions) > 0 {
-		return txList.Transactions[0], nil
+
+	denominations := make(map[models.StringID]uint8, len(assets))
+	for _, asset := range assets {
+		denominations[asset.ID] = asset.Denomination
 	}
-	return nil, nil
+	return denominations, nil
 }
 
-func (r *Reader) GetAsset(ctx context.Context, idStrOrAlias string) (*models.Asset, error) {
-	params := &params.ListAssetsParams{}
-
-	id, err := ids.FromString(idStrOrAlias)
-	if err == nil {
-		params.ID = &id
-	} else {
-		params.Alias = idStrOrAlias
+// formatOutputAmounts sets the FormattedAmount of each output whose asset's
+// denomination is known
+func formatOutputAmounts(outputs []*models.Output, denominations map[models.StringID]uint8) error {
+	var err error
+	for _, output := range outputs {
+		denomination, ok := denominations[output.AssetID]
+		if !ok {
+			continue
+		}
+		if output.FormattedAmount, err = output.Amount.Format(denomination); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	assetList, err := r.ListAssets(ctx, params)
-	if err != nil {
-		return nil, err
-	}
-	if len(assetList.Assets) > 0 {
-		return assetList.Assets[0], nil
+// decodeOutputPayloads sets the DecodedPayload of each output whose payload is
+// in a known format
+func decodeOutputPayloads(outputs []*models.Output) {
+	for _, output := range outputs {
+		output.DecodedPayload = models.DecodePayload(output.Payload)
 	}
-	return nil, err
 }
 
-func (r *Reader) GetAddress(ctx context.Context, id ids.ShortID) (*models.AddressInfo, error) {
-	addressList, err := r.ListAddresses(ctx, &params.ListAddressesParams{Address: &id})
-	if err != nil {
-		return nil, err
-	}
-	if len(addressList.Addresses) > 0 {
-		return addressList.Addresses[0], nil
+// setOutputLockStatuses sets whether each output is still locked at now, and
+// the time it unlocks at if it has a locktime
+func setOutputLockStatuses(outputs []*models.Output, now time.Time) {
+	for _, output := range outputs {
+		if output.Locktime == 0 {
+			continue
+		}
+
+		// Outputs can be spent once the time reaches their locktime
+		unlocksAt := time.Unix(int64(output.Locktime), 0).UTC()
+		output.Locked = output.Locktime > uint64(now.Unix())
+		output.UnlocksAt = &unlocksAt
 	}
-	return nil, err
 }
 
-func (r *Reader) GetOutput(ctx context.Context, id ids.ID) (*models.Output, error) {
-	outputList, err := r.ListOutputs(ctx, &params.ListOutputsParams{ID: &id})
-	if err != nil {
-		return nil, err
-	}
-	if len(outputList.Outputs) > 0 {
-		return outputList.Outputs[0], nil
+// newAssetTokenCounts converts the totals into AssetTokenCounts, including the
+// denominations of the assets that are known
+func newAssetTokenCounts(totals map[models.StringID]*models.Amount, denominations map[models.StringID]uint8) models.AssetTokenCounts {
+	counts := make(models.AssetTokenCounts, len(totals))
+	for assetID, total := range totals {
+		count := models.AssetTokenCount{Amount: total.TokenAmount()}
+		if denomination, ok := denominations[assetID]; ok {
+			count.Denomination = &denomination
+		}
+		counts[assetID] = count
 	}
-	return nil, err
+	return counts
 }
 
-func (r *Reader) getFirstTransactionTime(ctx context.Context, chainIDs []string) (time.Time, error) {
-	var ts int64
-	builder := r.conns.DB().NewSession("get_first_transaction_time").
-		Select("COALESCE(UNIX_TIMESTAMP(MIN(created_at)), 0)").
-		From("avm_transactions")
+// transactionOutputRecord is an output joined with one of its addresses
+type transactionOutputRecord struct {
+	models.Output
+	models.OutputAddress
+}
 
-	if len(chainIDs) > 0 {
-		builder.Where("avm_transactions.chain_id = ?", chainIDs)
+// loadTransactionOutputs loads output data for all inputs and outputs of the
+// given transactions into a single list. We can't treat them separately
+// because some may be both inputs and outputs for different transactions. The
+// outputs and inputs are queried concurrently but always returned in that
+// order. Each is queried in batches of at most batchSize transactions.
+func loadTransactionOutputs(ctx context.Context, dbRunner dbr.SessionRunner, txIDs []models.StringID, batchSize int) ([]*transactionOutputRecord, error) {
+	var outputs, inputs []*transactionOutputRecord
+	loadBatches := func(ctx context.Context, column string, records *[]*transactionOutputRecord) error {
+		for _, batch := range batchIDs(txIDs, batchSize) {
+			var batchRecords []*transactionOutputRecord
+			_, err := selectOutputs(dbRunner).
+				Where(column+" IN ?", batch).
+				LoadContext(ctx, &batchRecords)
+			if err != nil {
+				return err
+			}
+			*records = append(*records, batchRecords...)
+		}
+		return nil
 	}
 
-	err := builder.LoadOneContext(ctx, &ts)
-	if err != nil {
-		return time.Time{}, err
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return loadBatches(gctx, "avm_outputs.transaction_id", &outputs)
+	})
+	g.Go(func() error {
+		return loadBatches(gctx, "avm_outputs.redeeming_transaction_id", &inputs)
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
 	}
-	return time.Unix(ts, 0).UTC(), nil
+	return append(outputs, inputs...), nil
+}
+
+// batchIDs splits idList into consecutive batches of at most size IDs. A size < 1
+// returns them all in a single batch.
+func batchIDs(idList []models.StringID, size int) [][]models.StringID {
+	if size < 1 || len(idList) <= size {
+		return [][]models.StringID{idList}
+	}
+	batches := make([][]models.StringID, 0, (len(idList)+size-1)/size)
+	for len(idList) > size {
+		batches = append(batches, idList[:size])
+		idList = idList[size:]
+	}
+	return append(batches, idList)
 }
 
-func (r *Reader) dressTransactions(ctx context.Context, dbRunner dbr.SessionRunner, txs []*models.Transaction) error {
+// dressTransactions adds the inputs, outputs, and their totals to each
+// transaction. When light is set only the counts and totals are added. When
+// includeAssets is set the assets in the totals are added too.
+func (r *Reader) dressTransactions(ctx context.Context, dbRunner dbr.SessionRunner, txs []*models.Transaction, light bool, includeAssets bool) error {
 	if len(txs) == 0 {
 		return nil
 	}
@@ -522,31 +3076,28 @@ func (r *Reader) dressTransactions(ctx context.Context, dbRunner dbr.SessionRunn
 		txIDs[i] = tx.ID
 	}
 
-	// Load output data for all inputs and outputs into a single list
-	// We can't treat them separately because some my be both inputs and outputs
-	// for different transactions
-	type compositeRecord struct {
-		models.Output
-		models.OutputAddress
-	}
-
-	var outputs []*compositeRecord
-	_, err := selectOutputs(dbRunner).
-		Where("avm_outputs.transaction_id IN ?", txIDs).
-		LoadContext(ctx, &outputs)
+	outputs, err := loadTransactionOutputs(ctx, dbRunner, txIDs, r.inQueryBatchSize)
 	if err != nil {
 		return err
 	}
 
-	var inputs []*compositeRecord
-	_, err = selectOutputs(dbRunner).
-		Where("avm_outputs.redeeming_transaction_id IN ?", txIDs).
-		LoadContext(ctx, &inputs)
-	if err != nil {
-		return err
+	// An output created and spent by transactions in the same batch is loaded
+	// by both queries, so drop the repeated rows
+	type compositeKey struct {
+		outputID models.StringID
+		address  models.Address
 	}
-
-	outputs = append(outputs, inputs...)
+	loaded := make(map[compositeKey]struct{}, len(outputs))
+	uniqueOutputs := outputs[:0]
+	for _, output := range outputs {
+		key := compositeKey{output.Output.ID, output.OutputAddress.Address}
+		if _, ok := loaded[key]; ok {
+			continue
+		}
+		loaded[key] = struct{}{}
+		uniqueOutputs = append(uniqueOutputs, output)
+	}
+	outputs = uniqueOutputs
 
 	// Create a map of addresses for each output and maps of transaction ids to
 	// inputs, outputs, and the total amounts of the inputs and outputs
@@ -554,49 +3105,72 @@ func (r *Reader) dressTransactions(ctx context.Context, dbRunner dbr.SessionRunn
 		outputAddrs     = make(map[models.StringID]map[models.Address]struct{}, len(txs)*2)
 		inputsMap       = make(map[models.StringID]map[models.StringID]*models.Input, len(txs))
 		outputsMap      = make(map[models.StringID]map[models.StringID]*models.Output, len(txs))
-		inputTotalsMap  = make(map[models.StringID]map[models.StringID]*big.Int, len(txs))
-		outputTotalsMap = make(map[models.StringID]map[models.StringID]*big.Int, len(txs))
+		inputTotalsMap  = make(map[models.StringID]map[models.StringID]*models.Amount, len(txs))
+		outputTotalsMap = make(map[models.StringID]map[models.StringID]*models.Amount, len(txs))
 	)
 
-	// Create a helper to safely add big integers
-	addToBigIntMap := func(m map[models.StringID]*big.Int, assetID models.StringID, amt *big.Int) {
-		prevAmt := m[assetID]
-		if prevAmt == nil {
-			prevAmt = big.NewInt(0)
+	// Create a helper to add to the totals. Each total is only allocated once
+	// and then added to in place.
+	addToAmountMap := func(m map[models.StringID]*models.Amount, assetID models.StringID, amt *models.Amount) {
+		total, ok := m[assetID]
+		if !ok {
+			total = &models.Amount{}
+			m[assetID] = total
 		}
-		m[assetID] = prevAmt.Add(amt, prevAmt)
+		total.Add(amt)
 	}
 
-	// Collect outpoints into the maps
+	// Collect outpoints into the maps. Each amount is parsed into the same
+	// Amount since it's only needed until it's added to the totals.
+	amt := &models.Amount{}
 	for _, output := range outputs {
 		out := &output.Output
 
-		bigAmt := new(big.Int)
-		if _, ok := bigAmt.SetString(string(out.Amount), 10); !ok {
-			return errors.New("invalid amount")
+		if err := amt.SetTokenAmount(out.Amount); err != nil {
+			return err
 		}
 
 		if _, ok := inputsMap[out.RedeemingTransactionID]; !ok {
 			inputsMap[out.RedeemingTransactionID] = map[models.StringID]*models.Input{}
 		}
 		if _, ok := inputTotalsMap[out.RedeemingTransactionID]; !ok {
-			inputTotalsMap[out.RedeemingTransactionID] = map[models.StringID]*big.Int{}
+			inputTotalsMap[out.RedeemingTransactionID] = map[models.StringID]*models.Amount{}
 		}
 		if _, ok := outputsMap[out.TransactionID]; !ok {
 			outputsMap[out.TransactionID] = map[models.StringID]*models.Output{}
 		}
 		if _, ok := outputTotalsMap[out.TransactionID]; !ok {
-			outputTotalsMap[out.TransactionID] = map[models.StringID]*big.Int{}
+			outputTotalsMap[out.TransactionID] = map[models.StringID]*models.Amount{}
 		}
 		if _, ok := outputAddrs[out.ID]; !ok {
 			outputAddrs[out.ID] = map[models.Address]struct{}{}
 		}
 
+		// Outputs with several addresses have a row for each address, so each
+		// output is only added to the totals the first time it's seen
+		if _, ok := outputsMap[out.TransactionID][out.ID]; !ok {
+			addToAmountMap(outputTotalsMap[out.TransactionID], out.AssetID, amt)
+		}
+		if _, ok := inputsMap[out.RedeemingTransactionID][out.ID]; !ok {
+			addToAmountMap(inputTotalsMap[out.RedeemingTransactionID], out.AssetID, amt)
+		}
+
 		outputAddrs[out.ID][output.OutputAddress.Address] = struct{}{}
 		outputsMap[out.TransactionID][out.ID] = out
 		inputsMap[out.RedeemingTransactionID][out.ID] = &models.Input{Output: out}
-		addToBigIntMap(outputTotalsMap[out.TransactionID], out.AssetID, bigAmt)
-		addToBigIntMap(inputTotalsMap[out.RedeemingTransactionID], out.AssetID, bigAmt)
+	}
+
+	// Format the amounts of outputs for assets we know the denomination of
+	outs := make([]*models.Output, len(outputs))
+	for i, output := range outputs {
+		outs[i] = &output.Output
+	}
+	denominations, err := r.loadDenominations(ctx, dbRunner, outs)
+	if err != nil {
+		return err
+	}
+	if err = formatOutputAmounts(outs, denominations); err != nil {
+		return err
 	}
 
 	// Collect the addresses into a list on each outpoint
@@ -612,13 +3186,15 @@ func (r *Reader) dressTransactions(ctx context.Context, dbRunner dbr.SessionRunn
 			continue
 		}
 
-		// Get the Input and add the credentials for this Address
+		// Get the Input and add the credentials for this Address, even if its
+		// public key is unknown
 		for _, input = range inputsMap[out.RedeemingTransactionID] {
 			if input.Output.ID.Equals(out.OutputID) {
 				input.Creds = append(input.Creds, models.InputCredentials{
-					Address:   out.Address,
-					PublicKey: out.PublicKey,
-					Signature: out.Signature,
+					Address:      out.Address,
+					PublicKey:    out.PublicKey,
+					HasPublicKey: len(out.PublicKey) > 0,
+					Signature:    out.Signature,
 				})
 				break
 			}
@@ -627,26 +3203,96 @@ func (r *Reader) dressTransactions(ctx context.Context, dbRunner dbr.SessionRunn
 
 	// Add the data we've built up for each transaction
 	for _, tx := range txs {
-		if inputs, ok := inputsMap[tx.ID]; ok {
+		tx.DecodedMemo = models.DecodeMemo(tx.Memo)
+		tx.InputCount = len(inputsMap[tx.ID])
+		tx.OutputCount = len(outputsMap[tx.ID])
+
+		if inputs, ok := inputsMap[tx.ID]; ok && !light {
 			for _, input := range inputs {
 				tx.Inputs = append(tx.Inputs, input)
 			}
 		}
 
-		if outputs, ok := outputsMap[tx.ID]; ok {
+		if outputs, ok := outputsMap[tx.ID]; ok && !light {
 			for _, output := range outputs {
 				tx.Outputs = append(tx.Outputs, output)
 			}
 		}
 
-		tx.InputTotals = make(models.AssetTokenCounts, len(inputTotalsMap[tx.ID]))
+		// The maps are unordered, so order inputs by the output they spend and
+		// outputs by their index
+		sort.Slice(tx.Inputs, func(i, j int) bool {
+			a, b := tx.Inputs[i].Output, tx.Inputs[j].Output
+			if a.TransactionID != b.TransactionID {
+				return a.TransactionID < b.TransactionID
+			}
+			return a.OutputIndex < b.OutputIndex
+		})
+		sort.Slice(tx.Outputs, func(i, j int) bool {
+			return tx.Outputs[i].OutputIndex < tx.Outputs[j].OutputIndex
+		})
+
+		tx.InputTotals = newAssetTokenCounts(inputTotalsMap[tx.ID], denominations)
+		tx.OutputTotals = newAssetTokenCounts(outputTotalsMap[tx.ID], denominations)
+
+		// The fee is whatever was consumed but not output again. Assets that
+		// were minted have more outputs than inputs and are left out.
+		fees := make(map[models.StringID]*models.Amount, len(inputTotalsMap[tx.ID]))
 		for k, v := range inputTotalsMap[tx.ID] {
-			tx.InputTotals[k] = models.TokenAmount(v.String())
+			fee := &models.Amount{}
+			fee.Int().Set(v.Int())
+			if outputTotal, ok := outputTotalsMap[tx.ID][k]; ok {
+				fee.Int().Sub(fee.Int(), outputTotal.Int())
+			}
+			if fee.Int().Sign() > 0 {
+				fees[k] = fee
+			}
+		}
+		tx.Fees = newAssetTokenCounts(fees, denominations)
+	}
+
+	if includeAssets {
+		return r.addTransactionAssets(ctx, txs)
+	}
+	return nil
+}
+
+// addTransactionAssets adds the symbol and denomination of each asset in the
+// transactions' totals to them. Every asset of the page is loaded by a single
+// query. Assets that aren't indexed are left out.
+func (r *Reader) addTransactionAssets(ctx context.Context, txs []*models.Transaction) error {
+	assetIDSet := map[models.StringID]struct{}{}
+	assetIDs := []ids.ID{}
+	for _, tx := range txs {
+		for _, totals := range []models.AssetTokenCounts{tx.InputTotals, tx.OutputTotals} {
+			for assetID := range totals {
+				if _, ok := assetIDSet[assetID]; ok {
+					continue
+				}
+				assetIDSet[assetID] = struct{}{}
+
+				id, err := ids.FromString(string(assetID))
+				if err != nil {
+					return err
+				}
+				assetIDs = append(assetIDs, id)
+			}
 		}
+	}
+
+	assets, err := r.GetAssetsByIDs(ctx, assetIDs)
+	if err != nil {
+		return err
+	}
 
-		tx.OutputTotals = make(models.AssetTokenCounts, len(outputTotalsMap[tx.ID]))
-		for k, v := range outputTotalsMap[tx.ID] {
-			tx.OutputTotals[k] = models.TokenAmount(v.String())
+	for _, tx := range txs {
+		tx.Assets = map[models.StringID]models.AssetInfoLite{}
+		for _, totals := range []models.AssetTokenCounts{tx.InputTotals, tx.OutputTotals} {
+			for assetID := range totals {
+				if asset, ok := assets[assetID]; ok {
+					tx.Assets[assetID] = models.AssetInfoLite{Symbol: asset.Symbol, Denomination: asset.Denomination}
+				}
+			}
 		}
 	}
 	return nil
@@ -657,48 +3303,63 @@ func (r *Reader) dressAddresses(ctx context.Context, dbRunner dbr.SessionRunner,
 		return nil
 	}
 
-	// Create a list of ids for querying, and a map for accumulating results later
-	addrIDs := make([]models.Address, len(addrs))
-	addrsByID := make(map[models.Address]*models.AddressInfo, len(addrs))
-	for i, addr := range addrs {
-		addrIDs[i] = addr.Address
-		addrsByID[addr.Address] = addr
+	// Create a list of unique ids for querying, and a map for accumulating
+	// results later
+	addrIDs := make([]models.Address, 0, len(addrs))
+	addrsByID := make(map[models.Address][]*models.AddressInfo, len(addrs))
+	for _, addr := range addrs {
+		if _, ok := addrsByID[addr.Address]; !ok {
+			addrIDs = append(addrIDs, addr.Address)
+		}
+		addrsByID[addr.Address] = append(addrsByID[addr.Address], addr)
 
 		addr.Assets = make(map[models.StringID]models.AssetInfo, 1)
 	}
 
-	// Load each Transaction Output for the tx, both inputs and outputs
-	rows := []*struct {
-		Address models.Address `json:"address"`
-		models.AssetInfo
-	}{}
-
-	_, err := dbRunner.
-		Select(
-			"avm_output_addresses.address",
-			"avm_outputs.asset_id",
-			"COUNT(DISTINCT(avm_outputs.transaction_id)) AS transaction_count",
-			"COALESCE(SUM(avm_outputs.amount), 0) AS total_received",
-			"COALESCE(SUM(CASE WHEN avm_outputs.redeeming_transaction_id != '' THEN avm_outputs.amount ELSE 0 END), 0) AS total_sent",
-			"COALESCE(SUM(CASE WHEN avm_outputs.redeeming_transaction_id = '' THEN avm_outputs.amount ELSE 0 END), 0) AS balance",
-			"COALESCE(SUM(CASE WHEN avm_outputs.redeeming_transaction_id = '' THEN 1 ELSE 0 END), 0) AS utxo_count",
-		).
-		From("avm_outputs").
-		LeftJoin("avm_output_addresses", "avm_output_addresses.output_id = avm_outputs.id").
-		Where("avm_output_addresses.address IN ?", addrIDs).
-		GroupBy("avm_output_addresses.address", "avm_outputs.asset_id").
-		LoadContext(ctx, &rows)
-	if err != nil {
-		return err
+	// Group the outputs of a batch of addresses at a time. Each address is in
+	// a single batch, and so each of its assets in a single row, so the
+	// results don't depend on where the batches are split.
+	batchSize := r.inQueryBatchSize
+	if batchSize < 1 {
+		batchSize = len(addrIDs)
 	}
+	for len(addrIDs) > 0 {
+		batch := addrIDs
+		if len(batch) > batchSize {
+			batch = batch[:batchSize]
+		}
+		addrIDs = addrIDs[len(batch):]
+
+		rows := []*struct {
+			Address models.Address `json:"address"`
+			models.AssetInfo
+		}{}
+
+		_, err := dbRunner.
+			Select(
+				"avm_output_addresses.address",
+				"avm_outputs.asset_id",
+				"COUNT(DISTINCT(avm_outputs.transaction_id)) AS transaction_count",
+				"COALESCE(SUM(avm_outputs.amount), 0) AS total_received",
+				"COALESCE(SUM(CASE WHEN avm_outputs.redeeming_transaction_id != '' THEN avm_outputs.amount ELSE 0 END), 0) AS total_sent",
+				"COALESCE(SUM(CASE WHEN avm_outputs.redeeming_transaction_id = '' THEN avm_outputs.amount ELSE 0 END), 0) AS balance",
+				"COALESCE(SUM(CASE WHEN avm_outputs.redeeming_transaction_id = '' THEN 1 ELSE 0 END), 0) AS utxo_count",
+			).
+			From("avm_outputs").
+			LeftJoin("avm_output_addresses", "avm_output_addresses.output_id = avm_outputs.id").
+			Where("avm_output_addresses.address IN ?", batch).
+			GroupBy("avm_output_addresses.address", "avm_outputs.asset_id").
+			LoadContext(ctx, &rows)
+		if err != nil {
+			return err
+		}
 
-	// Accumulate rows into addresses
-	for _, row := range rows {
-		addr, ok := addrsByID[row.Address]
-		if !ok {
-			continue
+		// Accumulate rows into addresses
+		for _, row := range rows {
+			for _, addr := range addrsByID[row.Address] {
+				addr.Assets[row.AssetID] = row.AssetInfo
+			}
 		}
-		addr.Assets[row.AssetID] = row.AssetInfo
 	}
 
 	return nil
@@ -710,31 +3371,92 @@ func (r *Reader) searchByID(ctx context.Context, id ids.ID) (*models.SearchResul
 	if assets, err := r.ListAssets(ctx, &params.ListAssetsParams{ListParams: listParams, ID: &id}); err != nil {
 		return nil, err
 	} else if len(assets.Assets) > 0 {
-		return collateSearchResults(assets, nil, nil, nil)
+		return collateSearchResults(listParams, assets, nil, nil, nil)
 	}
 
 	if txs, err := r.ListTransactions(ctx, &params.ListTransactionsParams{ListParams: listParams, ID: &id}); err != nil {
 		return nil, err
 	} else if len(txs.Transactions) > 0 {
-		return collateSearchResults(nil, nil, txs, nil)
+		return collateSearchResults(listParams, nil, nil, txs, nil)
 	}
 
 	return &models.SearchResults{}, nil
 }
 
+// searchByTransactionIDPrefix returns the page of transactions whose IDs start
+// with the query and their total count, or nil if the first page is empty so
+// that the query can be searched for normally. Only the ID index is used,
+// unlike a normal search which also scans memos.
+func (r *Reader) searchByTransactionIDPrefix(ctx context.Context, p *params.SearchParams) (*models.SearchResults, error) {
+	txs, err := r.ListTransactions(ctx, &params.ListTransactionsParams{ListParams: p.ListParams, IDPrefix: p.Query})
+	if err != nil {
+		return nil, err
+	}
+	if len(txs.Transactions) == 0 && p.Offset == 0 {
+		return nil, nil
+	}
+
+	// Counting reports the offset for pages past the end, so it must be
+	// recounted from the start
+	count := txs.Count
+	if len(txs.Transactions) == 0 && !p.DisableCounting {
+		counted, err := r.ListTransactions(ctx, &params.ListTransactionsParams{ListParams: params.ListParams{CountOnly: true}, IDPrefix: p.Query})
+		if err != nil {
+			return nil, err
+		}
+		count = counted.Count
+	}
+	if p.DisableCounting {
+		count = 0
+	}
+
+	results := &models.SearchResults{Count: count, Results: make([]models.SearchResult, len(txs.Transactions))}
+	for i, tx := range txs.Transactions {
+		results.Results[i] = models.SearchResult{SearchResultType: models.ResultTypeTransaction, Data: tx}
+	}
+	return results, nil
+}
+
+// searchByAssetAlias returns the assets with the query as their alias, such as
+// "AVAX", or nil if there aren't any so that the query can be searched for
+// normally
+func (r *Reader) searchByAssetAlias(ctx context.Context, alias string) (*models.SearchResults, error) {
+	listParams := params.ListParams{DisableCounting: true}
+
+	assets, err := r.ListAssets(ctx, &params.ListAssetsParams{ListParams: listParams, Alias: alias})
+	if err != nil || len(assets.Assets) == 0 {
+		return nil, err
+	}
+	return collateSearchResults(listParams, assets, nil, nil, nil)
+}
+
+// isIDPrefix returns true if s could be the start of an ID, i.e. it's long
+// enough and only has base58 characters
+func isIDPrefix(s string) bool {
+	if len(s) < MinTransactionIDPrefixLength {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune(base58Alphabet, c) {
+			return false
+		}
+	}
+	return true
+}
+
 func (r *Reader) searchByShortID(ctx context.Context, id ids.ShortID) (*models.SearchResults, error) {
 	listParams := params.ListParams{DisableCounting: true}
 
 	if addrs, err := r.ListAddresses(ctx, &params.ListAddressesParams{ListParams: listParams, Address: &id}); err != nil {
 		return nil, err
 	} else if len(addrs.Addresses) > 0 {
-		return collateSearchResults(nil, addrs, nil, nil)
+		return collateSearchResults(listParams, nil, addrs, nil, nil)
 	}
 
 	return &models.SearchResults{}, nil
 }
 
-func collateSearchResults(assetResults *models.AssetList, addressResults *models.AddressList, transactionResults *models.TransactionList, _ *models.OutputList) (*models.SearchResults, error) {
+func collateSearchResults(listParams params.ListParams, assetResults *models.AssetList, addressResults *models.AddressList, transactionResults *models.TransactionList, _ *models.OutputList) (*models.SearchResults, error) {
 	var (
 		assets       []*models.Asset
 		addresses    []*models.AddressInfo
@@ -756,8 +3478,8 @@ func collateSearchResults(assetResults *models.AssetList, addressResults *models
 
 	// Build overall SearchResults object from our pieces
 	returnedResultCount := len(assets) + len(addresses) + len(transactions) + len(outputs)
-	if returnedResultCount > params.PaginationMaxLimit {
-		returnedResultCount = params.PaginationMaxLimit
+	if maxLimit := listParams.EffectiveMaxLimit(); returnedResultCount > maxLimit {
+		returnedResultCount = maxLimit
 	}
 
 	collatedResults := &models.SearchResults{
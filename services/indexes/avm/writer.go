@@ -131,7 +131,8 @@ func (w *Writer) Consume(ctx context.Context, i services.Consumable) error {
 	defer dbTx.RollbackUnlessCommitted()
 
 	// Ingest the tx and commit
-	err = w.insertTx(services.NewConsumerContext(ctx, job, dbTx, i.Timestamp()), i.Body())
+	cCtx := services.NewConsumableContext(ctx, job, dbTx, i)
+	err = w.insertTx(cCtx, i.Body())
 	if err != nil {
 		return stacktrace.Propagate(err, "Failed to insert tx")
 	}
@@ -140,6 +141,7 @@ func (w *Writer) Consume(ctx context.Context, i services.Consumable) error {
 		return stacktrace.Propagate(err, "Failed to commit database tx")
 	}
 
+	avax.InvalidateAggregates(ctx, w.conns, job, w.chainID, cCtx.Time())
 	return nil
 }
 
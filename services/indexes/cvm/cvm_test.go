@@ -0,0 +1,164 @@
+// (c) 2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cvm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+
+	"github.com/ava-labs/ortelius/cfg"
+	"github.com/ava-labs/ortelius/services"
+	"github.com/ava-labs/ortelius/services/indexes/avm"
+	"github.com/ava-labs/ortelius/services/indexes/models"
+	"github.com/ava-labs/ortelius/services/indexes/params"
+)
+
+var (
+	testCChainID = ids.NewID([32]byte{3})
+	testAssetID  = ids.NewID([32]byte{4})
+)
+
+func TestConsumeAtomicTxs(t *testing.T) {
+	w, r, closeFn := newTestIndex(t, 12345, testCChainID)
+	defer closeFn()
+
+	// Export 100 from the C chain and then import it back
+	exportTx := &UnsignedExportTx{
+		NetworkID:        12345,
+		BlockchainID:     testCChainID,
+		DestinationChain: ids.NewID([32]byte{5}),
+		Ins:              []EVMInput{{Amount: 101, AssetID: testAssetID}},
+		ExportedOutputs: []*avax.TransferableOutput{{
+			Asset: avax.Asset{ID: testAssetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt:          100,
+				OutputOwners: secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{ids.NewShortID([20]byte{1})}},
+			},
+		}},
+	}
+	exportTxID := consumeTestTx(t, w, exportTx)
+
+	importTx := &UnsignedImportTx{
+		NetworkID:    12345,
+		BlockchainID: testCChainID,
+		SourceChain:  ids.NewID([32]byte{5}),
+		ImportedInputs: []*avax.TransferableInput{{
+			UTXOID: avax.UTXOID{TxID: exportTxID, OutputIndex: 0},
+			Asset:  avax.Asset{ID: testAssetID},
+			In:     &secp256k1fx.TransferInput{Amt: 100, Input: secp256k1fx.Input{SigIndices: []uint32{}}},
+		}},
+		Outs: []EVMOutput{{Amount: 99, AssetID: testAssetID}},
+	}
+	importTxID := consumeTestTx(t, w, importTx, &secp256k1fx.Credential{})
+
+	var txs []*models.Transaction
+	for _, expected := range []struct {
+		id  ids.ID
+		typ models.TransactionType
+	}{
+		{exportTxID, models.TransactionTypeCVMExport},
+		{importTxID, models.TransactionTypeCVMImport},
+	} {
+		tx, err := r.GetTransaction(context.Background(), expected.id)
+		if err != nil {
+			t.Fatal("Failed to get transaction:", err.Error())
+		}
+		if tx == nil {
+			t.Fatal("Missing transaction:", expected.id.String())
+		}
+		if tx.Type != expected.typ.String() {
+			t.Fatal("Incorrect transaction type:", tx.Type)
+		}
+		txs = append(txs, tx)
+	}
+
+	// The exported UTXO is consumed by the import and the EVM side is ignored
+	assetID := models.ToStringID(testAssetID)
+	if len(txs[0].Outputs) != 1 || txs[0].OutputTotals[assetID].Amount != "100" {
+		t.Fatal("Incorrect export outputs:", txs[0].OutputTotals)
+	}
+	if len(txs[1].Inputs) != 1 || len(txs[1].Outputs) != 0 {
+		t.Fatal("Incorrect import inputs and outputs:", len(txs[1].Inputs), len(txs[1].Outputs))
+	}
+	if !txs[1].Inputs[0].Output.RedeemingTransactionID.Equals(models.ToStringID(importTxID)) {
+		t.Fatal("Incorrect redeeming transaction:", txs[1].Inputs[0].Output.RedeemingTransactionID)
+	}
+
+	outputList, err := r.ListOutputs(context.Background(), &params.ListOutputsParams{})
+	if err != nil {
+		t.Fatal("Failed to list outputs:", err.Error())
+	}
+	if len(outputList.Outputs) != 1 {
+		t.Fatal("Incorrect number of outputs:", len(outputList.Outputs))
+	}
+}
+
+func consumeTestTx(t *testing.T, w *Writer, unsignedTx UnsignedAtomicTx, creds ...verify.Verifiable) ids.ID {
+	tx := &Tx{UnsignedTx: unsignedTx, Creds: creds}
+	txBytes, err := w.codec.Marshal(tx)
+	if err != nil {
+		t.Fatal("Failed to marshal tx:", err.Error())
+	}
+
+	job := w.conns.Stream().NewJob("test")
+	sess := w.conns.DB().NewSessionForEventReceiver(job)
+	err = w.insertTx(services.NewConsumerContext(context.Background(), job, sess, time.Now().Unix()), txBytes)
+	if err != nil {
+		t.Fatal("Failed to insert tx:", err.Error())
+	}
+
+	// The tx is initialized from its bytes which gives it its ID
+	tx.UnsignedTx.Initialize(nil, txBytes)
+	return tx.UnsignedTx.ID()
+}
+
+func newTestIndex(t *testing.T, networkID uint32, chainID ids.ID) (*Writer, *avm.Reader, func()) {
+	// Start test redis
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatal("Failed to create miniredis server:", err.Error())
+	}
+
+	logConf, err := logging.DefaultConfig()
+	if err != nil {
+		t.Fatal("Failed to create logging config:", err.Error())
+	}
+
+	conf := cfg.Services{
+		Logging: logConf,
+		DB: &cfg.DB{
+			TXDB:   true,
+			Driver: "mysql",
+			DSN:    "root:password@tcp(127.0.0.1:3306)/ortelius_test?parseTime=true",
+		},
+		Redis: &cfg.Redis{
+			Addr: s.Addr(),
+		},
+	}
+
+	conns, err := services.NewConnectionsFromConfig(conf)
+	if err != nil {
+		t.Fatal("Failed to create connections:", err.Error())
+	}
+
+	// Create index
+	writer, err := NewWriter(conns, networkID, chainID.String())
+	if err != nil {
+		t.Fatal("Failed to create writer:", err.Error())
+	}
+
+	reader := avm.NewReader(conns, chainID.String())
+	return writer, reader, func() {
+		s.Close()
+		conns.Close()
+	}
+}
@@ -0,0 +1,92 @@
+// (c) 2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cvm
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/codec"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+// The types in this file mirror the atomic transactions of the C chain so that
+// they can be parsed without depending on the EVM implementation. Only the
+// fields needed for indexing are used but all of them must be present to match
+// the serialization format.
+
+// Tx is a signed atomic transaction
+type Tx struct {
+	UnsignedTx UnsignedAtomicTx    `serialize:"true" json:"unsignedTx"`
+	Creds      []verify.Verifiable `serialize:"true" json:"credentials"`
+}
+
+// UnsignedAtomicTx is an unsigned import or export transaction
+type UnsignedAtomicTx interface {
+	Initialize(unsignedBytes, bytes []byte)
+	ID() ids.ID
+	UnsignedBytes() []byte
+	Bytes() []byte
+}
+
+// EVMOutput credits an account on the C chain
+type EVMOutput struct {
+	Address [20]byte `serialize:"true" json:"address"`
+	Amount  uint64   `serialize:"true" json:"amount"`
+	AssetID ids.ID   `serialize:"true" json:"assetID"`
+}
+
+// EVMInput debits an account on the C chain
+type EVMInput struct {
+	Address [20]byte `serialize:"true" json:"address"`
+	Amount  uint64   `serialize:"true" json:"amount"`
+	AssetID ids.ID   `serialize:"true" json:"assetID"`
+	Nonce   uint64   `serialize:"true" json:"nonce"`
+}
+
+// UnsignedImportTx consumes UTXOs exported from another chain and credits
+// C chain accounts
+type UnsignedImportTx struct {
+	avax.Metadata
+
+	NetworkID      uint32                    `serialize:"true" json:"networkID"`
+	BlockchainID   ids.ID                    `serialize:"true" json:"blockchainID"`
+	SourceChain    ids.ID                    `serialize:"true" json:"sourceChain"`
+	ImportedInputs []*avax.TransferableInput `serialize:"true" json:"importedInputs"`
+	Outs           []EVMOutput               `serialize:"true" json:"outputs"`
+}
+
+// UnsignedExportTx debits C chain accounts and creates UTXOs for another chain
+type UnsignedExportTx struct {
+	avax.Metadata
+
+	NetworkID        uint32                     `serialize:"true" json:"networkID"`
+	BlockchainID     ids.ID                     `serialize:"true" json:"blockchainID"`
+	DestinationChain ids.ID                     `serialize:"true" json:"destinationChain"`
+	Ins              []EVMInput                 `serialize:"true" json:"inputs"`
+	ExportedOutputs  []*avax.TransferableOutput `serialize:"true" json:"exportedOutputs"`
+}
+
+// newCodec returns a codec with the type IDs used by the C chain
+func newCodec() (codec.Codec, error) {
+	c := codec.NewDefault()
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		c.RegisterType(&UnsignedImportTx{}),
+		c.RegisterType(&UnsignedExportTx{}),
+	)
+	c.Skip(3)
+	errs.Add(
+		c.RegisterType(&secp256k1fx.TransferInput{}),
+		c.RegisterType(&secp256k1fx.MintOutput{}),
+		c.RegisterType(&secp256k1fx.TransferOutput{}),
+		c.RegisterType(&secp256k1fx.MintOperation{}),
+		c.RegisterType(&secp256k1fx.Credential{}),
+		c.RegisterType(&secp256k1fx.Input{}),
+		c.RegisterType(&secp256k1fx.OutputOwners{}),
+	)
+	return c, errs.Err
+}
@@ -0,0 +1,131 @@
+// (c) 2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cvm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/utils/codec"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/health"
+	"github.com/palantir/stacktrace"
+
+	"github.com/ava-labs/ortelius/services"
+	avaxIndexer "github.com/ava-labs/ortelius/services/indexes/avax"
+	"github.com/ava-labs/ortelius/services/indexes/models"
+)
+
+const VMName = "evm"
+
+var (
+	ErrUnknownTxType = errors.New("unknown tx type")
+)
+
+// Writer indexes the atomic transactions of the C chain. Regular EVM
+// transactions don't move UTXOs and are not indexed.
+type Writer struct {
+	chainID   string
+	networkID uint32
+
+	codec codec.Codec
+	conns *services.Connections
+	avax  *avaxIndexer.Writer
+}
+
+func NewWriter(conns *services.Connections, networkID uint32, chainID string) (*Writer, error) {
+	cvmCodec, err := newCodec()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Writer{
+		conns:     conns,
+		chainID:   chainID,
+		networkID: networkID,
+		codec:     cvmCodec,
+		avax:      avaxIndexer.NewWriter(chainID, conns.Stream()),
+	}, nil
+}
+
+func (*Writer) Name() string { return "cvm-index" }
+
+// Bootstrap is a no-op because the C chain genesis contains no atomic txs
+func (*Writer) Bootstrap(context.Context) error { return nil }
+
+func (w *Writer) Consume(ctx context.Context, i services.Consumable) error {
+	var (
+		err  error
+		job  = w.conns.Stream().NewJob("index")
+		sess = w.conns.DB().NewSessionForEventReceiver(job)
+	)
+	job.KeyValue("id", i.ID())
+	job.KeyValue("chain_id", i.ChainID())
+
+	defer func() {
+		if err != nil {
+			job.CompleteKv(health.Error, health.Kvs{"err": err.Error()})
+			return
+		}
+		job.Complete(health.Success)
+	}()
+
+	// Create db tx
+	var dbTx *dbr.Tx
+	dbTx, err = sess.Begin()
+	if err != nil {
+		return err
+	}
+	defer dbTx.RollbackUnlessCommitted()
+
+	// Ingest the tx and commit
+	cCtx := services.NewConsumableContext(ctx, job, dbTx, i)
+	err = w.insertTx(cCtx, i.Body())
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to insert tx")
+	}
+
+	if err = dbTx.Commit(); err != nil {
+		return stacktrace.Propagate(err, "Failed to commit database tx")
+	}
+
+	avaxIndexer.InvalidateAggregates(ctx, w.conns, job, w.chainID, cCtx.Time())
+	return nil
+}
+
+func (w *Writer) insertTx(ctx services.ConsumerCtx, txBytes []byte) error {
+	tx := &Tx{}
+	if err := w.codec.Unmarshal(txBytes, tx); err != nil {
+		return err
+	}
+
+	unsignedBytes, err := w.codec.Marshal(&tx.UnsignedTx)
+	if err != nil {
+		return err
+	}
+
+	tx.UnsignedTx.Initialize(unsignedBytes, txBytes)
+
+	// The EVM side of each tx is account based, so only the UTXOs on the
+	// avax side are recorded
+	switch castTx := tx.UnsignedTx.(type) {
+	case *UnsignedImportTx:
+		baseTx := &avax.BaseTx{
+			Metadata:     castTx.Metadata,
+			NetworkID:    castTx.NetworkID,
+			BlockchainID: castTx.BlockchainID,
+		}
+		return w.avax.InsertTransaction(ctx, txBytes, unsignedBytes, baseTx, tx.Creds, models.TransactionTypeCVMImport, castTx.ImportedInputs, nil)
+	case *UnsignedExportTx:
+		baseTx := &avax.BaseTx{
+			Metadata:     castTx.Metadata,
+			NetworkID:    castTx.NetworkID,
+			BlockchainID: castTx.BlockchainID,
+		}
+		return w.avax.InsertTransaction(ctx, txBytes, unsignedBytes, baseTx, tx.Creds, models.TransactionTypeCVMExport, nil, castTx.ExportedOutputs)
+	default:
+		return ErrUnknownTxType
+	}
+}
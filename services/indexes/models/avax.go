@@ -4,7 +4,12 @@
 package models
 
 import (
+	"encoding/json"
+	"net/url"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 type Transaction struct {
@@ -15,15 +20,36 @@ type Transaction struct {
 	Inputs  []*Input  `json:"inputs"`
 	Outputs []*Output `json:"outputs"`
 
+	// InputCount and OutputCount are set even when Inputs and Outputs are
+	// left out of lightly dressed transactions
+	InputCount  int `json:"inputCount"`
+	OutputCount int `json:"outputCount"`
+
 	Memo []byte `json:"memo"`
 
+	// DecodedMemo is the memo as text if possible, since Memo is raw bytes
+	DecodedMemo DecodedMemo `json:"decodedMemo"`
+
 	InputTotals         AssetTokenCounts `json:"inputTotals"`
 	OutputTotals        AssetTokenCounts `json:"outputTotals"`
 	ReusedAddressTotals AssetTokenCounts `json:"reusedAddressTotals"`
 
+	// Fees is the amount of each asset consumed by the transaction, i.e. its
+	// inputs minus its outputs. Assets whose outputs are greater than or equal
+	// to their inputs, such as newly minted assets, are omitted.
+	Fees AssetTokenCounts `json:"fees"`
+
+	// Assets holds the symbol and denomination of each asset in the totals
+	// when they're requested, so they don't need to be looked up separately
+	Assets map[StringID]AssetInfoLite `json:"assets,omitempty"`
+
 	CanonicalSerialization []byte    `json:"canonicalSerialization,omitempty"`
 	CreatedAt              time.Time `json:"timestamp"`
 
+	// Height is the transaction's position in the order its chain accepted it
+	// in, starting at 1. It's 0 if it isn't known.
+	Height uint64 `json:"height"`
+
 	Score uint64 `json:"-"`
 }
 
@@ -46,13 +72,89 @@ type Output struct {
 
 	RedeemingTransactionID StringID `json:"redeemingTransactionID"`
 
+	// RedeemedAt is when the redeeming transaction was created. It's only set
+	// for spent outputs, and only when it's requested.
+	RedeemedAt *time.Time `json:"redeemedAt,omitempty"`
+
+	// Locked is true if the output's Locktime hasn't passed when it was listed,
+	// so it can't be spent yet. UnlocksAt is the time of a non-zero Locktime.
+	Locked    bool       `json:"locked"`
+	UnlocksAt *time.Time `json:"unlocksAt,omitempty"`
+
+	// FormattedAmount is the Amount in whole units of the asset. It's only set
+	// when the asset's denomination is known.
+	FormattedAmount string `json:"formattedAmount,omitempty"`
+
+	// Payload is the raw payload of NFT outputs. DecodedPayload is set from it
+	// when requested, and left nil if it isn't in a known format.
+	Payload        []byte          `json:"-"`
+	DecodedPayload *DecodedPayload `json:"decodedPayload,omitempty"`
+
 	Score uint64 `json:"-"`
 }
 
+// PayloadFormat is a known format of NFT payloads
+type PayloadFormat string
+
+const (
+	PayloadFormatURL  PayloadFormat = "url"
+	PayloadFormatJSON PayloadFormat = "json"
+	PayloadFormatText PayloadFormat = "text"
+)
+
+// DecodedPayload is an NFT payload interpreted as UTF-8 text
+type DecodedPayload struct {
+	Format PayloadFormat `json:"format"`
+	Text   string        `json:"text"`
+}
+
+// DecodePayload interprets an NFT payload as a URL, a JSON object or array, or
+// other UTF-8 text, in that order. It returns nil for empty payloads and for
+// binary ones, which aren't valid UTF-8 or contain control characters other
+// than whitespace.
+func DecodePayload(payload []byte) *DecodedPayload {
+	if len(payload) == 0 || !utf8.Valid(payload) {
+		return nil
+	}
+
+	text := string(payload)
+	for _, r := range text {
+		if unicode.IsControl(r) && !unicode.IsSpace(r) {
+			return nil
+		}
+	}
+
+	trimmed := strings.TrimSpace(text)
+	switch {
+	case isPayloadURL(trimmed):
+		return &DecodedPayload{Format: PayloadFormatURL, Text: trimmed}
+	case strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "["):
+		if json.Valid(payload) {
+			return &DecodedPayload{Format: PayloadFormatJSON, Text: text}
+		}
+	}
+	return &DecodedPayload{Format: PayloadFormatText, Text: text}
+}
+
+// isPayloadURL returns true if s is a single absolute URL with a host, such as
+// an http or ipfs link
+func isPayloadURL(s string) bool {
+	if strings.IndexFunc(s, unicode.IsSpace) != -1 {
+		return false
+	}
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+// InputCredentials is a signature of an input by one of its addresses. The
+// PublicKey is recovered from the signature when the transaction is indexed.
+// It's empty, and HasPublicKey is false, if it couldn't be recovered, but the
+// credential is still listed since the signature exists.
 type InputCredentials struct {
-	Address   Address `json:"address"`
-	PublicKey []byte  `json:"public_key"`
-	Signature []byte  `json:"signature"`
+	Address      Address `json:"address"`
+	PublicKey    []byte  `json:"public_key"`
+	HasPublicKey bool    `json:"hasPublicKey"`
+	Signature    []byte  `json:"signature"`
 }
 
 type OutputAddress struct {
@@ -74,9 +176,20 @@ type Asset struct {
 	CurrentSupply TokenAmount `json:"currentSupply"`
 	CreatedAt     time.Time   `json:"timestamp"`
 
+	// CreatedByTransactionID is the CreateAssetTx, or genesis tx, that created
+	// the asset
+	CreatedByTransactionID StringID `json:"createdByTransactionID"`
+
 	Score uint64 `json:"-"`
 }
 
+// AssetInfoLite is the information about an asset needed to display amounts
+// of it
+type AssetInfoLite struct {
+	Symbol       string `json:"symbol"`
+	Denomination uint8  `json:"denomination"`
+}
+
 type AssetInfo struct {
 	AssetID StringID `json:"id"`
 
@@ -87,9 +200,21 @@ type AssetInfo struct {
 	TotalSent        TokenAmount `json:"totalSent"`
 }
 
+// NFTGroupStats summarizes the NFTs of a single group of an asset. HolderCount
+// is the number of distinct addresses owning an unspent NFT of the group, and
+// LastActivity is the last time one of its NFTs was created or spent.
+type NFTGroupStats struct {
+	GroupID      uint32    `json:"groupID"`
+	OutputCount  uint64    `json:"outputCount"`
+	HolderCount  uint64    `json:"holderCount"`
+	LastActivity time.Time `json:"lastActivity"`
+}
+
 type AddressInfo struct {
-	Address   Address `json:"address"`
-	PublicKey []byte  `json:"publicKey"`
+	Address Address `json:"address"`
+
+	// PublicKey is nil if it hasn't been recovered from a signature
+	PublicKey []byte `json:"publicKey"`
 
 	Assets map[StringID]AssetInfo `json:"assets"`
 
@@ -99,4 +224,8 @@ type AddressInfo struct {
 type OutputList struct {
 	ListMetadata
 	Outputs []*Output `json:"outputs"`
+
+	// NextCursor is set when paginating by cursor and more results are
+	// available. Clients pass it back verbatim to fetch the next page.
+	NextCursor string `json:"nextCursor,omitempty"`
 }
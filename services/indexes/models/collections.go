@@ -9,6 +9,14 @@ import (
 
 type ListMetadata struct {
 	Count uint64 `json:"count"`
+
+	// HasMore is true if there are more results after this page. Unlike Count
+	// it's set even when counting is disabled.
+	HasMore bool `json:"hasMore"`
+
+	// Approximate is true if Count was served from a cache that's refreshed
+	// in the background, so it may lag behind the index
+	Approximate bool `json:"approximate"`
 }
 
 type TransactionList struct {
@@ -19,6 +27,10 @@ type TransactionList struct {
 type AssetList struct {
 	ListMetadata
 	Assets []*Asset `json:"assets"`
+
+	// NextCursor is set when paginating by cursor and more results are
+	// available. Clients pass it back verbatim to fetch the next page.
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 type AddressList struct {
@@ -53,10 +65,37 @@ type SearchResult struct {
 	Score uint64 `json:"score"`
 }
 
+// IndexStatus describes how far behind the indexer is on each chain as of
+// Time
+type IndexStatus struct {
+	Time   time.Time          `json:"time"`
+	Chains []ChainIndexStatus `json:"chains"`
+}
+
+// ChainIndexStatus is the time of the latest transaction indexed on a chain
+// and how long before the IndexStatus's Time it was
+type ChainIndexStatus struct {
+	ChainID               StringID      `json:"chainID"`
+	LatestTransactionTime time.Time     `json:"latestTransactionTime"`
+	Lag                   time.Duration `json:"lag"`
+}
+
 type AggregatesHistogram struct {
 	Aggregates   Aggregates    `json:"aggregates"`
 	IntervalSize time.Duration `json:"intervalSize,omitempty"`
 	Intervals    []Aggregates  `json:"intervals,omitempty"`
+
+	// IntervalCount is the number of intervals in the whole time range, which
+	// is more than the length of Intervals when only a window was requested
+	IntervalCount int `json:"intervalCount,omitempty"`
+
+	// RequestedEndTime is the end time that was requested, when it was moved
+	// back to the end of the last full interval
+	RequestedEndTime *time.Time `json:"requestedEndTime,omitempty"`
+
+	// Chains holds the aggregates of each chain over the whole time range. It's
+	// only set when grouping by chain was requested.
+	Chains map[string]Aggregates `json:"chains,omitempty"`
 }
 
 type Aggregates struct {
@@ -73,4 +112,61 @@ type Aggregates struct {
 	AddressCount     uint64 `json:"addressCount"`
 	OutputCount      uint64 `json:"outputCount"`
 	AssetCount       uint64 `json:"assetCount"`
+
+	// SupplyChange is the net change in an asset's supply. It's only set by
+	// supply histograms and may be negative.
+	SupplyChange TokenAmount `json:"supplyChange,omitempty"`
+
+	// Velocity is the TransactionVolume divided by the asset's current supply,
+	// as a decimal string. It's only set when aggregating a single asset.
+	Velocity string `json:"velocity,omitempty"`
+}
+
+// AssetVolume is the sum of the amounts of an asset's outputs created in a
+// time window
+type AssetVolume struct {
+	AssetID StringID    `json:"assetID"`
+	Volume  TokenAmount `json:"volume" db:"transaction_volume"`
+}
+
+// IntegrityReport lists the records of the index that contradict each other,
+// as found by an integrity scan
+type IntegrityReport struct {
+	Outputs      []*OutputInconsistency `json:"outputs"`
+	Transactions []*FeeInconsistency    `json:"transactions"`
+
+	// HasMore is true if the scan stopped at its limit, so there may be more
+	// inconsistencies
+	HasMore bool `json:"hasMore"`
+}
+
+// InconsistencyReason tells why an output is inconsistent
+type InconsistencyReason string
+
+const (
+	// InconsistencyRedeemerNotIndexed is a spent output whose redeeming
+	// transaction isn't indexed
+	InconsistencyRedeemerNotIndexed InconsistencyReason = "redeemer_not_indexed"
+
+	// InconsistencyRedeemedBeforeCreated is a spent output whose redeeming
+	// transaction was accepted before the output was created
+	InconsistencyRedeemedBeforeCreated InconsistencyReason = "redeemed_before_created"
+)
+
+// OutputInconsistency is a spent output that contradicts the record of the
+// transaction spending it
+type OutputInconsistency struct {
+	ID                     StringID            `json:"id"`
+	TransactionID          StringID            `json:"transactionID"`
+	RedeemingTransactionID StringID            `json:"redeemingTransactionID"`
+	Reason                 InconsistencyReason `json:"reason"`
+}
+
+// FeeInconsistency is a transaction whose outputs of an asset add up to more
+// than the outputs of it that it spends, implying a negative fee
+type FeeInconsistency struct {
+	TransactionID StringID    `json:"transactionID"`
+	AssetID       StringID    `json:"assetID"`
+	InputAmount   TokenAmount `json:"inputAmount"`
+	OutputAmount  TokenAmount `json:"outputAmount"`
 }
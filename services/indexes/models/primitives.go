@@ -4,14 +4,23 @@
 package models
 
 import (
+	"bytes"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"math/big"
 	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 )
 
+var ErrInvalidTokenAmount = errors.New("invalid token amount")
+
 // bech32HRP is the human-readable part of bech32 addresses. It needs to be
 // available to Address.MarshalJSON is there is no other way to give it this
 // data
@@ -73,8 +82,61 @@ func (addr Address) MarshalJSON() ([]byte, error) {
 	return json.Marshal(bech32Addr)
 }
 
-// AssetTokenCounts maps asset IDs to a TokenAmount for that asset.
-type AssetTokenCounts map[StringID]TokenAmount
+// AssetTokenCount is an amount of an asset in the asset's base unit.
+// Denomination is set when the asset's denomination is known, which adds the
+// formatted amount to the JSON encoding.
+type AssetTokenCount struct {
+	Amount       TokenAmount
+	Denomination *uint8
+}
+
+// AssetTokenCounts maps asset IDs to an AssetTokenCount for that asset.
+type AssetTokenCounts map[StringID]AssetTokenCount
+
+type assetTokenCountJSON struct {
+	Amount          TokenAmount `json:"amount"`
+	FormattedAmount string      `json:"formattedAmount,omitempty"`
+}
+
+// MarshalJSON encodes each count as an object holding the amount as an integer
+// string, and the formatted amount if the denomination is known. Amounts that
+// aren't integers, such as ones in scientific notation, are rejected.
+func (c AssetTokenCounts) MarshalJSON() ([]byte, error) {
+	counts := make(map[StringID]assetTokenCountJSON, len(c))
+	for assetID, count := range c {
+		var denomination uint8
+		if count.Denomination != nil {
+			denomination = *count.Denomination
+		}
+
+		formattedAmount, err := count.Amount.Format(denomination)
+		if err != nil {
+			return nil, err
+		}
+
+		countJSON := assetTokenCountJSON{Amount: count.Amount}
+		if count.Denomination != nil {
+			countJSON.FormattedAmount = formattedAmount
+		}
+		counts[assetID] = countJSON
+	}
+	return json.Marshal(counts)
+}
+
+// UnmarshalJSON decodes counts encoded by MarshalJSON. The denominations
+// aren't encoded so they're left unset.
+func (c *AssetTokenCounts) UnmarshalJSON(b []byte) error {
+	counts := map[StringID]assetTokenCountJSON{}
+	if err := json.Unmarshal(b, &counts); err != nil {
+		return err
+	}
+
+	*c = make(AssetTokenCounts, len(counts))
+	for assetID, count := range counts {
+		(*c)[assetID] = AssetTokenCount{Amount: count.Amount}
+	}
+	return nil
+}
 
 // TokenAmount represents some number of tokens as a string.
 type TokenAmount string
@@ -83,3 +145,107 @@ type TokenAmount string
 func TokenAmountForUint64(i uint64) TokenAmount {
 	return TokenAmount(strconv.Itoa(int(i)))
 }
+
+// Format returns the amount in whole units of an asset with the given
+// denomination, e.g. "1234500" with a denomination of 6 is "1.2345". Trailing
+// zeros after the decimal point are removed.
+func (t TokenAmount) Format(denomination uint8) (string, error) {
+	amount, ok := new(big.Int).SetString(string(t), 10)
+	if !ok {
+		return "", ErrInvalidTokenAmount
+	}
+
+	sign := ""
+	if amount.Sign() < 0 {
+		sign = "-"
+		amount.Neg(amount)
+	}
+
+	digits := amount.String()
+	if denomination == 0 {
+		return sign + digits, nil
+	}
+
+	// Left pad so there's at least one digit before the decimal point
+	if len(digits) <= int(denomination) {
+		digits = strings.Repeat("0", int(denomination)-len(digits)+1) + digits
+	}
+
+	whole := digits[:len(digits)-int(denomination)]
+	fraction := strings.TrimRight(digits[len(digits)-int(denomination):], "0")
+	if fraction == "" {
+		return sign + whole, nil
+	}
+	return sign + whole + "." + fraction, nil
+}
+
+// Amount is a TokenAmount parsed for arithmetic. The zero value is 0. Setting
+// or adding to an Amount reuses its storage, so a single Amount can parse many
+// TokenAmounts, or accumulate a total of them, without allocating for each one.
+type Amount struct {
+	v big.Int
+}
+
+// ParseAmount parses a TokenAmount into a new Amount
+func ParseAmount(t TokenAmount) (*Amount, error) {
+	a := &Amount{}
+	if err := a.SetTokenAmount(t); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// SetTokenAmount sets a to the value of t. Amounts that fit in a uint64, such
+// as those of single outputs, are parsed without allocating.
+func (a *Amount) SetTokenAmount(t TokenAmount) error {
+	if i, err := strconv.ParseUint(string(t), 10, 64); err == nil {
+		a.v.SetUint64(i)
+		return nil
+	}
+	if _, ok := a.v.SetString(string(t), 10); !ok {
+		return ErrInvalidTokenAmount
+	}
+	return nil
+}
+
+// Add sets a to the sum of a and b and returns a
+func (a *Amount) Add(b *Amount) *Amount {
+	a.v.Add(&a.v, &b.v)
+	return a
+}
+
+// Int returns the value of a. Changing it changes a.
+func (a *Amount) Int() *big.Int {
+	return &a.v
+}
+
+// TokenAmount returns the value of a as a TokenAmount
+func (a *Amount) TokenAmount() TokenAmount {
+	return TokenAmount(a.v.String())
+}
+
+// DecodedMemo is a best-effort human-readable form of a transaction's memo
+type DecodedMemo struct {
+	// Text is the memo itself if it's text, or else its hex encoding
+	Text string `json:"text"`
+
+	// IsText is true if Text is the memo itself rather than its hex encoding
+	IsText bool `json:"isText"`
+}
+
+// DecodeMemo decodes the memo as text if it's valid UTF-8 without control
+// characters other than whitespace. Memos are often padded with null bytes so
+// trailing nulls are removed first, but a memo with any other null byte isn't
+// considered text. Memos that aren't text are hex encoded.
+func DecodeMemo(memo []byte) DecodedMemo {
+	text := bytes.TrimRight(memo, "\x00")
+	if !utf8.Valid(text) {
+		return DecodedMemo{Text: hex.EncodeToString(memo)}
+	}
+	for _, r := range string(text) {
+		if unicode.IsControl(r) && !unicode.IsSpace(r) {
+			return DecodedMemo{Text: hex.EncodeToString(memo)}
+		}
+	}
+	return DecodedMemo{Text: string(text), IsText: true}
+}
@@ -0,0 +1,166 @@
+// (c) 2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package models
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestTokenAmountFormat(t *testing.T) {
+	for _, test := range []struct {
+		amount       TokenAmount
+		denomination uint8
+		expected     string
+	}{
+		{"0", 0, "0"},
+		{"12345", 0, "12345"},
+		{"0", 6, "0"},
+		{"1", 6, "0.000001"},
+		{"123456", 6, "0.123456"},
+		{"1000000", 6, "1"},
+		{"1234500", 6, "1.2345"},
+		{"0", 9, "0"},
+		{"1", 9, "0.000000001"},
+		{"500000000", 9, "0.5"},
+		{"360000000000000000", 9, "360000000"},
+		{"18446744073709551616000000001", 9, "18446744073709551616.000000001"},
+	} {
+		formatted, err := test.amount.Format(test.denomination)
+		if err != nil {
+			t.Fatal("Failed to format amount:", err.Error())
+		}
+		if formatted != test.expected {
+			t.Fatalf("Incorrect format of %s with denomination %d: %s", test.amount, test.denomination, formatted)
+		}
+	}
+
+	for _, amount := range []TokenAmount{"", "1.5", "abc"} {
+		if _, err := amount.Format(6); err != ErrInvalidTokenAmount {
+			t.Fatalf("Expected an error for %q, got %v", amount, err)
+		}
+	}
+}
+
+func TestAmount(t *testing.T) {
+	total := &Amount{}
+	for _, amount := range []TokenAmount{"0", "1", "18446744073709551615", "18446744073709551616000000001"} {
+		parsed, err := ParseAmount(amount)
+		if err != nil {
+			t.Fatal("Failed to parse amount:", err.Error())
+		}
+		if parsed.TokenAmount() != amount {
+			t.Fatalf("Incorrect amount for %s: %s", amount, parsed.TokenAmount())
+		}
+		total.Add(parsed)
+	}
+	if total.TokenAmount() != "18446744092156295689709551617" {
+		t.Fatal("Incorrect total:", total.TokenAmount())
+	}
+
+	// Setting an Amount replaces its value
+	if err := total.SetTokenAmount("5"); err != nil {
+		t.Fatal("Failed to set amount:", err.Error())
+	}
+	if total.Int().Cmp(big.NewInt(5)) != 0 {
+		t.Fatal("Incorrect amount:", total.TokenAmount())
+	}
+
+	for _, amount := range []TokenAmount{"", "1.5", "abc"} {
+		if _, err := ParseAmount(amount); err != ErrInvalidTokenAmount {
+			t.Fatalf("Expected an error for %q, got %v", amount, err)
+		}
+	}
+}
+
+// BenchmarkAmountTotals compares totaling amounts by parsing each one into a
+// new big.Int with reusing a single Amount
+func BenchmarkAmountTotals(b *testing.B) {
+	amounts := make([]TokenAmount, 100)
+	for i := range amounts {
+		amounts[i] = TokenAmountForUint64(uint64(i) * 1000000007)
+	}
+
+	b.Run("big.Int", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			total := big.NewInt(0)
+			for _, amount := range amounts {
+				amt, ok := new(big.Int).SetString(string(amount), 10)
+				if !ok {
+					b.Fatal("Failed to parse amount")
+				}
+				total.Add(total, amt)
+			}
+		}
+	})
+
+	b.Run("Amount", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			total, amt := &Amount{}, &Amount{}
+			for _, amount := range amounts {
+				if err := amt.SetTokenAmount(amount); err != nil {
+					b.Fatal("Failed to parse amount:", err.Error())
+				}
+				total.Add(amt)
+			}
+		}
+	})
+}
+
+func TestAssetTokenCountsJSON(t *testing.T) {
+	denomination := uint8(9)
+	counts := AssetTokenCounts{
+		"asset1": {Amount: "12345678901234567890", Denomination: &denomination},
+		"asset2": {Amount: "98765432109876543210"},
+	}
+
+	countsJSON, err := json.Marshal(counts)
+	if err != nil {
+		t.Fatal("Failed to marshal counts:", err.Error())
+	}
+	expectedJSON := `{"asset1":{"amount":"12345678901234567890","formattedAmount":"12345678901.23456789"},"asset2":{"amount":"98765432109876543210"}}`
+	if string(countsJSON) != expectedJSON {
+		t.Fatal("Incorrect JSON:", string(countsJSON))
+	}
+
+	var decoded AssetTokenCounts
+	if err = json.Unmarshal(countsJSON, &decoded); err != nil {
+		t.Fatal("Failed to unmarshal counts:", err.Error())
+	}
+	if len(decoded) != len(counts) {
+		t.Fatal("Incorrect number of counts:", len(decoded))
+	}
+	for assetID, count := range counts {
+		if decoded[assetID].Amount != count.Amount {
+			t.Fatalf("Incorrect amount for %s: %s", assetID, decoded[assetID].Amount)
+		}
+	}
+
+	// Amounts that aren't integers can't be marshaled
+	if _, err = json.Marshal(AssetTokenCounts{"asset1": {Amount: "1.2345678901234567e+19"}}); err == nil {
+		t.Fatal("Expected an error marshaling an amount in scientific notation")
+	}
+}
+
+func TestDecodeMemo(t *testing.T) {
+	for _, test := range []struct {
+		memo     []byte
+		expected DecodedMemo
+	}{
+		{nil, DecodedMemo{Text: "", IsText: true}},
+		{[]byte{}, DecodedMemo{Text: "", IsText: true}},
+		{[]byte("hello, wörld ✓\n"), DecodedMemo{Text: "hello, wörld ✓\n", IsText: true}},
+		{[]byte("padded\x00\x00\x00"), DecodedMemo{Text: "padded", IsText: true}},
+		{[]byte("embedded\x00null"), DecodedMemo{Text: "656d626564646564006e756c6c"}},
+		{[]byte{0xff, 0xfe, 0x01}, DecodedMemo{Text: "fffe01"}},
+		{[]byte{0x1b, '[', '3', '1', 'm'}, DecodedMemo{Text: "1b5b33316d"}},
+	} {
+		if decoded := DecodeMemo(test.memo); decoded != test.expected {
+			t.Fatalf("Incorrect decoding of %x: %+v", test.memo, decoded)
+		}
+	}
+}
@@ -29,6 +29,8 @@ var (
 	TransactionTypePVMExport          TransactionType = 0x12
 	TransactionTypeAdvanceTime        TransactionType = 0x13
 	TransactionTypeRewardValidator    TransactionType = 0x14
+	TransactionTypeCVMImport          TransactionType = 0x15
+	TransactionTypeCVMExport          TransactionType = 0x16
 
 	ResultTypeTransaction SearchResultType = "transaction"
 	ResultTypeAsset       SearchResultType = "asset"
@@ -76,6 +78,12 @@ func (t TransactionType) String() string {
 		return "advance_time"
 	case TransactionTypeRewardValidator:
 		return "reward_validator"
+
+		// CVM
+	case TransactionTypeCVMImport:
+		return "cvm_import"
+	case TransactionTypeCVMExport:
+		return "cvm_export"
 	default:
 		return "unknown"
 	}
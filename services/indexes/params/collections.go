@@ -5,6 +5,8 @@ package params
 
 import (
 	"errors"
+	"fmt"
+	"math/big"
 	"net/url"
 	"strconv"
 	"strings"
@@ -20,6 +22,28 @@ const (
 	TransactionSortDefault       TransactionSort = TransactionSortTimestampAsc
 	TransactionSortTimestampAsc                  = "timestamp-asc"
 	TransactionSortTimestampDesc                 = "timestamp-desc"
+	TransactionSortVolumeAsc                     = "volume-asc"
+	TransactionSortVolumeDesc                    = "volume-desc"
+
+	OutputSortDefault       OutputSort = OutputSortTimestampAsc
+	OutputSortTimestampAsc             = "timestamp-asc"
+	OutputSortTimestampDesc            = "timestamp-desc"
+	OutputSortAmountAsc                = "amount-asc"
+	OutputSortAmountDesc               = "amount-desc"
+
+	AssetSortDefault      AssetSort = AssetSortTimestampAsc
+	AssetSortTimestampAsc           = "timestamp-asc"
+	AssetSortCreatedDesc            = "timestamp-desc"
+	AssetSortRelevance              = "relevance"
+
+	QueryModeDefault   QueryMode = QueryModePrefix
+	QueryModePrefix              = "prefix"
+	QueryModeSubstring           = "substring"
+
+	IntervalAlignmentDefault IntervalAlignment = IntervalAlignmentNone
+	IntervalAlignmentNone                      = "none"
+	IntervalAlignmentStrict                    = "strict"
+	IntervalAlignmentAlign                     = "align"
 )
 
 var (
@@ -62,6 +86,32 @@ type AggregateParams struct {
 	StartTime    time.Time
 	EndTime      time.Time
 	IntervalSize time.Duration
+
+	// GroupByChain adds the totals of each chain over the whole time range.
+	// Intervals aren't broken down by chain.
+	GroupByChain bool
+
+	// VolumeExcludedOutputTypes leaves outputs of these types, such as NFTs
+	// whose amounts aren't fungible, out of the transaction volume. They're
+	// still counted in every other aggregate.
+	VolumeExcludedOutputTypes []models.OutputType
+
+	// IntervalOffset and IntervalLimit select the window of intervals that is
+	// returned, so a long series can be fetched in parts. The overall
+	// aggregates still cover the whole time range. An IntervalLimit of 0
+	// returns every interval after the offset.
+	IntervalOffset int
+	IntervalLimit  int
+
+	// IntervalAlignment controls what happens when the time range isn't a
+	// multiple of the IntervalSize, which would leave the last interval
+	// partial. Strict alignment rejects the range, and aligning it moves the
+	// EndTime back to the end of the last full interval.
+	IntervalAlignment IntervalAlignment
+
+	// RequestedEndTime is the EndTime before AlignEndTime moved it. It's zero
+	// if the EndTime wasn't moved.
+	RequestedEndTime time.Time
 }
 
 func (p *AggregateParams) ForValues(q url.Values) (err error) {
@@ -91,9 +141,100 @@ func (p *AggregateParams) ForValues(q url.Values) (err error) {
 		return err
 	}
 
+	p.GroupByChain, err = GetQueryBool(q, KeyGroupByChain, false)
+	if err != nil {
+		return err
+	}
+
+	p.IntervalOffset, err = GetQueryInt(q, KeyIntervalOffset, 0)
+	if err != nil {
+		return err
+	}
+
+	p.IntervalLimit, err = GetQueryInt(q, KeyIntervalLimit, 0)
+	if err != nil {
+		return err
+	}
+
+	p.IntervalAlignment, err = toIntervalAlignment(GetQueryString(q, KeyIntervalAlignment, string(IntervalAlignmentDefault)))
+	if err != nil {
+		return err
+	}
+
+	for _, outputTypeStr := range q[KeyVolumeExcludeOutputType] {
+		outputType, err := toOutputType(outputTypeStr)
+		if err != nil {
+			return err
+		}
+		p.VolumeExcludedOutputTypes = append(p.VolumeExcludedOutputTypes, outputType)
+	}
+
 	return nil
 }
 
+// Validate returns an error if the time range or interval size can't produce
+// a histogram, or if any of the VolumeExcludedOutputTypes is undefined. An
+// IntervalSize of 0 aggregates the whole range at once. With strict alignment
+// the time range must also be a multiple of the IntervalSize.
+func (p *AggregateParams) Validate() error {
+	for _, outputType := range p.VolumeExcludedOutputTypes {
+		if !isOutputType(outputType) {
+			return fmt.Errorf("%w: %d", ErrUndefinedOutputType, outputType)
+		}
+	}
+	if p.EndTime.Before(p.StartTime) {
+		return ErrInvalidTimeRange
+	}
+	if p.IntervalSize < 0 {
+		return ErrInvalidIntervalSize
+	}
+	if p.IntervalSize > p.EndTime.Sub(p.StartTime) {
+		return ErrIntervalSizeTooLarge
+	}
+	if p.IntervalOffset < 0 || p.IntervalLimit < 0 {
+		return ErrInvalidIntervalWindow
+	}
+	if p.IntervalAlignment == IntervalAlignmentStrict && p.partialInterval() != 0 {
+		return ErrIntervalRangeNotAligned
+	}
+	return nil
+}
+
+// AlignEndTime moves the EndTime back to the end of the last full interval if
+// aligning was requested, recording the original in RequestedEndTime. Ranges
+// shorter than a single interval are left for Validate to reject.
+func (p *AggregateParams) AlignEndTime() {
+	if p.IntervalAlignment != IntervalAlignmentAlign || p.IntervalSize > p.EndTime.Sub(p.StartTime) {
+		return
+	}
+	if partial := p.partialInterval(); partial != 0 {
+		p.RequestedEndTime = p.EndTime
+		p.EndTime = p.EndTime.Add(-partial)
+	}
+}
+
+// partialInterval returns the length of the time range past the end of the
+// last full interval
+func (p *AggregateParams) partialInterval() time.Duration {
+	if p.IntervalSize <= 0 {
+		return 0
+	}
+	return p.EndTime.Sub(p.StartTime) % p.IntervalSize
+}
+
+// IntervalWindow returns the indexes of the first interval to return and the
+// one after the last, given the number of intervals in the whole range
+func (p *AggregateParams) IntervalWindow(intervalCount int) (start int, end int) {
+	start, end = p.IntervalOffset, intervalCount
+	if p.IntervalLimit > 0 && start+p.IntervalLimit < end {
+		end = start + p.IntervalLimit
+	}
+	if start > end {
+		start = end
+	}
+	return start, end
+}
+
 func (p *AggregateParams) CacheKey() []string {
 	k := make([]string, 0, 4)
 
@@ -106,8 +247,24 @@ func (p *AggregateParams) CacheKey() []string {
 		CacheKey(KeyEndTime, RoundTime(p.EndTime, time.Hour).Unix()),
 		CacheKey(KeyIntervalSize, int64(p.IntervalSize.Seconds())),
 		CacheKey(KeyChainID, strings.Join(p.ChainIDs, "|")),
+		CacheKey(KeyGroupByChain, p.GroupByChain),
 	)
 
+	for _, outputType := range p.VolumeExcludedOutputTypes {
+		k = append(k, CacheKey(KeyVolumeExcludeOutputType, uint32(outputType)))
+	}
+
+	if p.IntervalOffset != 0 || p.IntervalLimit != 0 {
+		k = append(k,
+			CacheKey(KeyIntervalOffset, p.IntervalOffset),
+			CacheKey(KeyIntervalLimit, p.IntervalLimit),
+		)
+	}
+
+	if p.IntervalAlignment != "" && p.IntervalAlignment != IntervalAlignmentDefault {
+		k = append(k, CacheKey(KeyIntervalAlignment, p.IntervalAlignment))
+	}
+
 	return k
 }
 
@@ -120,7 +277,7 @@ func (p *AggregateParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
 	}
 
 	if len(p.ChainIDs) > 0 {
-		b.Where("avm_outputs.chain_id = ?", p.ChainIDs)
+		b.Where("avm_outputs.chain_id IN ?", p.ChainIDs)
 	}
 
 	return b
@@ -136,15 +293,54 @@ type ListTransactionsParams struct {
 	ID       *ids.ID
 	ChainIDs []string
 
+	// IDs restricts results to the given transactions
+	IDs []ids.ID
+
+	// IDPrefix restricts results to transactions whose IDs start with it
+	IDPrefix string
+
 	Query string
 
 	Addresses []ids.ShortID
 	AssetID   *ids.ID
 
+	// RecipientAddress restricts results to transactions with an output to
+	// the address that didn't spend any of the address's outputs, so that
+	// funds received can be told apart from funds sent
+	RecipientAddress *ids.ShortID
+
+	// AssetIDs restricts results to transactions with at least one input or
+	// output in one of the given assets. It is applied even when Query is set,
+	// and results are ordered by Sort either way.
+	AssetIDs []ids.ID
+
+	// StartTime and EndTime bound the transactions' creation time, inclusively.
+	// A zero time leaves that side unbounded. They're applied along with Query.
 	StartTime time.Time
 	EndTime   time.Time
 
+	// MinHeight restricts results to transactions accepted after the given
+	// height and orders them by height, ascending. It can't be combined with a
+	// Sort other than the default. Paging with the height of the last
+	// transaction of each page syncs a chain.
+	MinHeight *uint64
+
+	// Sort orders the results. Transactions sharing a timestamp are ordered by
+	// ID, in the same direction as the timestamp sorts and ascending for the
+	// volume sorts, so offset pagination is stable across requests.
 	Sort TransactionSort
+
+	// IncludeSerialization loads each transaction's canonical serialization,
+	// which is otherwise left empty to keep responses small
+	IncludeSerialization bool
+
+	// Light leaves out each transaction's inputs and outputs, keeping only
+	// their counts and totals, for pages that only summarize transactions
+	Light bool
+
+	// IncludeAssets adds the symbol and denomination of each asset in a
+	// transaction's totals to the transaction
+	IncludeAssets bool
 }
 
 func (p *ListTransactionsParams) ForValues(q url.Values) error {
@@ -166,9 +362,13 @@ func (p *ListTransactionsParams) ForValues(q url.Values) error {
 
 	p.ChainIDs = q[KeyChainID]
 
-	p.AssetID, err = GetQueryID(q, KeyAssetID)
-	if err != nil {
-		return err
+	assetIDStrs := q[KeyAssetID]
+	for _, assetIDStr := range assetIDStrs {
+		assetID, err := ids.FromString(assetIDStr)
+		if err != nil {
+			return err
+		}
+		p.AssetIDs = append(p.AssetIDs, assetID)
 	}
 
 	addressStrs := q[KeyAddress]
@@ -180,6 +380,11 @@ func (p *ListTransactionsParams) ForValues(q url.Values) error {
 		p.Addresses = append(p.Addresses, addr)
 	}
 
+	p.RecipientAddress, err = GetQueryAddress(q, KeyRecipientAddress)
+	if err != nil {
+		return err
+	}
+
 	p.StartTime, err = GetQueryTime(q, KeyStartTime)
 	if err != nil {
 		return err
@@ -190,6 +395,35 @@ func (p *ListTransactionsParams) ForValues(q url.Values) error {
 		return err
 	}
 
+	p.MinHeight, err = GetQueryUint64(q, KeyMinHeight)
+	if err != nil {
+		return err
+	}
+
+	p.IncludeSerialization, err = GetQueryBool(q, KeyIncludeSerialization, false)
+	if err != nil {
+		return err
+	}
+
+	p.Light, err = GetQueryBool(q, KeyLight, false)
+	if err != nil {
+		return err
+	}
+
+	p.IncludeAssets, err = GetQueryBool(q, KeyIncludeAssets, false)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate returns an error if MinHeight is combined with a sort other than the
+// default, since syncing by height orders transactions by height instead
+func (p *ListTransactionsParams) Validate() error {
+	if p.MinHeight != nil && p.Sort != "" && p.Sort != TransactionSortDefault {
+		return ErrMinHeightWithSort
+	}
 	return nil
 }
 
@@ -201,18 +435,42 @@ func (p *ListTransactionsParams) CacheKey() []string {
 		k = append(k, CacheKey(KeyID, p.ID.String()))
 	}
 
+	for _, id := range p.IDs {
+		k = append(k, CacheKey(KeyID, id.String()))
+	}
+
+	if p.IDPrefix != "" {
+		k = append(k, CacheKey(KeyIDPrefix, p.IDPrefix))
+	}
+
 	if p.AssetID != nil {
 		k = append(k, CacheKey(KeyAssetID, p.AssetID.String()))
 	}
 
+	for _, assetID := range p.AssetIDs {
+		k = append(k, CacheKey(KeyAssetID, assetID.String()))
+	}
+
 	for _, address := range p.Addresses {
 		k = append(k, CacheKey(KeyAddress, address.String()))
 	}
 
+	if p.RecipientAddress != nil {
+		k = append(k, CacheKey(KeyRecipientAddress, p.RecipientAddress.String()))
+	}
+
+	if p.MinHeight != nil {
+		k = append(k, CacheKey(KeyMinHeight, *p.MinHeight))
+	}
+
 	k = append(k,
-		CacheKey(KeyStartTime, RoundTime(p.StartTime, time.Hour).Unix()),
-		CacheKey(KeyEndTime, RoundTime(p.EndTime, time.Hour).Unix()),
+		// The bounds aren't rounded when applied so they can't be rounded here
+		CacheKey(KeyStartTime, p.StartTime.Unix()),
+		CacheKey(KeyEndTime, p.EndTime.Unix()),
 		CacheKey(KeyChainID, strings.Join(p.ChainIDs, "|")),
+		CacheKey(KeyIncludeSerialization, p.IncludeSerialization),
+		CacheKey(KeyLight, p.Light),
+		CacheKey(KeyIncludeAssets, p.IncludeAssets),
 	)
 
 	return k
@@ -220,7 +478,7 @@ func (p *ListTransactionsParams) CacheKey() []string {
 
 // true if we will need to left join
 func (p *ListTransactionsParams) NeedsDistinct() bool {
-	return len(p.Addresses) > 0 || p.AssetID != nil
+	return len(p.Addresses) > 0 || p.AssetID != nil || len(p.AssetIDs) > 0
 }
 
 func (p *ListTransactionsParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
@@ -232,7 +490,19 @@ func (p *ListTransactionsParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder
 			Limit(1)
 	}
 
-	needOutputsJoin := len(p.Addresses) > 0 || p.AssetID != nil
+	if len(p.IDs) > 0 {
+		txIDs := make([]string, len(p.IDs))
+		for i, id := range p.IDs {
+			txIDs[i] = id.String()
+		}
+		b = b.Where("avm_transactions.id IN ?", txIDs)
+	}
+
+	if p.IDPrefix != "" {
+		b = b.Where(dbr.Like("avm_transactions.id", escapeLike(p.IDPrefix)+"%"))
+	}
+
+	needOutputsJoin := len(p.Addresses) > 0 || p.AssetID != nil || len(p.AssetIDs) > 0
 	if needOutputsJoin {
 		b = b.LeftJoin("avm_outputs", "(avm_outputs.transaction_id = avm_transactions.id OR avm_outputs.redeeming_transaction_id = avm_transactions.id)")
 	}
@@ -250,6 +520,21 @@ func (p *ListTransactionsParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder
 		b = b.Where("avm_outputs.asset_id = ?", p.AssetID.String())
 	}
 
+	if p.RecipientAddress != nil {
+		addr := p.RecipientAddress.String()
+		b = b.
+			Where("avm_transactions.id IN (SELECT avm_outputs.transaction_id FROM avm_outputs JOIN avm_output_addresses ON avm_output_addresses.output_id = avm_outputs.id WHERE avm_output_addresses.address = ?)", addr).
+			Where("avm_transactions.id NOT IN (SELECT avm_outputs.redeeming_transaction_id FROM avm_outputs JOIN avm_output_addresses ON avm_output_addresses.output_id = avm_outputs.id WHERE avm_output_addresses.address = ?)", addr)
+	}
+
+	if len(p.AssetIDs) > 0 {
+		assetIDs := make([]string, len(p.AssetIDs))
+		for i, id := range p.AssetIDs {
+			assetIDs[i] = id.String()
+		}
+		b = b.Where("avm_outputs.asset_id IN ?", assetIDs)
+	}
+
 	if !p.StartTime.IsZero() {
 		b = b.Where("avm_transactions.created_at >= ?", p.StartTime)
 	}
@@ -257,12 +542,26 @@ func (p *ListTransactionsParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder
 		b = b.Where("avm_transactions.created_at <= ?", p.EndTime)
 	}
 
+	if p.MinHeight != nil {
+		b = b.Where("avm_transactions.height > ?", *p.MinHeight)
+	}
+
 	if p.Query != "" {
-		b.Where(dbr.Like("avm_transactions.id", p.Query+"%"))
+		// Match IDs by prefix and memos by substring. Queries longer than the
+		// largest memo can't match one so they aren't compared against memos.
+		query := escapeLike(p.Query)
+		if len(p.Query) > MaxMemoLength {
+			b.Where(dbr.Like("avm_transactions.id", query+"%"))
+		} else {
+			b.Where(dbr.Or(
+				dbr.Like("avm_transactions.id", query+"%"),
+				dbr.Like("avm_transactions.memo", "%"+query+"%"),
+			))
+		}
 	}
 
 	if len(p.ChainIDs) > 0 {
-		b.Where("avm_transactions.chain_id = ?", p.ChainIDs)
+		b.Where("avm_transactions.chain_id IN ?", p.ChainIDs)
 	}
 
 	return b
@@ -270,35 +569,132 @@ func (p *ListTransactionsParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder
 
 type ListAssetsParams struct {
 	ListParams
-	ID    *ids.ID
-	Query string
-	Alias string
+	ID       *ids.ID
+	ChainIDs []string
+	Query    string
+	Alias    string
+
+	// QueryMode controls whether the Query matches the start of an asset's ID,
+	// name, or symbol, or any part of them. Prefix matches can use the indexes
+	// and are much faster.
+	QueryMode QueryMode
+
+	// IDs restricts results to the given assets
+	IDs []ids.ID
+
+	// StartTime and EndTime bound the creation time of the assets. A zero time
+	// leaves that side unbounded.
+	StartTime time.Time
+	EndTime   time.Time
+
+	// Sort orders the results. AssetSortRelevance ranks exact symbol matches
+	// of the Query first, then prefix matches of the symbol or name, with ties
+	// broken by the largest current supply. AssetSortCreatedDesc lists the
+	// newest assets first. Cursor pagination only supports the default sort.
+	Sort AssetSort
+
+	// StartAfter enables cursor pagination. When set, only assets created
+	// after the cursor in (created_at, id) order are returned. A zero cursor
+	// returns the first page.
+	StartAfter *Cursor
 }
 
-func (p *ListAssetsParams) ForValue(q url.Values) error {
+func (p *ListAssetsParams) ForValues(q url.Values) error {
 	err := p.ListParams.ForValues(q)
 	if err != nil {
 		return err
 	}
 
+	p.Sort = AssetSortDefault
+	sortBys, ok := q[KeySortBy]
+	if ok && len(sortBys) >= 1 {
+		p.Sort, _ = toAssetSort(sortBys[0])
+	}
+
+	cursorStrs, ok := q[KeyCursor]
+	if ok && len(cursorStrs) >= 1 {
+		cursor, err := ParseCursor(cursorStrs[0])
+		if err != nil {
+			return err
+		}
+		p.StartAfter = &cursor
+	}
+
 	p.ID, err = GetQueryID(q, KeyID)
 	if err != nil {
 		return err
 	}
 
+	p.ChainIDs = q[KeyChainID]
+
+	p.Query = GetQueryString(q, KeySearchQuery, "")
+
+	p.QueryMode, err = toQueryMode(GetQueryString(q, KeyQueryMode, string(QueryModeDefault)))
+	if err != nil {
+		return err
+	}
+
+	p.StartTime, err = GetQueryTime(q, KeyStartTime)
+	if err != nil {
+		return err
+	}
+
+	p.EndTime, err = GetQueryTime(q, KeyEndTime)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func (p *ListAssetsParams) CacheKey() []string {
 	k := p.ListParams.CacheKey()
 
+	if p.Query != "" {
+		k = append(k,
+			CacheKey(KeySearchQuery, p.Query),
+			CacheKey(KeyQueryMode, p.QueryMode),
+		)
+	}
+
 	if p.ID != nil {
 		k = append(k, CacheKey(KeyID, p.ID.String()))
 	}
 
+	for _, id := range p.IDs {
+		k = append(k, CacheKey(KeyID, id.String()))
+	}
+
+	k = append(k, CacheKey(KeyChainID, strings.Join(p.ChainIDs, "|")))
+
+	if !p.StartTime.IsZero() {
+		k = append(k, CacheKey(KeyStartTime, p.StartTime.Unix()))
+	}
+
+	if !p.EndTime.IsZero() {
+		k = append(k, CacheKey(KeyEndTime, p.EndTime.Unix()))
+	}
+
+	if p.Sort != "" {
+		k = append(k, CacheKey(KeySortBy, p.Sort))
+	}
+
+	if p.StartAfter != nil {
+		k = append(k, CacheKey(KeyCursor, p.StartAfter.String()))
+	}
+
 	return k
 }
 
+// Validate returns an error if a cursor is combined with a sort other than
+// the default
+func (p *ListAssetsParams) Validate() error {
+	if p.StartAfter != nil && p.Sort != "" && p.Sort != AssetSortDefault {
+		return ErrCursorWithSort
+	}
+	return nil
+}
+
 func (p *ListAssetsParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
 	p.ListParams.Apply(b)
 
@@ -308,26 +704,70 @@ func (p *ListAssetsParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
 			Limit(1)
 	}
 
+	if len(p.IDs) > 0 {
+		assetIDs := make([]string, len(p.IDs))
+		for i, id := range p.IDs {
+			assetIDs[i] = id.String()
+		}
+		b = b.Where("avm_assets.id IN ?", assetIDs)
+	}
+
 	if p.Alias != "" {
 		b = b.
 			Where("alias = ?", p.Alias)
 	}
 
 	if p.Query != "" {
+		pattern := escapeLike(p.Query) + "%"
+		if p.QueryMode == QueryModeSubstring {
+			pattern = "%" + pattern
+		}
 		b.Where(dbr.Or(
-			dbr.Like("avm_assets.id", p.Query+"%"),
-			dbr.Like("avm_assets.name", p.Query+"%"),
-			dbr.Like("avm_assets.symbol", p.Query+"%"),
+			dbr.Like("avm_assets.id", pattern),
+			dbr.Like("avm_assets.name", pattern),
+			dbr.Like("avm_assets.symbol", pattern),
 		))
 	}
 
+	if len(p.ChainIDs) > 0 {
+		b.Where("avm_assets.chain_id IN ?", p.ChainIDs)
+	}
+
+	if !p.StartTime.IsZero() {
+		b.Where("avm_assets.created_at >= ?", p.StartTime)
+	}
+	if !p.EndTime.IsZero() {
+		b.Where("avm_assets.created_at <= ?", p.EndTime)
+	}
+
+	if p.StartAfter != nil {
+		b = p.StartAfter.Apply(b, "avm_assets")
+	}
+
 	return b
 }
 
 type ListAddressesParams struct {
 	ListParams
-	Address *ids.ShortID
-	Query   string
+	Address  *ids.ShortID
+	ChainIDs []string
+	Query    string
+
+	// AssetID restricts results to addresses that have received the asset, and
+	// MinBalance further restricts them to addresses whose unspent balance of
+	// that asset is at least MinBalance base units. MinBalance is ignored
+	// without an AssetID.
+	AssetID    *ids.ID
+	MinBalance *big.Int
+
+	// SortByBalance orders addresses by their unspent balance of AssetID,
+	// largest first, for example to list the top holders of an asset. It's
+	// ignored without an AssetID.
+	SortByBalance bool
+
+	// HasPublicKey restricts results to addresses whose public key has been
+	// recovered from a signature when true, or hasn't when false
+	HasPublicKey *bool
 }
 
 func (p *ListAddressesParams) ForValues(q url.Values) error {
@@ -349,6 +789,41 @@ func (p *ListAddressesParams) ForValues(q url.Values) error {
 		p.Address = &addr
 	}
 
+	p.AssetID, err = GetQueryID(q, KeyAssetID)
+	if err != nil {
+		return err
+	}
+
+	p.MinBalance, err = GetQueryBigInt(q, KeyMinBalance)
+	if err != nil {
+		return err
+	}
+	if p.MinBalance != nil && p.AssetID == nil {
+		return ErrMinBalanceWithoutAsset
+	}
+
+	sortByBalanceStrs, ok := q[KeySortByBalance]
+	if ok && len(sortByBalanceStrs) >= 1 {
+		p.SortByBalance, err = strconv.ParseBool(sortByBalanceStrs[0])
+		if err != nil {
+			return err
+		}
+	}
+	if p.SortByBalance && p.AssetID == nil {
+		return ErrSortByBalanceWithoutAsset
+	}
+
+	hasPublicKeyStrs, ok := q[KeyHasPublicKey]
+	if ok && len(hasPublicKeyStrs) >= 1 {
+		b, err := strconv.ParseBool(hasPublicKeyStrs[0])
+		if err != nil {
+			return err
+		}
+		p.HasPublicKey = &b
+	}
+
+	p.ChainIDs = q[KeyChainID]
+
 	return nil
 }
 
@@ -359,9 +834,37 @@ func (p *ListAddressesParams) CacheKey() []string {
 		k = append(k, CacheKey(KeyAddress, p.Address.String()))
 	}
 
+	if p.AssetID != nil {
+		k = append(k, CacheKey(KeyAssetID, p.AssetID.String()))
+	}
+
+	if p.MinBalance != nil {
+		k = append(k, CacheKey(KeyMinBalance, p.MinBalance.String()))
+	}
+
+	if p.SortByBalance {
+		k = append(k, CacheKey(KeySortByBalance, p.SortByBalance))
+	}
+
+	if p.HasPublicKey != nil {
+		k = append(k, CacheKey(KeyHasPublicKey, *p.HasPublicKey))
+	}
+
+	k = append(k, CacheKey(KeyChainID, strings.Join(p.ChainIDs, "|")))
+
 	return k
 }
 
+// UnspentBalanceColumn sums the unspent amounts of the outputs of an address
+// when they're grouped by address
+const UnspentBalanceColumn = "SUM(CASE WHEN avm_outputs.redeeming_transaction_id = '' THEN avm_outputs.amount ELSE 0 END)"
+
+// NeedsGrouping returns true if the query groups rows by address, in which case
+// counting must be done over a subquery
+func (p *ListAddressesParams) NeedsGrouping() bool {
+	return p.AssetID != nil && (p.MinBalance != nil || p.SortByBalance)
+}
+
 func (p *ListAddressesParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
 	p.ListParams.Apply(b)
 
@@ -369,6 +872,38 @@ func (p *ListAddressesParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
 		b = b.
 			Where("avm_output_addresses.address = ?", p.Address.String()).
 			Limit(1)
+	} else if p.Query != "" {
+		b = b.Where(dbr.Like("avm_output_addresses.address", escapeLike(p.Query)+"%"))
+	}
+
+	if p.AssetID != nil || len(p.ChainIDs) > 0 {
+		b = b.Join("avm_outputs", "avm_outputs.id = avm_output_addresses.output_id")
+	}
+
+	if p.AssetID != nil {
+		b = b.Where("avm_outputs.asset_id = ?", p.AssetID.String())
+	}
+
+	if len(p.ChainIDs) > 0 {
+		b = b.Where("avm_outputs.chain_id IN ?", p.ChainIDs)
+	}
+
+	// A subquery is used rather than a join since the list query already joins
+	// the addresses table for the public keys
+	if p.HasPublicKey != nil {
+		withPublicKey := "avm_output_addresses.address IN (SELECT addresses.address FROM addresses WHERE addresses.public_key IS NOT NULL)"
+		if *p.HasPublicKey {
+			b = b.Where(withPublicKey)
+		} else {
+			b = b.Where("NOT " + withPublicKey)
+		}
+	}
+
+	if p.NeedsGrouping() {
+		b = b.GroupBy("avm_output_addresses.address")
+		if p.MinBalance != nil {
+			b = b.Having(UnspentBalanceColumn+" >= CAST(? AS DECIMAL(65))", p.MinBalance.String())
+		}
 	}
 
 	return b
@@ -376,11 +911,51 @@ func (p *ListAddressesParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
 
 type ListOutputsParams struct {
 	ListParams
-	ID        *ids.ID
-	ChainIDs  []string
+	ID       *ids.ID
+	ChainIDs []string
+	Spent    *bool
+	Query    string
+
+	// Addresses restricts results to outputs owned by any of the addresses.
+	// Outputs owned by several of them, such as multisig outputs, are matched
+	// once per address, so queries must be DISTINCT if NeedsDistinct is true.
 	Addresses []ids.ShortID
-	Spent     *bool
-	Query     string
+
+	// OutputTypes restricts results to outputs of the given types. Use
+	// Validate to reject types that don't exist.
+	OutputTypes []models.OutputType
+
+	// GroupID restricts results to outputs of the given NFT group. Outputs
+	// that aren't NFTs are stored with a group ID of 0, so filtering by group
+	// 0 also matches them unless OutputTypes is restricted to NFT types.
+	GroupID *uint32
+
+	// StartAfter enables cursor pagination. When set, results are ordered by
+	// (created_at, id) and only rows after the cursor are returned. A zero
+	// cursor returns the first page.
+	StartAfter *Cursor
+
+	// Sort orders the results. Ties are broken by (created_at, id). Cursor
+	// pagination only supports the default sort.
+	Sort OutputSort
+
+	// IncludeRedeemedAt sets the time spent outputs were spent at, which
+	// needs the redeeming transactions to be joined
+	IncludeRedeemedAt bool
+
+	// IncludeAddresses set to false skips the query loading the addresses of
+	// the outputs. Addresses are included when it's nil.
+	IncludeAddresses *bool
+
+	// DecodePayloads sets the DecodedPayload of NFT outputs whose payload is
+	// in a known format
+	DecodePayloads bool
+
+	// MinAmount and MaxAmount restrict results to outputs whose amount, in the
+	// asset's base units, is within the inclusive range. Either may be nil to
+	// leave that side unbounded.
+	MinAmount *big.Int
+	MaxAmount *big.Int
 }
 
 func (p *ListOutputsParams) ForValues(q url.Values) error {
@@ -414,6 +989,86 @@ func (p *ListOutputsParams) ForValues(q url.Values) error {
 		p.Spent = &b
 	}
 
+	cursorStrs, ok := q[KeyCursor]
+	if ok && len(cursorStrs) >= 1 {
+		cursor, err := ParseCursor(cursorStrs[0])
+		if err != nil {
+			return err
+		}
+		p.StartAfter = &cursor
+	}
+
+	for _, outputTypeStr := range q[KeyOutputType] {
+		outputType, err := toOutputType(outputTypeStr)
+		if err != nil {
+			return err
+		}
+		p.OutputTypes = append(p.OutputTypes, outputType)
+	}
+
+	p.GroupID, err = GetQueryUint32(q, KeyGroupID)
+	if err != nil {
+		return err
+	}
+
+	p.Sort = OutputSortDefault
+	sortBys, ok := q[KeySortBy]
+	if ok && len(sortBys) >= 1 {
+		p.Sort, _ = toOutputSort(sortBys[0])
+	}
+
+	p.IncludeRedeemedAt, err = GetQueryBool(q, KeyIncludeRedeemedAt, false)
+	if err != nil {
+		return err
+	}
+
+	p.DecodePayloads, err = GetQueryBool(q, KeyDecodePayloads, false)
+	if err != nil {
+		return err
+	}
+
+	p.MinAmount, err = GetQueryBigInt(q, KeyMinAmount)
+	if err != nil {
+		return err
+	}
+	p.MaxAmount, err = GetQueryBigInt(q, KeyMaxAmount)
+	if err != nil {
+		return err
+	}
+
+	includeAddressesStrs, ok := q[KeyIncludeAddresses]
+	if ok && len(includeAddressesStrs) >= 1 {
+		b, err := strconv.ParseBool(includeAddressesStrs[0])
+		if err != nil {
+			return err
+		}
+		p.IncludeAddresses = &b
+	}
+
+	return nil
+}
+
+// AddressesIncluded returns whether the addresses of the outputs are loaded,
+// which they are unless IncludeAddresses is false
+func (p *ListOutputsParams) AddressesIncluded() bool {
+	return p.IncludeAddresses == nil || *p.IncludeAddresses
+}
+
+// Validate returns an error if any of the OutputTypes is undefined, if a
+// cursor is combined with a sort other than the default, or if MinAmount is
+// greater than MaxAmount
+func (p *ListOutputsParams) Validate() error {
+	for _, outputType := range p.OutputTypes {
+		if !isOutputType(outputType) {
+			return fmt.Errorf("%w: %d", ErrUndefinedOutputType, outputType)
+		}
+	}
+	if p.StartAfter != nil && p.Sort != "" && p.Sort != OutputSortDefault {
+		return ErrCursorWithSort
+	}
+	if p.MinAmount != nil && p.MaxAmount != nil && p.MinAmount.Cmp(p.MaxAmount) > 0 {
+		return ErrInvalidAmountRange
+	}
 	return nil
 }
 
@@ -438,9 +1093,50 @@ func (p *ListOutputsParams) CacheKey() []string {
 		k = append(k, CacheKey(KeySearchQuery, p.Query))
 	}
 
+	if p.StartAfter != nil {
+		k = append(k, CacheKey(KeyCursor, p.StartAfter.String()))
+	}
+
+	for _, outputType := range p.OutputTypes {
+		k = append(k, CacheKey(KeyOutputType, uint32(outputType)))
+	}
+
+	if p.GroupID != nil {
+		k = append(k, CacheKey(KeyGroupID, *p.GroupID))
+	}
+
+	if p.Sort != "" {
+		k = append(k, CacheKey(KeySortBy, p.Sort))
+	}
+
+	if p.IncludeRedeemedAt {
+		k = append(k, CacheKey(KeyIncludeRedeemedAt, p.IncludeRedeemedAt))
+	}
+
+	if !p.AddressesIncluded() {
+		k = append(k, CacheKey(KeyIncludeAddresses, false))
+	}
+
+	if p.DecodePayloads {
+		k = append(k, CacheKey(KeyDecodePayloads, p.DecodePayloads))
+	}
+
+	if p.MinAmount != nil {
+		k = append(k, CacheKey(KeyMinAmount, p.MinAmount.String()))
+	}
+
+	if p.MaxAmount != nil {
+		k = append(k, CacheKey(KeyMaxAmount, p.MaxAmount.String()))
+	}
+
 	return k
 }
 
+// true if an output can be joined to more than one of the addresses
+func (p *ListOutputsParams) NeedsDistinct() bool {
+	return len(p.Addresses) > 1
+}
+
 func (p *ListOutputsParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
 	p.ListParams.Apply(b)
 
@@ -472,7 +1168,28 @@ func (p *ListOutputsParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
 	}
 
 	if len(p.ChainIDs) > 0 {
-		b.Where("avm_outputs.chain_id = ?", p.ChainIDs)
+		b.Where("avm_outputs.chain_id IN ?", p.ChainIDs)
+	}
+
+	if len(p.OutputTypes) > 0 {
+		b.Where("avm_outputs.output_type IN ?", p.OutputTypes)
+	}
+
+	if p.GroupID != nil {
+		b.Where("avm_outputs.group_id = ?", *p.GroupID)
+	}
+
+	// The bounds may not fit in the unsigned amount column, so they're compared
+	// as decimals
+	if p.MinAmount != nil {
+		b.Where("avm_outputs.amount >= CAST(? AS DECIMAL(65))", p.MinAmount.String())
+	}
+	if p.MaxAmount != nil {
+		b.Where("avm_outputs.amount <= CAST(? AS DECIMAL(65))", p.MaxAmount.String())
+	}
+
+	if p.StartAfter != nil {
+		b = p.StartAfter.Apply(b, "avm_outputs")
 	}
 
 	return b
@@ -523,8 +1240,95 @@ func toTransactionSort(s string) (TransactionSort, error) {
 		return TransactionSortTimestampAsc, nil
 	case TransactionSortTimestampDesc:
 		return TransactionSortTimestampDesc, nil
+	case TransactionSortVolumeAsc:
+		return TransactionSortVolumeAsc, nil
+	case TransactionSortVolumeDesc:
+		return TransactionSortVolumeDesc, nil
 	}
 	return TransactionSortDefault, ErrUndefinedSort
 }
 
+type OutputSort string
+
+func toOutputSort(s string) (OutputSort, error) {
+	switch s {
+	case OutputSortTimestampAsc:
+		return OutputSortTimestampAsc, nil
+	case OutputSortTimestampDesc:
+		return OutputSortTimestampDesc, nil
+	case OutputSortAmountAsc:
+		return OutputSortAmountAsc, nil
+	case OutputSortAmountDesc:
+		return OutputSortAmountDesc, nil
+	}
+	return OutputSortDefault, ErrUndefinedSort
+}
+
+type AssetSort string
+
+func toAssetSort(s string) (AssetSort, error) {
+	switch s {
+	case AssetSortTimestampAsc:
+		return AssetSortTimestampAsc, nil
+	case AssetSortCreatedDesc:
+		return AssetSortCreatedDesc, nil
+	case AssetSortRelevance:
+		return AssetSortRelevance, nil
+	}
+	return AssetSortDefault, ErrUndefinedSort
+}
+
+type QueryMode string
+
+func toQueryMode(s string) (QueryMode, error) {
+	switch s {
+	case QueryModePrefix:
+		return QueryModePrefix, nil
+	case QueryModeSubstring:
+		return QueryModeSubstring, nil
+	}
+	return QueryModeDefault, fmt.Errorf("%w: %s", ErrUndefinedQueryMode, s)
+}
+
+type IntervalAlignment string
+
+func toIntervalAlignment(s string) (IntervalAlignment, error) {
+	switch s {
+	case IntervalAlignmentNone:
+		return IntervalAlignmentNone, nil
+	case IntervalAlignmentStrict:
+		return IntervalAlignmentStrict, nil
+	case IntervalAlignmentAlign:
+		return IntervalAlignmentAlign, nil
+	}
+	return IntervalAlignmentDefault, fmt.Errorf("%w: %s", ErrUndefinedIntervalAlignment, s)
+}
+
+// outputTypes are the output types that can be filtered by
+var outputTypes = []models.OutputType{
+	models.OutputTypesSECP2556K1Transfer,
+	models.OutputTypesSECP2556K1Mint,
+	models.OutputTypesNFTTransfer,
+	models.OutputTypesNFTMint,
+}
+
+// toOutputType parses an output type from either its name or its numeric value
+func toOutputType(s string) (models.OutputType, error) {
+	for _, outputType := range outputTypes {
+		if s == outputType.String() || s == strconv.FormatUint(uint64(outputType), 10) {
+			return outputType, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", ErrUndefinedOutputType, s)
+}
+
+func isOutputType(t models.OutputType) bool {
+	for _, outputType := range outputTypes {
+		if t == outputType {
+			return true
+		}
+	}
+	return false
+}
+
 type BlockSort string
@@ -7,8 +7,11 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/gocraft/dbr/v2"
 )
 
@@ -26,12 +29,68 @@ const (
 	KeyEndTime      = "endTime"
 	KeyIntervalSize = "intervalSize"
 	KeyDisableCount = "disableCount"
+	KeyCountOnly    = "countOnly"
+	KeyExactCount   = "exactCount"
+	KeyCursor       = "cursor"
+	KeyMinBalance   = "minBalance"
+	KeyHasPublicKey = "hasPublicKey"
+	KeyOutputType   = "outputType"
+	KeyGroupID      = "groupID"
+	KeyGroupByChain = "groupByChain"
+
+	KeyVolumeExcludeOutputType = "volumeExcludeOutputType"
+	KeySortByBalance           = "sortByBalance"
+	KeyMinHeight               = "minHeight"
+	KeyIntervalOffset          = "intervalOffset"
+	KeyIntervalLimit           = "intervalLimit"
+	KeyIDPrefix                = "idPrefix"
+	KeyIntervalAlignment       = "intervalAlignment"
+	KeyRecipientAddress        = "recipientAddress"
+
+	KeyIncludeSerialization = "includeSerialization"
+	KeyQueryMode            = "queryMode"
+	KeyLight                = "light"
+	KeyIncludeAssets        = "includeAssets"
+	KeyIncludeRedeemedAt    = "includeRedeemedAt"
+	KeyIncludeAddresses     = "includeAddresses"
+	KeyDecodePayloads       = "decodePayloads"
+	KeyMaxLimit             = "maxLimit"
+	KeyMinAmount            = "minAmount"
+	KeyMaxAmount            = "maxAmount"
 
 	PaginationMaxLimit      = 500
 	PaginationDefaultLimit  = 500
 	PaginationDefaultOffset = 0
+
+	// PaginationHardMaxLimit bounds the MaxLimit internal callers may set on
+	// ListParams to raise the limit above PaginationMaxLimit
+	PaginationHardMaxLimit = 5000
+
+	// MaxMemoLength is the size of the memo column
+	MaxMemoLength = 1024
 )
 
+// defaultLimit is the limit ListParams.ForValues uses when the query values
+// don't set one
+var defaultLimit = PaginationDefaultLimit
+
+// SetDefaultLimit sets the package-wide limit used when the query values given
+// to ListParams.ForValues don't set one. It's bounded by PaginationMaxLimit,
+// and a limit < 1 restores PaginationDefaultLimit. Only query values are
+// affected; a zero Limit set directly in Go still means unlimited.
+func SetDefaultLimit(limit int) {
+	switch {
+	case limit < 1:
+		defaultLimit = PaginationDefaultLimit
+	case limit > PaginationMaxLimit:
+		defaultLimit = PaginationMaxLimit
+	default:
+		defaultLimit = limit
+	}
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 var (
 	IntervalMinute = 1 * time.Minute
 	IntervalHour   = 60 * time.Minute
@@ -50,7 +109,24 @@ var (
 		"all":    IntervalAll,
 	}
 
-	ErrUndefinedSort = errors.New("undefined sort")
+	ErrUndefinedSort      = errors.New("undefined sort")
+	ErrUndefinedQueryMode = errors.New("undefined query mode")
+	ErrInvalidCursor      = errors.New("invalid cursor")
+	ErrCursorWithSort     = errors.New("cursor pagination only supports the default sort")
+	ErrMinHeightWithSort  = errors.New("minHeight only supports the default sort")
+
+	ErrMinBalanceWithoutAsset    = errors.New("minBalance requires an assetID")
+	ErrSortByBalanceWithoutAsset = errors.New("sortByBalance requires an assetID")
+	ErrUndefinedOutputType       = errors.New("undefined output type")
+	ErrInvalidAmountRange        = errors.New("minAmount is greater than maxAmount")
+
+	ErrInvalidTimeRange      = errors.New("end time is before start time")
+	ErrInvalidIntervalSize   = errors.New("interval size is negative")
+	ErrIntervalSizeTooLarge  = errors.New("interval size is larger than the time range")
+	ErrInvalidIntervalWindow = errors.New("interval offset or limit is negative")
+
+	ErrUndefinedIntervalAlignment = errors.New("undefined interval alignment")
+	ErrIntervalRangeNotAligned    = errors.New("time range isn't a multiple of the interval size")
 
 	// Ensure params types satisfy the interface
 	_ Param = &ListParams{}
@@ -65,6 +141,12 @@ func CacheKey(name string, val interface{}) string {
 	return fmt.Sprintf("%s=%v", name, val)
 }
 
+// escapeLike escapes the wildcards in s so that it's matched literally in a
+// LIKE pattern
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
 func RoundTime(t time.Time, precision time.Duration) time.Time {
 	ts := t.Unix()
 	ts -= (ts % int64(precision.Seconds()))
@@ -78,10 +160,23 @@ type ListParams struct {
 	Limit           int
 	Offset          int
 	DisableCounting bool
+
+	// CountOnly only counts the matching rows without loading any of them. It
+	// takes precedence over DisableCounting.
+	CountOnly bool
+
+	// ExactCount counts the matching rows even when the Reader would serve an
+	// approximate count from its cache
+	ExactCount bool
+
+	// MaxLimit overrides PaginationMaxLimit for internal callers that need
+	// larger pages than the public API allows. It's never read from query
+	// values, and it's bounded by PaginationHardMaxLimit. Zero uses the default.
+	MaxLimit int
 }
 
 func (p *ListParams) ForValues(q url.Values) (err error) {
-	p.Limit, err = GetQueryInt(q, KeyLimit, PaginationDefaultLimit)
+	p.Limit, err = GetQueryInt(q, KeyLimit, defaultLimit)
 	if err != nil {
 		return err
 	}
@@ -93,6 +188,14 @@ func (p *ListParams) ForValues(q url.Values) (err error) {
 	if err != nil {
 		return err
 	}
+	p.CountOnly, err = GetQueryBool(q, KeyCountOnly, false)
+	if err != nil {
+		return err
+	}
+	p.ExactCount, err = GetQueryBool(q, KeyExactCount, false)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -103,18 +206,91 @@ func (p *ListParams) CacheKey() []string {
 
 		// inject the DisableCount to the key..  Makes sure cache hits will return answer matching request
 		CacheKey(KeyDisableCount, p.DisableCounting),
+		CacheKey(KeyCountOnly, p.CountOnly),
+		CacheKey(KeyExactCount, p.ExactCount),
+		CacheKey(KeyMaxLimit, p.MaxLimit),
 	}
 }
 
-func (p ListParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
-	if p.Limit > PaginationMaxLimit {
-		p.Limit = PaginationMaxLimit
+// EffectiveMaxLimit returns the largest limit that will be applied to the
+// query, which is MaxLimit if set or else PaginationMaxLimit
+func (p ListParams) EffectiveMaxLimit() int {
+	switch {
+	case p.MaxLimit <= 0:
+		return PaginationMaxLimit
+	case p.MaxLimit > PaginationHardMaxLimit:
+		return PaginationHardMaxLimit
+	default:
+		return p.MaxLimit
+	}
+}
+
+// EffectiveLimit returns the limit that will be applied to the query, or 0 if
+// the query is unlimited
+func (p ListParams) EffectiveLimit() int {
+	if maxLimit := p.EffectiveMaxLimit(); p.Limit > maxLimit {
+		return maxLimit
 	}
-	if p.Limit != 0 {
-		b.Limit(uint64(p.Limit))
+	return p.Limit
+}
+
+func (p ListParams) Apply(b *dbr.SelectBuilder) *dbr.SelectBuilder {
+	if limit := p.EffectiveLimit(); limit != 0 {
+		b.Limit(uint64(limit))
 	}
 	if p.Offset != 0 {
 		b.Offset(uint64(p.Offset))
 	}
 	return b
 }
+
+// Cursor is a stable position in a list ordered by (created_at, id). Unlike
+// an offset it is not affected by rows inserted between page fetches.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// ParseCursor parses a cursor previously created by Cursor.String. An empty
+// string results in the zero Cursor, which points to the start of the list.
+func ParseCursor(s string) (Cursor, error) {
+	if s == "" {
+		return Cursor{}, nil
+	}
+
+	parts := strings.SplitN(s, "_", 2)
+	if len(parts) != 2 {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	ts, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	if _, err = ids.FromString(parts[1]); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return Cursor{CreatedAt: time.Unix(ts, 0).UTC(), ID: parts[1]}, nil
+}
+
+// IsZero returns true if the cursor points to the start of the list
+func (c Cursor) IsZero() bool { return c.ID == "" }
+
+// String encodes the cursor into an opaque token to be passed back by clients
+func (c Cursor) String() string {
+	if c.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%d_%s", c.CreatedAt.Unix(), c.ID)
+}
+
+// Apply restricts the query to rows strictly after the cursor position for the
+// given table
+func (c Cursor) Apply(b *dbr.SelectBuilder, table string) *dbr.SelectBuilder {
+	if c.IsZero() {
+		return b
+	}
+	return b.Where("("+table+".created_at, "+table+".id) > (?, ?)", c.CreatedAt, c.ID)
+}
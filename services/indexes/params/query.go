@@ -4,15 +4,33 @@
 package params
 
 import (
+	"errors"
+	"fmt"
+	"math/big"
 	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 )
 
+// ErrInvalidAddress is returned for strings formatted like bech32 addresses
+// whose checksum doesn't match, such as mistyped addresses
+var ErrInvalidAddress = errors.New("invalid address")
+
+const (
+	// bech32Charset holds the characters the data of bech32 strings is encoded
+	// with
+	bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	// bech32AddressDataLength is the length of the data of a bech32 address,
+	// which is 20 bytes in 5 bit characters and a 6 character checksum
+	bech32AddressDataLength = 38
+)
+
 func GetQueryInt(q url.Values, key string, defaultVal int) (val int, err error) {
 	strs := q[key]
 	if len(strs) >= 1 {
@@ -37,6 +55,46 @@ func GetQueryString(q url.Values, key string, defaultVal string) string {
 	return defaultVal
 }
 
+func GetQueryBigInt(q url.Values, key string) (*big.Int, error) {
+	str := GetQueryString(q, key, "")
+	if str == "" {
+		return nil, nil
+	}
+
+	i, ok := new(big.Int).SetString(str, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer for %s: %s", key, str)
+	}
+	return i, nil
+}
+
+func GetQueryUint32(q url.Values, key string) (*uint32, error) {
+	str := GetQueryString(q, key, "")
+	if str == "" {
+		return nil, nil
+	}
+
+	i, err := strconv.ParseUint(str, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid integer for %s: %s", key, str)
+	}
+	u := uint32(i)
+	return &u, nil
+}
+
+func GetQueryUint64(q url.Values, key string) (*uint64, error) {
+	str := GetQueryString(q, key, "")
+	if str == "" {
+		return nil, nil
+	}
+
+	i, err := strconv.ParseUint(str, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid integer for %s: %s", key, str)
+	}
+	return &i, nil
+}
+
 func GetQueryTime(q url.Values, key string) (time.Time, error) {
 	strs, ok := q[key]
 	if !ok || len(strs) < 1 {
@@ -125,8 +183,36 @@ func AddressFromString(addrStr string) (ids.ShortID, error) {
 		if err == nil {
 			return addrFromShortIDStr, nil
 		}
+		if isBech32AddressLike(addrStr) {
+			return ids.ShortEmpty, ErrInvalidAddress
+		}
 		return ids.ShortEmpty, err
 	}
 
 	return ids.ToShortID(addrBytes)
 }
+
+// isBech32AddressLike returns true if addrStr, without its chain prefix, has
+// the HRP of a network and data of an address's length in the bech32 charset,
+// whether or not its checksum is valid
+func isBech32AddressLike(addrStr string) bool {
+	addrStr = strings.ToLower(addrStr)
+	sep := strings.LastIndexByte(addrStr, '1')
+	if sep < 0 {
+		return false
+	}
+
+	hrp, data := addrStr[:sep], addrStr[sep+1:]
+	if _, ok := constants.NetworkHRPToNetworkID[hrp]; !ok && hrp != constants.FallbackHRP {
+		return false
+	}
+	if len(data) != bech32AddressDataLength {
+		return false
+	}
+	for _, c := range data {
+		if !strings.ContainsRune(bech32Charset, c) {
+			return false
+		}
+	}
+	return true
+}
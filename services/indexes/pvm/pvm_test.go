@@ -2,15 +2,21 @@ package pvm
 
 import (
 	"context"
+	"math"
 	"testing"
 
 	"github.com/alicebob/miniredis"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/core"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 
 	"github.com/ava-labs/ortelius/cfg"
 	"github.com/ava-labs/ortelius/services"
 	"github.com/ava-labs/ortelius/services/indexes/avm"
+	"github.com/ava-labs/ortelius/services/indexes/models"
 	"github.com/ava-labs/ortelius/services/indexes/params"
 )
 
@@ -34,6 +40,97 @@ func TestBootstrap(t *testing.T) {
 	}
 }
 
+func TestConsumePartial(t *testing.T) {
+	w, r, closeFn := newTestIndex(t, 12345, ChainID)
+	defer closeFn()
+
+	ctx := context.Background()
+	goodTx := newTestCreateSubnetTx(t, 1, 100)
+
+	// The inputs of the poison tx overflow when summed, so it can't be indexed
+	poisonTx := newTestCreateSubnetTx(t, 2, math.MaxUint64, math.MaxUint64)
+
+	blk := &platformvm.StandardBlock{Txs: []*platformvm.Tx{goodTx, poisonTx}}
+	blk.Block = &core.Block{PrntID: ids.Empty, Hght: 1}
+	var block platformvm.Block = blk
+	blockBytes, err := w.codec.Marshal(&block)
+	if err != nil {
+		t.Fatal("Failed to marshal block:", err.Error())
+	}
+	msg := testConsumable{body: blockBytes, height: 7}
+
+	listTxIDs := func() []models.StringID {
+		txList, err := r.ListTransactions(ctx, &params.ListTransactionsParams{ChainIDs: []string{ChainID.String()}})
+		if err != nil {
+			t.Fatal("Failed to list transactions:", err.Error())
+		}
+		txIDs := make([]models.StringID, len(txList.Transactions))
+		for i, tx := range txList.Transactions {
+			txIDs[i] = tx.ID
+		}
+		return txIDs
+	}
+
+	// Consuming the whole block fails and indexes nothing
+	if err = w.Consume(ctx, msg); err == nil {
+		t.Fatal("Expected consuming the block to fail")
+	}
+	if txIDs := listTxIDs(); len(txIDs) != 0 {
+		t.Fatal("Expected no transactions to be indexed:", txIDs)
+	}
+
+	// Consuming it partially indexes the good tx and reports the poison tx
+	result, err := w.ConsumePartial(ctx, msg)
+	if err != nil {
+		t.Fatal("Failed to consume block partially:", err.Error())
+	}
+	if len(result.Failures) != 1 || result.Failures[0].TxID != poisonTx.ID().String() || result.Failures[0].Err == nil {
+		t.Fatal("Incorrect failures:", result.Failures)
+	}
+	if txIDs := listTxIDs(); len(txIDs) != 1 || txIDs[0] != models.StringID(goodTx.ID().String()) {
+		t.Fatal("Expected only the good transaction to be indexed:", txIDs)
+	}
+
+	// The tx is indexed at the height of the consumed block
+	tx, err := r.GetTransaction(ctx, goodTx.ID())
+	if err != nil {
+		t.Fatal("Failed to get transaction:", err.Error())
+	}
+	if tx.Height != 7 {
+		t.Fatal("Incorrect transaction height:", tx.Height)
+	}
+}
+
+// newTestCreateSubnetTx returns a tx spending inputs of the given amounts
+func newTestCreateSubnetTx(t *testing.T, seed byte, amounts ...uint64) *platformvm.Tx {
+	unsignedTx := &platformvm.UnsignedCreateSubnetTx{Owner: &secp256k1fx.OutputOwners{}}
+	unsignedTx.BlockchainID = ids.Empty
+	tx := &platformvm.Tx{UnsignedTx: unsignedTx}
+	for i, amount := range amounts {
+		unsignedTx.Ins = append(unsignedTx.Ins, &avax.TransferableInput{
+			UTXOID: avax.UTXOID{TxID: ids.NewID([32]byte{seed}), OutputIndex: uint32(i)},
+			Asset:  avax.Asset{ID: ids.NewID([32]byte{100})},
+			In:     &secp256k1fx.TransferInput{Amt: amount},
+		})
+		tx.Creds = append(tx.Creds, &secp256k1fx.Credential{})
+	}
+	if err := initializeTx(platformvm.Codec, tx); err != nil {
+		t.Fatal("Failed to initialize tx:", err.Error())
+	}
+	return tx
+}
+
+type testConsumable struct {
+	body   []byte
+	height uint64
+}
+
+func (testConsumable) ID() string       { return "1" }
+func (testConsumable) ChainID() string  { return ChainID.String() }
+func (c testConsumable) Body() []byte   { return c.body }
+func (testConsumable) Timestamp() int64 { return 1 }
+func (c testConsumable) Height() uint64 { return c.height }
+
 func newTestIndex(t *testing.T, networkID uint32, chainID ids.ID) (*Writer, *avm.Reader, func()) {
 	// Start test redis
 	s, err := miniredis.Run()
@@ -16,8 +16,11 @@ import (
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/platformvm"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/health"
 
 	"github.com/ava-labs/ortelius/services"
+	"github.com/ava-labs/ortelius/services/db"
 	avaxIndexer "github.com/ava-labs/ortelius/services/indexes/avax"
 	"github.com/ava-labs/ortelius/services/indexes/models"
 )
@@ -49,23 +52,76 @@ func NewWriter(conns *services.Connections, networkID uint32, chainID string) (*
 
 func (*Writer) Name() string { return "pvm-index" }
 
+// Consume indexes the block and all of its transactions in a single DB
+// transaction, so nothing is indexed if any of them fails
 func (w *Writer) Consume(ctx context.Context, c services.Consumable) error {
 	job := w.conns.Stream().NewJob("index")
 	sess := w.conns.DB().NewSessionForEventReceiver(job)
 
-	// Create w tx
+	return w.inDBTx(ctx, job, sess, c, func(cCtx services.ConsumerCtx) error {
+		return w.indexBlock(cCtx, c.Body())
+	})
+}
+
+// ConsumePartial indexes the block and then each of its transactions in its
+// own DB transaction, reporting the transactions that failed instead of
+// failing the block. Transient DB errors fail the whole block so that it can
+// be retried.
+func (w *Writer) ConsumePartial(ctx context.Context, c services.Consumable) (*services.ConsumeResult, error) {
+	job := w.conns.Stream().NewJob("index_partial")
+	sess := w.conns.DB().NewSessionForEventReceiver(job)
+
+	result := &services.ConsumeResult{}
+	blkType, blk, txs, err := w.parseBlock(c.Body())
+	if err == ErrUnknownBlockType {
+		_ = job.EventErr("index_block", err)
+		return result, nil
+	}
+	if err != nil {
+		return nil, job.EventErr("index_block.unmarshal_block", err)
+	}
+
+	err = w.inDBTx(ctx, job, sess, c, func(cCtx services.ConsumerCtx) error {
+		return w.indexCommonBlock(cCtx, blkType, blk, c.Body())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tx := range txs {
+		err = w.inDBTx(ctx, job, sess, c, func(cCtx services.ConsumerCtx) error {
+			return w.indexBlockTransaction(cCtx, blk.ID(), tx)
+		})
+		if db.ErrIsRetryable(err) {
+			return nil, err
+		}
+		if err != nil {
+			result.Failures = append(result.Failures, services.TxFailure{TxID: tx.ID().String(), Err: err})
+		}
+	}
+	return result, nil
+}
+
+// inDBTx calls fn with a context for consuming c in a new DB transaction,
+// which is committed if fn succeeds and rolled back otherwise. Aggregates
+// cached for the time of committed writes are invalidated.
+func (w *Writer) inDBTx(ctx context.Context, job *health.Job, sess *dbr.Session, c services.Consumable, fn func(services.ConsumerCtx) error) error {
 	dbTx, err := sess.Begin()
 	if err != nil {
 		return err
 	}
 	defer dbTx.RollbackUnlessCommitted()
 
-	// Consume the tx and commit
-	err = w.indexBlock(services.NewConsumerContext(ctx, job, dbTx, c.Timestamp()), c.Body())
-	if err != nil {
+	cCtx := services.NewConsumableContext(ctx, job, dbTx, c)
+	if err = fn(cCtx); err != nil {
 		return err
 	}
-	return dbTx.Commit()
+	if err = dbTx.Commit(); err != nil {
+		return err
+	}
+
+	avaxIndexer.InvalidateAggregates(ctx, w.conns, job, w.chainID, cCtx.Time())
+	return nil
 }
 
 func (w *Writer) Bootstrap(ctx context.Context) error {
@@ -117,12 +173,12 @@ func (w *Writer) Bootstrap(ctx context.Context) error {
 	return errs.Err
 }
 
-func initializeTx(c codec.Codec, tx platformvm.Tx) error {
+func initializeTx(c codec.Codec, tx *platformvm.Tx) error {
 	unsignedBytes, err := c.Marshal(&tx.UnsignedTx)
 	if err != nil {
 		return err
 	}
-	signedBytes, err := c.Marshal(&tx)
+	signedBytes, err := c.Marshal(tx)
 	if err != nil {
 		return err
 	}
@@ -131,42 +187,52 @@ func initializeTx(c codec.Codec, tx platformvm.Tx) error {
 }
 
 func (w *Writer) indexBlock(ctx services.ConsumerCtx, blockBytes []byte) error {
-	var block platformvm.Block
-	if err := w.codec.Unmarshal(blockBytes, &block); err != nil {
+	blkType, blk, txs, err := w.parseBlock(blockBytes)
+	if err == ErrUnknownBlockType {
+		_ = ctx.Job().EventErr("index_block", err)
+		return nil
+	}
+	if err != nil {
 		return ctx.Job().EventErr("index_block.unmarshal_block", err)
 	}
 
 	errs := wrappers.Errs{}
+	errs.Add(w.indexCommonBlock(ctx, blkType, blk, blockBytes))
+	for _, tx := range txs {
+		errs.Add(w.indexBlockTransaction(ctx, blk.ID(), tx))
+	}
+	return errs.Err
+}
+
+// parseBlock returns the type of the block, its common fields, and its
+// transactions. ErrUnknownBlockType is returned for blocks of other types.
+func (w *Writer) parseBlock(blockBytes []byte) (models.BlockType, platformvm.CommonBlock, []*platformvm.Tx, error) {
+	var block platformvm.Block
+	if err := w.codec.Unmarshal(blockBytes, &block); err != nil {
+		return 0, platformvm.CommonBlock{}, nil, err
+	}
 
 	switch blk := block.(type) {
 	case *platformvm.ProposalBlock:
-		errs.Add(
-			initializeTx(w.codec, blk.Tx),
-			w.indexCommonBlock(ctx, models.BlockTypeProposal, blk.CommonBlock, blockBytes),
-			w.indexTransaction(ctx, blk.ID(), blk.Tx),
-		)
+		return models.BlockTypeProposal, blk.CommonBlock, []*platformvm.Tx{&blk.Tx}, nil
 	case *platformvm.StandardBlock:
-		errs.Add(w.indexCommonBlock(ctx, models.BlockTypeStandard, blk.CommonBlock, blockBytes))
-		for _, tx := range blk.Txs {
-			errs.Add(
-				initializeTx(w.codec, *tx),
-				w.indexTransaction(ctx, blk.ID(), *tx),
-			)
-		}
+		return models.BlockTypeStandard, blk.CommonBlock, blk.Txs, nil
 	case *platformvm.AtomicBlock:
-		errs.Add(
-			initializeTx(w.codec, blk.Tx),
-			w.indexCommonBlock(ctx, models.BlockTypeProposal, blk.CommonBlock, blockBytes),
-			w.indexTransaction(ctx, blk.ID(), blk.Tx),
-		)
+		return models.BlockTypeProposal, blk.CommonBlock, []*platformvm.Tx{&blk.Tx}, nil
 	case *platformvm.Abort:
-		errs.Add(w.indexCommonBlock(ctx, models.BlockTypeAbort, blk.CommonBlock, blockBytes))
+		return models.BlockTypeAbort, blk.CommonBlock, nil, nil
 	case *platformvm.Commit:
-		errs.Add(w.indexCommonBlock(ctx, models.BlockTypeCommit, blk.CommonBlock, blockBytes))
-	default:
-		ctx.Job().EventErr("index_block", ErrUnknownBlockType)
+		return models.BlockTypeCommit, blk.CommonBlock, nil, nil
 	}
-	return nil
+	return 0, platformvm.CommonBlock{}, nil, ErrUnknownBlockType
+}
+
+// indexBlockTransaction initializes a transaction of a block and indexes it
+func (w *Writer) indexBlockTransaction(ctx services.ConsumerCtx, blockID ids.ID, tx *platformvm.Tx) error {
+	if err := initializeTx(w.codec, tx); err != nil {
+		return err
+	}
+	return w.indexTransaction(ctx, blockID, *tx)
 }
 
 func (w *Writer) indexCommonBlock(ctx services.ConsumerCtx, blkType models.BlockType, blk platformvm.CommonBlock, blockBytes []byte) error {
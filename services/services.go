@@ -15,6 +15,13 @@ type Consumable interface {
 	Timestamp() int64
 }
 
+// OrderedConsumable is a Consumable that knows its height, its position in
+// the order its chain accepted it in
+type OrderedConsumable interface {
+	Consumable
+	Height() uint64
+}
+
 // Consumer takes in Consumables and adds them to the service's backend
 type Consumer interface {
 	Name() string
@@ -22,13 +29,39 @@ type Consumer interface {
 	Consume(context.Context, Consumable) error
 }
 
+// TxFailure is a transaction of a Consumable that couldn't be indexed
+type TxFailure struct {
+	TxID string
+	Err  error
+}
+
+// ConsumeResult reports the transactions of a Consumable that failed to be
+// indexed by a PartialConsumer. The rest of its transactions were indexed.
+type ConsumeResult struct {
+	Failures []TxFailure
+}
+
+// PartialConsumer is a Consumer that can index the transactions of a
+// Consumable individually, so that one bad transaction doesn't stop the rest
+// from being indexed. Consume still indexes all of them or none.
+type PartialConsumer interface {
+	Consumer
+
+	// ConsumePartial indexes each transaction of the Consumable on its own
+	// and reports those that failed. An error is returned only if nothing
+	// could be indexed, or if a failure was transient and the Consumable
+	// should be retried.
+	ConsumePartial(context.Context, Consumable) (*ConsumeResult, error)
+}
+
 // ConsumerCtx
 type ConsumerCtx struct {
 	ctx context.Context
 	job *health.Job
 	db  dbr.SessionRunner
 
-	time time.Time
+	time   time.Time
+	height uint64
 }
 
 func NewConsumerContext(ctx context.Context, job *health.Job, db dbr.SessionRunner, ts int64) ConsumerCtx {
@@ -40,7 +73,25 @@ func NewConsumerContext(ctx context.Context, job *health.Job, db dbr.SessionRunn
 	}
 }
 
+// NewConsumableContext returns a context for consuming c, with its height if
+// it's an OrderedConsumable
+func NewConsumableContext(ctx context.Context, job *health.Job, db dbr.SessionRunner, c Consumable) ConsumerCtx {
+	cCtx := NewConsumerContext(ctx, job, db, c.Timestamp())
+	if ordered, ok := c.(OrderedConsumable); ok {
+		cCtx = cCtx.WithHeight(ordered.Height())
+	}
+	return cCtx
+}
+
 func (ic ConsumerCtx) Time() time.Time       { return ic.time }
+func (ic ConsumerCtx) Height() uint64        { return ic.height }
 func (ic ConsumerCtx) Job() *health.Job      { return ic.job }
 func (ic ConsumerCtx) DB() dbr.SessionRunner { return ic.db }
 func (ic ConsumerCtx) Ctx() context.Context  { return ic.ctx }
+
+// WithHeight returns a copy of the context for a Consumable with the given
+// height. Contexts without one have a height of 0.
+func (ic ConsumerCtx) WithHeight(height uint64) ConsumerCtx {
+	ic.height = height
+	return ic
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/segmentio/kafka-go"
 
 	"github.com/ava-labs/ortelius/cfg"
@@ -28,23 +29,77 @@ type serviceConsumerFactory func(*services.Connections, uint32, string, string)
 
 // consumer takes events from Kafka and sends them to a service consumer
 type consumer struct {
+	chainVM  string
 	chainID  string
 	reader   *kafka.Reader
 	consumer services.Consumer
 	conns    *services.Connections
+	metrics  *consumerMetrics
+	retry    RetryPolicy
+
+	failedTxHandler FailedTxHandler
+}
+
+// consumerConfig holds the settings of the consumers created by a factory
+type consumerConfig struct {
+	registerer      prometheus.Registerer
+	retryPolicy     RetryPolicy
+	failedTxHandler FailedTxHandler
+}
+
+// ConsumerOption configures the consumers created by NewConsumerFactory
+type ConsumerOption func(*consumerConfig)
+
+// WithMetrics registers consumer metrics with registerer. Consumers don't
+// record metrics by default.
+func WithMetrics(registerer prometheus.Registerer) ConsumerOption {
+	return func(c *consumerConfig) { c.registerer = registerer }
+}
+
+// WithRetryPolicy sets the policy for retrying messages that failed because of
+// a transient DB error. DefaultRetryPolicy is used by default.
+func WithRetryPolicy(policy RetryPolicy) ConsumerOption {
+	return func(c *consumerConfig) { c.retryPolicy = policy }
+}
+
+// FailedTxHandler is given the transactions of a message that couldn't be
+// indexed, such as to dead-letter them. Returning an error fails the message.
+type FailedTxHandler func(ctx context.Context, msg services.Consumable, failures []services.TxFailure) error
+
+// WithFailedTxHandler makes consumers whose service consumer is a
+// services.PartialConsumer index the transactions of each message
+// individually, passing those that failed to handler instead of failing the
+// whole message. By default a message is indexed entirely or not at all.
+func WithFailedTxHandler(handler FailedTxHandler) ConsumerOption {
+	return func(c *consumerConfig) { c.failedTxHandler = handler }
 }
 
 // NewConsumerFactory returns a processorFactory for the given service consumer
-func NewConsumerFactory(factory serviceConsumerFactory) ProcessorFactory {
+func NewConsumerFactory(factory serviceConsumerFactory, opts ...ConsumerOption) ProcessorFactory {
+	config := consumerConfig{retryPolicy: DefaultRetryPolicy}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
 	return func(conf cfg.Config, chainVM string, chainID string) (Processor, error) {
+		metrics, err := newConsumerMetrics(config.registerer)
+		if err != nil {
+			return nil, err
+		}
+
 		conns, err := services.NewConnectionsFromConfig(conf.Services)
 		if err != nil {
 			return nil, err
 		}
 
 		c := &consumer{
+			chainVM: chainVM,
 			chainID: chainID,
 			conns:   conns,
+			metrics: metrics,
+			retry:   config.retryPolicy,
+
+			failedTxHandler: config.failedTxHandler,
 		}
 
 		// Create consumer backend
@@ -107,13 +162,43 @@ func (c *consumer) ProcessNextMessage(ctx context.Context, log logging.Logger) e
 		return err
 	}
 
-	if err = c.consumer.Consume(ctx, msg); err != nil {
+	if err = c.consume(ctx, msg); err != nil {
 		log.Error("consumer.Consume: %s", err.Error())
 		return err
 	}
 	return nil
 }
 
+// consume sends the Message to the service consumer, retrying transient
+// failures, and records its metrics
+func (c *consumer) consume(ctx context.Context, msg *Message) error {
+	start := time.Now()
+	err := c.retry.Do(ctx, func() error {
+		return c.consumeOnce(ctx, msg)
+	})
+	c.metrics.observe(c.chainVM, c.chainID, msg.Timestamp(), time.Since(start), err)
+	return err
+}
+
+// consumeOnce sends the Message to the service consumer, indexing its
+// transactions individually if there's a FailedTxHandler and the service
+// consumer supports it
+func (c *consumer) consumeOnce(ctx context.Context, msg *Message) error {
+	partialConsumer, ok := c.consumer.(services.PartialConsumer)
+	if c.failedTxHandler == nil || !ok {
+		return c.consumer.Consume(ctx, msg)
+	}
+
+	result, err := partialConsumer.ConsumePartial(ctx, msg)
+	if err != nil {
+		return err
+	}
+	if len(result.Failures) == 0 {
+		return nil
+	}
+	return c.failedTxHandler(ctx, msg, result.Failures)
+}
+
 // getNextMessage gets the next Message from the Kafka Indexer
 func (c *consumer) getNextMessage(ctx context.Context) (*Message, error) {
 	// Get raw Message from Kafka
@@ -133,5 +218,6 @@ func (c *consumer) getNextMessage(ctx context.Context) (*Message, error) {
 		body:      msg.Value,
 		id:        id.String(),
 		timestamp: msg.Time.UTC().Unix(),
+		offset:    msg.Offset,
 	}, nil
 }
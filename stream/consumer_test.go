@@ -0,0 +1,62 @@
+// (c) 2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package stream
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/ortelius/services"
+)
+
+// testPartialConsumer fails a whole message with Consume but reports its
+// failures with ConsumePartial
+type testPartialConsumer struct {
+	testServiceConsumer
+	failures []services.TxFailure
+}
+
+func (c *testPartialConsumer) ConsumePartial(context.Context, services.Consumable) (*services.ConsumeResult, error) {
+	return &services.ConsumeResult{Failures: c.failures}, nil
+}
+
+func TestConsumeFailedTxHandler(t *testing.T) {
+	failures := []services.TxFailure{{TxID: "tx1", Err: errors.New("poison")}}
+	msg := &Message{id: "msg1"}
+
+	var handled []services.TxFailure
+	handler := func(_ context.Context, _ services.Consumable, f []services.TxFailure) error {
+		handled = append(handled, f...)
+		return nil
+	}
+
+	// Without a handler the message is consumed entirely or not at all
+	c := &consumer{consumer: &testPartialConsumer{testServiceConsumer{err: errors.New("failed")}, failures}}
+	if err := c.consume(context.Background(), msg); err == nil {
+		t.Fatal("Expected consume to fail without a handler")
+	}
+
+	// With one the failures are handled and the message succeeds
+	c.failedTxHandler = handler
+	if err := c.consume(context.Background(), msg); err != nil {
+		t.Fatal("Failed to consume partially:", err.Error())
+	}
+	if len(handled) != 1 || handled[0].TxID != "tx1" {
+		t.Fatal("Incorrect handled failures:", handled)
+	}
+
+	// Messages without failures aren't passed to the handler
+	handled = nil
+	c.consumer = &testPartialConsumer{}
+	if err := c.consume(context.Background(), msg); err != nil || len(handled) != 0 {
+		t.Fatal("Expected no failures to handle:", err, handled)
+	}
+
+	// Consumers that can't consume partially still consume entirely
+	c.consumer = &testServiceConsumer{err: errors.New("failed")}
+	if err := c.consume(context.Background(), msg); err == nil || len(handled) != 0 {
+		t.Fatal("Expected consume to fail for a consumer without partial support")
+	}
+}
@@ -4,20 +4,61 @@
 package consumers
 
 import (
+	"sync"
+
 	"github.com/ava-labs/ortelius/services"
 	"github.com/ava-labs/ortelius/services/indexes/avm"
+	"github.com/ava-labs/ortelius/services/indexes/cvm"
 	"github.com/ava-labs/ortelius/services/indexes/pvm"
 	"github.com/ava-labs/ortelius/stream"
 )
 
-var Indexer = stream.NewConsumerFactory(func(conns *services.Connections, networkID uint32, chainVM string, chainID string) (indexer services.Consumer, err error) {
-	switch chainVM {
-	case avm.VMName:
-		indexer, err = avm.NewWriter(conns, networkID, chainID)
-	case pvm.VMName:
-		indexer, err = pvm.NewWriter(conns, networkID, chainID)
-	default:
+var (
+	writerFactoriesMu = sync.Mutex{}
+	writerFactories   = map[string]WriterFactory{}
+)
+
+// WriterFactory creates the writer that indexes a chain of a VM
+type WriterFactory func(conns *services.Connections, networkID uint32, chainID string) (services.Consumer, error)
+
+// RegisterVMWriter adds a WriterFactory to the registry for chains of the named
+// VM, replacing any already registered for it. This lets custom or renamed VMs
+// be indexed by an existing writer.
+func RegisterVMWriter(name string, factory WriterFactory) {
+	writerFactoriesMu.Lock()
+	defer writerFactoriesMu.Unlock()
+	writerFactories[name] = factory
+}
+
+func init() {
+	RegisterVMWriter(avm.VMName, func(conns *services.Connections, networkID uint32, chainID string) (services.Consumer, error) {
+		return avm.NewWriter(conns, networkID, chainID)
+	})
+	RegisterVMWriter(pvm.VMName, func(conns *services.Connections, networkID uint32, chainID string) (services.Consumer, error) {
+		return pvm.NewWriter(conns, networkID, chainID)
+	})
+	RegisterVMWriter(cvm.VMName, func(conns *services.Connections, networkID uint32, chainID string) (services.Consumer, error) {
+		return cvm.NewWriter(conns, networkID, chainID)
+	})
+}
+
+// Indexer creates indexers with the default consumer options
+var Indexer = NewIndexer()
+
+// NewIndexer returns a factory for indexers configured by opts
+func NewIndexer(opts ...stream.ConsumerOption) stream.ProcessorFactory {
+	return stream.NewConsumerFactory(newVMWriter, opts...)
+}
+
+// newVMWriter creates the writer registered for chainVM, or returns
+// stream.ErrUnknownVM if there isn't one
+func newVMWriter(conns *services.Connections, networkID uint32, chainVM string, chainID string) (services.Consumer, error) {
+	writerFactoriesMu.Lock()
+	factory, ok := writerFactories[chainVM]
+	writerFactoriesMu.Unlock()
+
+	if !ok {
 		return nil, stream.ErrUnknownVM
 	}
-	return indexer, err
-})
+	return factory(conns, networkID, chainID)
+}
@@ -0,0 +1,69 @@
+// (c) 2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package consumers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/ortelius/services"
+	"github.com/ava-labs/ortelius/services/indexes/avm"
+	"github.com/ava-labs/ortelius/services/indexes/cvm"
+	"github.com/ava-labs/ortelius/services/indexes/pvm"
+	"github.com/ava-labs/ortelius/stream"
+)
+
+// fakeWriter records the messages it consumes
+type fakeWriter struct {
+	chainID  string
+	consumed []services.Consumable
+}
+
+func (*fakeWriter) Name() string                    { return "fake" }
+func (*fakeWriter) Bootstrap(context.Context) error { return nil }
+func (w *fakeWriter) Consume(_ context.Context, msg services.Consumable) error {
+	w.consumed = append(w.consumed, msg)
+	return nil
+}
+
+type fakeMessage struct{ chainID string }
+
+func (m fakeMessage) ID() string       { return "1" }
+func (m fakeMessage) ChainID() string  { return m.chainID }
+func (m fakeMessage) Body() []byte     { return []byte{} }
+func (m fakeMessage) Timestamp() int64 { return 0 }
+
+func TestRegisterVMWriter(t *testing.T) {
+	writer := &fakeWriter{}
+	RegisterVMWriter("fakevm", func(_ *services.Connections, _ uint32, chainID string) (services.Consumer, error) {
+		writer.chainID = chainID
+		return writer, nil
+	})
+
+	consumer, err := newVMWriter(nil, 5, "fakevm", "chain1")
+	if err != nil {
+		t.Fatal("Failed to create writer:", err.Error())
+	}
+	if consumer != writer || writer.chainID != "chain1" {
+		t.Fatal("Incorrect writer for the registered VM")
+	}
+
+	msg := fakeMessage{chainID: "chain1"}
+	if err = consumer.Consume(context.Background(), msg); err != nil {
+		t.Fatal("Failed to consume:", err.Error())
+	}
+	if len(writer.consumed) != 1 || writer.consumed[0] != msg {
+		t.Fatal("Message not routed to the registered writer")
+	}
+
+	// The built-in VMs are registered, and unregistered VMs are still unknown
+	for _, name := range []string{avm.VMName, pvm.VMName, cvm.VMName} {
+		if _, ok := writerFactories[name]; !ok {
+			t.Fatal("Built-in VM not registered:", name)
+		}
+	}
+	if _, err = newVMWriter(nil, 5, "unknownvm", "chain1"); err != stream.ErrUnknownVM {
+		t.Fatal("Expected ErrUnknownVM, got:", err)
+	}
+}
@@ -0,0 +1,114 @@
+// (c) 2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package stream
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricsNamespace = "ortelius"
+	metricsSubsystem = "consumer"
+
+	metricsLabelVM      = "vm"
+	metricsLabelChainID = "chain_id"
+)
+
+// consumerMetrics tracks the messages handled by consumers, labeled by VM and
+// chain so that a single lagging or failing chain can be found
+type consumerMetrics struct {
+	consumed      *prometheus.CounterVec
+	errors        *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	lastTimestamp *prometheus.GaugeVec
+}
+
+// newConsumerMetrics creates the consumer metrics and registers them with
+// registerer. Metrics are shared by every consumer using the same registerer.
+// A nil registerer results in nil metrics, which record nothing.
+func newConsumerMetrics(registerer prometheus.Registerer) (*consumerMetrics, error) {
+	if registerer == nil {
+		return nil, nil
+	}
+
+	labels := []string{metricsLabelVM, metricsLabelChainID}
+	m := &consumerMetrics{
+		consumed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "messages_consumed_total",
+			Help:      "Number of messages successfully consumed",
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "errors_total",
+			Help:      "Number of messages that failed to be consumed",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "consume_duration_seconds",
+			Help:      "Time taken to consume a message",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+		lastTimestamp: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "last_message_timestamp_seconds",
+			Help:      "Timestamp of the last message successfully consumed",
+		}, labels),
+	}
+
+	consumed, err := register(registerer, m.consumed)
+	if err != nil {
+		return nil, err
+	}
+	errs, err := register(registerer, m.errors)
+	if err != nil {
+		return nil, err
+	}
+	duration, err := register(registerer, m.duration)
+	if err != nil {
+		return nil, err
+	}
+	lastTimestamp, err := register(registerer, m.lastTimestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	m.consumed = consumed.(*prometheus.CounterVec)
+	m.errors = errs.(*prometheus.CounterVec)
+	m.duration = duration.(*prometheus.HistogramVec)
+	m.lastTimestamp = lastTimestamp.(*prometheus.GaugeVec)
+	return m, nil
+}
+
+// register registers c, or returns the collector already registered in its
+// place so that multiple consumers can share it
+func register(registerer prometheus.Registerer, c prometheus.Collector) (prometheus.Collector, error) {
+	err := registerer.Register(c)
+	if existing, ok := err.(prometheus.AlreadyRegisteredError); ok {
+		return existing.ExistingCollector, nil
+	}
+	return c, err
+}
+
+// observe records the outcome of consuming a message with the given timestamp
+func (m *consumerMetrics) observe(vm string, chainID string, msgTimestamp int64, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+
+	labels := prometheus.Labels{metricsLabelVM: vm, metricsLabelChainID: chainID}
+	m.duration.With(labels).Observe(duration.Seconds())
+	if err != nil {
+		m.errors.With(labels).Inc()
+		return
+	}
+	m.consumed.With(labels).Inc()
+	m.lastTimestamp.With(labels).Set(float64(msgTimestamp))
+}
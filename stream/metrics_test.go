@@ -0,0 +1,81 @@
+// (c) 2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package stream
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/ava-labs/ortelius/services"
+)
+
+type testServiceConsumer struct{ err error }
+
+func (*testServiceConsumer) Name() string                                         { return "test" }
+func (*testServiceConsumer) Bootstrap(context.Context) error                      { return nil }
+func (c *testServiceConsumer) Consume(context.Context, services.Consumable) error { return c.err }
+
+func TestConsumerMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	// Consumers for two chains share the registry
+	newTestConsumer := func(chainID string, consumeErr error) *consumer {
+		metrics, err := newConsumerMetrics(registry)
+		if err != nil {
+			t.Fatal("Failed to create metrics:", err.Error())
+		}
+		return &consumer{chainVM: "avm", chainID: chainID, consumer: &testServiceConsumer{err: consumeErr}, metrics: metrics}
+	}
+	okConsumer := newTestConsumer("chain1", nil)
+	failingConsumer := newTestConsumer("chain2", errors.New("failed"))
+
+	for i := int64(1); i <= 2; i++ {
+		if err := okConsumer.consume(context.Background(), &Message{chainID: "chain1", timestamp: 100 * i}); err != nil {
+			t.Fatal("Failed to consume:", err.Error())
+		}
+	}
+	if err := failingConsumer.consume(context.Background(), &Message{chainID: "chain2", timestamp: 300}); err == nil {
+		t.Fatal("Expected consume to fail")
+	}
+
+	// Scrape the registry the same way Prometheus would
+	w := httptest.NewRecorder()
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := ioutil.ReadAll(w.Body)
+	if err != nil {
+		t.Fatal("Failed to read metrics:", err.Error())
+	}
+
+	for _, expected := range []string{
+		`ortelius_consumer_messages_consumed_total{chain_id="chain1",vm="avm"} 2`,
+		`ortelius_consumer_errors_total{chain_id="chain2",vm="avm"} 1`,
+		`ortelius_consumer_last_message_timestamp_seconds{chain_id="chain1",vm="avm"} 200`,
+		`ortelius_consumer_consume_duration_seconds_count{chain_id="chain1",vm="avm"} 2`,
+		`ortelius_consumer_consume_duration_seconds_count{chain_id="chain2",vm="avm"} 1`,
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Fatalf("Missing metric %s in:\n%s", expected, body)
+		}
+	}
+	if strings.Contains(string(body), `ortelius_consumer_messages_consumed_total{chain_id="chain2"`) {
+		t.Fatal("Failed message counted as consumed")
+	}
+
+	// A nil registerer disables metrics
+	metrics, err := newConsumerMetrics(nil)
+	if err != nil || metrics != nil {
+		t.Fatal("Expected nil metrics without a registerer")
+	}
+	c := &consumer{consumer: &testServiceConsumer{}, metrics: metrics}
+	if err = c.consume(context.Background(), &Message{}); err != nil {
+		t.Fatal("Failed to consume without metrics:", err.Error())
+	}
+}
@@ -0,0 +1,57 @@
+// (c) 2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package stream
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/ava-labs/ortelius/services/db"
+)
+
+// DefaultRetryPolicy is used by consumers that aren't given a RetryPolicy
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   100 * time.Millisecond,
+	Jitter:      100 * time.Millisecond,
+}
+
+// RetryPolicy controls how many times a consumer attempts to handle a message
+// that failed because of a transient DB error. The delay before each retry
+// doubles from BaseDelay and is increased by a random duration of up to
+// Jitter. Errors that aren't transient are never retried, and a MaxAttempts of
+// 1 or less disables retries.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	Jitter      time.Duration
+}
+
+// Do calls fn until it succeeds, fails with an error that isn't retryable, or
+// has been attempted MaxAttempts times, and returns its last error
+func (p RetryPolicy) Do(ctx context.Context, fn func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || attempt >= p.MaxAttempts || !db.ErrIsRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-time.After(p.delay(attempt)):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// delay returns the time to wait after the given attempt failed
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.BaseDelay << uint(attempt-1)
+	if p.Jitter > 0 {
+		d += time.Duration(rand.Int63n(int64(p.Jitter)))
+	}
+	return d
+}
@@ -0,0 +1,76 @@
+// (c) 2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/palantir/stacktrace"
+
+	"github.com/ava-labs/ortelius/services"
+)
+
+// flakyDBConsumer fails with err the given number of times before succeeding,
+// like a writer whose DB transaction fails
+type flakyDBConsumer struct {
+	err      error
+	failures int
+	attempts int
+}
+
+func (*flakyDBConsumer) Name() string                    { return "flaky" }
+func (*flakyDBConsumer) Bootstrap(context.Context) error { return nil }
+func (c *flakyDBConsumer) Consume(context.Context, services.Consumable) error {
+	c.attempts++
+	if c.attempts <= c.failures {
+		return stacktrace.Propagate(c.err, "Failed to insert tx")
+	}
+	return nil
+}
+
+func TestConsumerRetries(t *testing.T) {
+	var (
+		deadlock  = &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+		duplicate = &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}
+		policy    = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, Jitter: time.Millisecond}
+	)
+
+	for _, test := range []struct {
+		name             string
+		err              error
+		failures         int
+		policy           RetryPolicy
+		expectedAttempts int
+		expectErr        bool
+	}{
+		{"succeeds", deadlock, 0, policy, 1, false},
+		{"transient failures", deadlock, 2, policy, 3, false},
+		{"too many transient failures", deadlock, 5, policy, 3, true},
+		{"permanent failure", duplicate, 5, policy, 1, true},
+		{"retries disabled", deadlock, 1, RetryPolicy{}, 1, true},
+	} {
+		serviceConsumer := &flakyDBConsumer{err: test.err, failures: test.failures}
+		c := &consumer{consumer: serviceConsumer, retry: test.policy}
+
+		err := c.consume(context.Background(), &Message{})
+		if (err != nil) != test.expectErr {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if serviceConsumer.attempts != test.expectedAttempts {
+			t.Fatalf("%s: incorrect number of attempts: %d", test.name, serviceConsumer.attempts)
+		}
+	}
+
+	// Retries stop once the context is done
+	ctx, cancelFn := context.WithCancel(context.Background())
+	cancelFn()
+	serviceConsumer := &flakyDBConsumer{err: deadlock, failures: 5}
+	c := &consumer{consumer: serviceConsumer, retry: RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour}}
+	if err := c.consume(ctx, &Message{}); err == nil || serviceConsumer.attempts != 1 {
+		t.Fatal("Expected a single attempt with a canceled context:", serviceConsumer.attempts)
+	}
+}
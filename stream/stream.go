@@ -30,6 +30,7 @@ type Message struct {
 	chainID   string
 	body      []byte
 	timestamp int64
+	offset    int64
 }
 
 func (m *Message) ID() string       { return m.id }
@@ -37,6 +38,10 @@ func (m *Message) ChainID() string  { return m.chainID }
 func (m *Message) Body() []byte     { return m.body }
 func (m *Message) Timestamp() int64 { return m.timestamp }
 
+// Height is the Message's position in its chain's stream, starting at 1. The
+// stream holds the chain's accepted items in the order they were accepted.
+func (m *Message) Height() uint64 { return uint64(m.offset) + 1 }
+
 func getSocketName(root string, networkID uint32, chainID string, eventType EventType) string {
 	return path.Join(root, fmt.Sprintf("%d-%s-%s", networkID, chainID, eventType))
 }